@@ -0,0 +1,780 @@
+package tronwallet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrABIData is returned when ABI-encoded data is malformed or does not
+// match the expected types.
+var ErrABIData = errors.New("invalid ABI data")
+
+// ABIParam is an input or output of an ABI entry. Components lists the
+// fields of tuple types; java-tron does not store them, so ABIs read back
+// from a node cannot encode tuples.
+type ABIParam struct {
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`
+	Indexed    bool       `json:"indexed,omitempty"`
+	Components []ABIParam `json:"components,omitempty"`
+}
+
+// ABIEntry is one function, event, constructor, fallback, receive or error
+// of a contract ABI. The JSON tags follow the Solidity compiler's ABI JSON,
+// so its output can be decoded with encoding/json. Type and StateMutability
+// use the compiler's lower-case names, such as "function" and "view".
+type ABIEntry struct {
+	Type            string     `json:"type"`
+	Name            string     `json:"name,omitempty"`
+	Inputs          []ABIParam `json:"inputs,omitempty"`
+	Outputs         []ABIParam `json:"outputs,omitempty"`
+	Anonymous       bool       `json:"anonymous,omitempty"`
+	Constant        bool       `json:"constant,omitempty"`
+	Payable         bool       `json:"payable,omitempty"`
+	StateMutability string     `json:"stateMutability,omitempty"`
+}
+
+// abiEntryTypes and abiStateMutabilities list the Solidity names of
+// java-tron's SmartContract.ABI.Entry enums, indexed by enum value.
+var (
+	abiEntryTypes        = []string{"", "constructor", "function", "event", "fallback", "receive", "error"}
+	abiStateMutabilities = []string{"", "pure", "view", "nonpayable", "payable"}
+)
+
+// abiEnum returns the enum value of name in names; the empty name is 0.
+func abiEnum(names []string, what, name string) (int64, error) {
+	for i, n := range names {
+		if strings.EqualFold(n, name) {
+			return int64(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown ABI %s %q", what, name)
+}
+
+// abiEnumName returns the Solidity name of an enum value, or the empty
+// string for values java-tron does not define.
+func abiEnumName(names []string, v int64) string {
+	if v < 0 || v >= int64(len(names)) {
+		return ""
+	}
+	return names[v]
+}
+
+// abiEntryTypeName and abiStateMutabilityName render the enums as
+// java-tron's JSON does.
+func abiEntryTypeName(v int64) string {
+	if name := abiEnumName(abiEntryTypes, v); name != "" {
+		return strings.ToUpper(name[:1]) + name[1:]
+	}
+	if v == 0 {
+		return "UnknownEntryType"
+	}
+	return fmt.Sprintf("EntryType(%d)", v)
+}
+
+func abiStateMutabilityName(v int64) string {
+	if name := abiEnumName(abiStateMutabilities, v); name != "" {
+		return strings.ToUpper(name[:1]) + name[1:]
+	}
+	if v == 0 {
+		return "UnknownMutabilityType"
+	}
+	return fmt.Sprintf("StateMutabilityType(%d)", v)
+}
+
+// marshalABI encodes entries as a SmartContract.ABI message.
+func marshalABI(entries []ABIEntry) ([]byte, error) {
+	var w protoWriter
+	for i, e := range entries {
+		typ, err := abiEnum(abiEntryTypes, "entry type", e.Type)
+		if err != nil {
+			return nil, fmt.Errorf("ABI entry %d: %w", i, err)
+		}
+		mutability, err := abiEnum(abiStateMutabilities, "state mutability", e.StateMutability)
+		if err != nil {
+			return nil, fmt.Errorf("ABI entry %d: %w", i, err)
+		}
+		var ew protoWriter
+		ew.bool(1, e.Anonymous)
+		ew.bool(2, e.Constant)
+		ew.string(3, e.Name)
+		for _, p := range e.Inputs {
+			ew.message(4, marshalABIParam(p))
+		}
+		for _, p := range e.Outputs {
+			ew.message(5, marshalABIParam(p))
+		}
+		ew.int64(6, typ)
+		ew.bool(7, e.Payable)
+		ew.int64(8, mutability)
+		w.message(1, ew.buf)
+	}
+	return w.buf, nil
+}
+
+func marshalABIParam(p ABIParam) []byte {
+	var w protoWriter
+	w.bool(1, p.Indexed)
+	w.string(2, p.Name)
+	w.string(3, p.Type)
+	return w.buf
+}
+
+// parseABI decodes a SmartContract.ABI message.
+func parseABI(b []byte) ([]ABIEntry, error) {
+	fields, err := parseProto(b)
+	if err != nil {
+		return nil, err
+	}
+	var entries []ABIEntry
+	for _, f := range fields {
+		if f.Num != 1 {
+			continue
+		}
+		e, err := parseABIEntry(f)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *e)
+	}
+	return entries, nil
+}
+
+func parseABIEntry(f protoField) (*ABIEntry, error) {
+	b, err := f.bytes()
+	if err != nil {
+		return nil, err
+	}
+	fields, err := parseProto(b)
+	if err != nil {
+		return nil, err
+	}
+	e := &ABIEntry{}
+	for _, f := range fields {
+		var v uint64
+		var p *ABIParam
+		switch f.Num {
+		case 1:
+			v, err = f.uint64()
+			e.Anonymous = v != 0
+		case 2:
+			v, err = f.uint64()
+			e.Constant = v != 0
+		case 3:
+			e.Name, err = f.string()
+		case 4:
+			if p, err = parseABIParam(f); err == nil {
+				e.Inputs = append(e.Inputs, *p)
+			}
+		case 5:
+			if p, err = parseABIParam(f); err == nil {
+				e.Outputs = append(e.Outputs, *p)
+			}
+		case 6:
+			v, err = f.uint64()
+			e.Type = abiEnumName(abiEntryTypes, int64(v))
+		case 7:
+			v, err = f.uint64()
+			e.Payable = v != 0
+		case 8:
+			v, err = f.uint64()
+			e.StateMutability = abiEnumName(abiStateMutabilities, int64(v))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+func parseABIParam(f protoField) (*ABIParam, error) {
+	b, err := f.bytes()
+	if err != nil {
+		return nil, err
+	}
+	fields, err := parseProto(b)
+	if err != nil {
+		return nil, err
+	}
+	p := &ABIParam{}
+	for _, f := range fields {
+		var v uint64
+		switch f.Num {
+		case 1:
+			v, err = f.uint64()
+			p.Indexed = v != 0
+		case 2:
+			p.Name, err = f.string()
+		case 3:
+			p.Type, err = f.string()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// ParseABI reads a contract ABI in any of the JSON forms in use: the
+// Solidity compiler's array of entries, the {"entrys": [...]} message of
+// TRON nodes, or a /wallet/getcontract response holding it under "abi".
+// Node enum names such as "Function" and "View" are converted to the
+// compiler's lower-case names, and entries without a type are functions.
+func ParseABI(data []byte) ([]ABIEntry, error) {
+	var entries []ABIEntry
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var node struct {
+			Entrys []ABIEntry `json:"entrys"`
+			ABI    *struct {
+				Entrys []ABIEntry `json:"entrys"`
+			} `json:"abi"`
+		}
+		if err := json.Unmarshal(trimmed, &node); err != nil {
+			return nil, err
+		}
+		entries = node.Entrys
+		if node.ABI != nil {
+			entries = node.ABI.Entrys
+		}
+	} else if err := json.Unmarshal(trimmed, &entries); err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		e := &entries[i]
+		e.Type = strings.ToLower(e.Type)
+		if e.Type == "" {
+			e.Type = "function"
+		}
+		e.StateMutability = strings.ToLower(e.StateMutability)
+		if _, err := abiEnum(abiEntryTypes, "entry type", e.Type); err != nil {
+			return nil, fmt.Errorf("ABI entry %d: %w", i, err)
+		}
+		if _, err := abiEnum(abiStateMutabilities, "state mutability", e.StateMutability); err != nil {
+			return nil, fmt.Errorf("ABI entry %d: %w", i, err)
+		}
+		for _, params := range [][]ABIParam{e.Inputs, e.Outputs} {
+			if _, err := parseABITypes(params); err != nil {
+				return nil, fmt.Errorf("ABI entry %d (%s): %w", i, e.Name, err)
+			}
+		}
+	}
+	return entries, nil
+}
+
+// FindABIFunction returns the function called name in entries. Overloaded
+// functions must be given by signature, such as "transfer(address,uint256)".
+func FindABIFunction(entries []ABIEntry, name string) (*ABIEntry, error) {
+	var found *ABIEntry
+	for i := range entries {
+		e := &entries[i]
+		if e.Type != "function" || (e.Name != name && e.Signature() != name) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("function %s is overloaded; use its signature", name)
+		}
+		found = e
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no function %s in ABI", name)
+	}
+	return found, nil
+}
+
+// Signature returns the canonical signature of the entry, such as
+// "transfer(address,uint256)", which its selector and event topic hash.
+// Tuples are written as their component types in parentheses.
+func (e ABIEntry) Signature() string {
+	types := make([]string, len(e.Inputs))
+	for i, p := range e.Inputs {
+		if t, err := parseABIType(p.Type, p.Components); err == nil {
+			types[i] = t.name
+		} else {
+			types[i] = p.Type
+		}
+	}
+	return e.Name + "(" + strings.Join(types, ",") + ")"
+}
+
+// Selector returns the 4-byte function selector of the entry.
+func (e ABIEntry) Selector() []byte {
+	return abiSelector(e.Signature())
+}
+
+// EncodeCall returns the calldata of a call of the function with args: its
+// selector followed by the arguments encoded as by EncodeABI.
+func (e ABIEntry) EncodeCall(args ...any) ([]byte, error) {
+	data, err := EncodeABI(e.Inputs, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", e.Name, err)
+	}
+	return append(e.Selector(), data...), nil
+}
+
+// DecodeCall decodes the arguments of calldata for the function, checking
+// its selector.
+func (e ABIEntry) DecodeCall(data []byte) ([]any, error) {
+	if len(data) < 4 || !bytes.Equal(data[:4], e.Selector()) {
+		return nil, fmt.Errorf("%w: calldata is not a call of %s", ErrABIData, e.Signature())
+	}
+	return DecodeABI(e.Inputs, data[4:])
+}
+
+// DecodeOutput decodes the return data of the function, such as the
+// constant_result of /wallet/triggerconstantcontract.
+func (e ABIEntry) DecodeOutput(data []byte) ([]any, error) {
+	return DecodeABI(e.Outputs, data)
+}
+
+// EncodeABI encodes values as the Solidity ABI tuple of params, as used for
+// call arguments and constructor arguments. Values map to types as follows:
+//
+//   - intN, uintN and trcToken: *big.Int, any Go integer, or a decimal
+//     string
+//   - address: a TRON address in Base58 or hex; contracts see the 20-byte
+//     account hash
+//   - bool: bool; string: string; bytes and bytesN: []byte
+//   - arrays: a slice or array of element values
+//   - tuples: []any in component order, or map[string]any by name
+func EncodeABI(params []ABIParam, values ...any) ([]byte, error) {
+	types, err := parseABITypes(params)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != len(types) {
+		return nil, fmt.Errorf("got %d values for %d parameters", len(values), len(types))
+	}
+	return encodeABITuple(types, values)
+}
+
+// DecodeABI decodes ABI-encoded data holding the tuple of params. Integers
+// decode to *big.Int, addresses to Base58 strings, bytes and bytesN to
+// []byte, and arrays and tuples to []any. Encodings a compiler would not
+// produce, such as dirty padding, are rejected with ErrABIData.
+func DecodeABI(params []ABIParam, data []byte) ([]any, error) {
+	types, err := parseABITypes(params)
+	if err != nil {
+		return nil, err
+	}
+	return decodeABITuple(types, data)
+}
+
+// maxABIHeadSize bounds the encoded size of static types, so that absurd
+// fixed array lengths in an ABI cannot overflow size arithmetic.
+const maxABIHeadSize = 1 << 24
+
+type abiKind int
+
+const (
+	abiUint abiKind = iota
+	abiInt
+	abiAddress
+	abiBool
+	abiFixedBytes
+	abiBytes
+	abiString
+	abiArray
+	abiSlice
+	abiTuple
+)
+
+// abiType is a parsed Solidity type. size is the bit size of integers, the
+// length of bytesN and the length of fixed arrays. name is the canonical
+// type used in signatures.
+type abiType struct {
+	kind       abiKind
+	size       int
+	elem       *abiType
+	components []*abiType
+	fields     []string
+	name       string
+}
+
+func parseABITypes(params []ABIParam) ([]*abiType, error) {
+	types := make([]*abiType, len(params))
+	for i, p := range params {
+		t, err := parseABIType(p.Type, p.Components)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %d (%s): %w", i, p.Name, err)
+		}
+		types[i] = t
+	}
+	return types, nil
+}
+
+// parseABIType parses a type such as "uint256", "bytes32[]" or "tuple[2]";
+// components are the fields of the innermost tuple.
+func parseABIType(typ string, components []ABIParam) (*abiType, error) {
+	if i := strings.LastIndexByte(typ, '['); i > 0 && strings.HasSuffix(typ, "]") {
+		elem, err := parseABIType(typ[:i], components)
+		if err != nil {
+			return nil, err
+		}
+		n := typ[i+1 : len(typ)-1]
+		if n == "" {
+			return &abiType{kind: abiSlice, elem: elem, name: elem.name + "[]"}, nil
+		}
+		k, err := strconv.Atoi(n)
+		if err != nil || k <= 0 || elem.headSize() > maxABIHeadSize/k {
+			return nil, fmt.Errorf("invalid array length in %q", typ)
+		}
+		return &abiType{kind: abiArray, size: k, elem: elem, name: elem.name + "[" + n + "]"}, nil
+	}
+	switch typ {
+	case "address":
+		return &abiType{kind: abiAddress, name: typ}, nil
+	case "bool":
+		return &abiType{kind: abiBool, name: typ}, nil
+	case "string":
+		return &abiType{kind: abiString, name: typ}, nil
+	case "bytes":
+		return &abiType{kind: abiBytes, name: typ}, nil
+	case "trcToken":
+		// TRC10 token IDs are encoded as uint256 but keep their own name
+		// in signatures.
+		return &abiType{kind: abiUint, size: 256, name: typ}, nil
+	case "tuple":
+		if len(components) == 0 {
+			return nil, errors.New("tuple type without components")
+		}
+		t := &abiType{kind: abiTuple}
+		names := make([]string, len(components))
+		for i, c := range components {
+			ct, err := parseABIType(c.Type, c.Components)
+			if err != nil {
+				return nil, err
+			}
+			t.components = append(t.components, ct)
+			t.fields = append(t.fields, c.Name)
+			names[i] = ct.name
+		}
+		t.name = "(" + strings.Join(names, ",") + ")"
+		return t, nil
+	}
+	for _, prefix := range []string{"uint", "int"} {
+		if rest, ok := strings.CutPrefix(typ, prefix); ok {
+			bits := 256
+			if rest != "" {
+				var err error
+				if bits, err = strconv.Atoi(rest); err != nil || bits < 8 || bits > 256 || bits%8 != 0 {
+					return nil, fmt.Errorf("invalid integer type %q", typ)
+				}
+			}
+			kind := abiUint
+			if prefix == "int" {
+				kind = abiInt
+			}
+			return &abiType{kind: kind, size: bits, name: prefix + strconv.Itoa(bits)}, nil
+		}
+	}
+	if rest, ok := strings.CutPrefix(typ, "bytes"); ok {
+		if n, err := strconv.Atoi(rest); err == nil && n >= 1 && n <= 32 {
+			return &abiType{kind: abiFixedBytes, size: n, name: typ}, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported ABI type %q", typ)
+}
+
+// dynamic reports whether values of t are encoded out of line.
+func (t *abiType) dynamic() bool {
+	switch t.kind {
+	case abiBytes, abiString, abiSlice:
+		return true
+	case abiArray:
+		return t.elem.dynamic()
+	case abiTuple:
+		for _, c := range t.components {
+			if c.dynamic() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// headSize is the number of bytes t takes in the head of a tuple.
+func (t *abiType) headSize() int {
+	if t.dynamic() {
+		return 32
+	}
+	switch t.kind {
+	case abiArray:
+		return t.size * t.elem.headSize()
+	case abiTuple:
+		n := 0
+		for _, c := range t.components {
+			n += c.headSize()
+		}
+		return n
+	}
+	return 32
+}
+
+func encodeABITuple(types []*abiType, values []any) ([]byte, error) {
+	headSize := 0
+	for _, t := range types {
+		headSize += t.headSize()
+	}
+	var head, tail []byte
+	for i, t := range types {
+		enc, err := t.encode(values[i])
+		if err != nil {
+			return nil, err
+		}
+		if t.dynamic() {
+			head = append(head, abiUintWord(uint64(headSize+len(tail)))...)
+			tail = append(tail, enc...)
+		} else {
+			head = append(head, enc...)
+		}
+	}
+	return append(head, tail...), nil
+}
+
+func (t *abiType) encode(v any) ([]byte, error) {
+	switch t.kind {
+	case abiUint, abiInt:
+		n, err := abiInteger(v)
+		if err != nil {
+			return nil, err
+		}
+		return encodeIntWord(n, t.size, t.kind == abiInt)
+	case abiAddress:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("address value must be a string, got %T", v)
+		}
+		return abiAddressWord(s)
+	case abiBool:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("bool value must be a bool, got %T", v)
+		}
+		if b {
+			return abiUintWord(1), nil
+		}
+		return abiUintWord(0), nil
+	case abiFixedBytes:
+		b, ok := v.([]byte)
+		if !ok || len(b) != t.size {
+			return nil, fmt.Errorf("%s value must be %d bytes", t.name, t.size)
+		}
+		return abiPadRight(b), nil
+	case abiBytes, abiString:
+		var b []byte
+		switch x := v.(type) {
+		case []byte:
+			b = x
+		case string:
+			b = []byte(x)
+		default:
+			return nil, fmt.Errorf("%s value must be a string or []byte, got %T", t.name, v)
+		}
+		return append(abiUintWord(uint64(len(b))), abiPadRight(b)...), nil
+	case abiArray, abiSlice:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return nil, fmt.Errorf("%s value must be a slice, got %T", t.name, v)
+		}
+		if t.kind == abiArray && rv.Len() != t.size {
+			return nil, fmt.Errorf("%s value has %d elements", t.name, rv.Len())
+		}
+		types := make([]*abiType, rv.Len())
+		values := make([]any, rv.Len())
+		for i := range values {
+			types[i], values[i] = t.elem, rv.Index(i).Interface()
+		}
+		enc, err := encodeABITuple(types, values)
+		if err != nil {
+			return nil, fmt.Errorf("element of %s: %w", t.name, err)
+		}
+		if t.kind == abiSlice {
+			enc = append(abiUintWord(uint64(len(values))), enc...)
+		}
+		return enc, nil
+	case abiTuple:
+		values, err := t.tupleValues(v)
+		if err != nil {
+			return nil, err
+		}
+		return encodeABITuple(t.components, values)
+	}
+	return nil, fmt.Errorf("unsupported ABI type %s", t.name)
+}
+
+// tupleValues returns the component values of a tuple given as a slice or
+// as a map keyed by component name.
+func (t *abiType) tupleValues(v any) ([]any, error) {
+	if m, ok := v.(map[string]any); ok {
+		values := make([]any, len(t.fields))
+		for i, f := range t.fields {
+			if values[i], ok = m[f]; !ok {
+				return nil, fmt.Errorf("tuple value has no field %q", f)
+			}
+		}
+		return values, nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("tuple value must be a slice or map, got %T", v)
+	}
+	if rv.Len() != len(t.components) {
+		return nil, fmt.Errorf("tuple %s value has %d fields", t.name, rv.Len())
+	}
+	values := make([]any, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+	return values, nil
+}
+
+// abiInteger converts an integer argument to a big.Int.
+func abiInteger(v any) (*big.Int, error) {
+	switch x := v.(type) {
+	case *big.Int:
+		if x != nil {
+			return x, nil
+		}
+	case string:
+		if n, ok := new(big.Int).SetString(x, 10); ok {
+			return n, nil
+		}
+	default:
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return big.NewInt(rv.Int()), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return new(big.Int).SetUint64(rv.Uint()), nil
+		}
+	}
+	return nil, fmt.Errorf("invalid integer %v", v)
+}
+
+func abiUintWord(n uint64) []byte {
+	return new(big.Int).SetUint64(n).FillBytes(make([]byte, 32))
+}
+
+// abiPadRight zero-pads b to a multiple of 32 bytes.
+func abiPadRight(b []byte) []byte {
+	return append(append([]byte(nil), b...), make([]byte, (32-len(b)%32)%32)...)
+}
+
+func decodeABITuple(types []*abiType, data []byte) ([]any, error) {
+	values := make([]any, len(types))
+	pos := 0
+	for i, t := range types {
+		size := t.headSize()
+		if pos+size > len(data) {
+			return nil, fmt.Errorf("%w: data too short for %s", ErrABIData, t.name)
+		}
+		field := data[pos : pos+size]
+		if t.dynamic() {
+			off, err := abiOffset(field)
+			if err != nil {
+				return nil, err
+			}
+			if off > len(data) {
+				return nil, fmt.Errorf("%w: offset of %s out of range", ErrABIData, t.name)
+			}
+			field = data[off:]
+		}
+		v, err := t.decode(field)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+		pos += size
+	}
+	return values, nil
+}
+
+// abiOffset reads an offset or length word, which must fit in an int.
+func abiOffset(word []byte) (int, error) {
+	n := new(big.Int).SetBytes(word)
+	if !n.IsInt64() || n.Int64() > math.MaxInt32 {
+		return 0, fmt.Errorf("%w: offset or length too large", ErrABIData)
+	}
+	return int(n.Int64()), nil
+}
+
+func (t *abiType) decode(data []byte) (any, error) {
+	if t.kind < abiArray && len(data) < 32 {
+		return nil, fmt.Errorf("%w: data too short for %s", ErrABIData, t.name)
+	}
+	switch t.kind {
+	case abiUint, abiInt:
+		n := new(big.Int).SetBytes(data[:32])
+		if t.kind == abiInt && data[0]&0x80 != 0 {
+			n.Sub(n, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+		if _, err := encodeIntWord(n, t.size, t.kind == abiInt); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrABIData, err)
+		}
+		return n, nil
+	case abiAddress:
+		if !bytes.Equal(data[:12], make([]byte, 12)) {
+			return nil, fmt.Errorf("%w: address has dirty padding", ErrABIData)
+		}
+		return encodeAddress(append([]byte{addressPrefix}, data[12:32]...)), nil
+	case abiBool:
+		if !bytes.Equal(data[:31], make([]byte, 31)) || data[31] > 1 {
+			return nil, fmt.Errorf("%w: invalid bool", ErrABIData)
+		}
+		return data[31] == 1, nil
+	case abiFixedBytes:
+		if !bytes.Equal(data[t.size:32], make([]byte, 32-t.size)) {
+			return nil, fmt.Errorf("%w: %s has dirty padding", ErrABIData, t.name)
+		}
+		return append([]byte(nil), data[:t.size]...), nil
+	case abiBytes, abiString:
+		n, err := abiOffset(data[:32])
+		if err != nil {
+			return nil, err
+		}
+		padded := (n + 31) / 32 * 32
+		if padded > len(data)-32 {
+			return nil, fmt.Errorf("%w: %s length out of range", ErrABIData, t.name)
+		}
+		if !bytes.Equal(data[32+n:32+padded], make([]byte, padded-n)) {
+			return nil, fmt.Errorf("%w: %s has dirty padding", ErrABIData, t.name)
+		}
+		b := append([]byte(nil), data[32:32+n]...)
+		if t.kind == abiString {
+			return string(b), nil
+		}
+		return b, nil
+	case abiArray, abiSlice:
+		n := t.size
+		if t.kind == abiSlice {
+			if len(data) < 32 {
+				return nil, fmt.Errorf("%w: data too short for %s", ErrABIData, t.name)
+			}
+			var err error
+			if n, err = abiOffset(data[:32]); err != nil {
+				return nil, err
+			}
+			data = data[32:]
+		}
+		// Every element takes at least one word, which bounds n before
+		// anything is allocated.
+		if n > len(data)/32 {
+			return nil, fmt.Errorf("%w: %s length out of range", ErrABIData, t.name)
+		}
+		types := make([]*abiType, n)
+		for i := range types {
+			types[i] = t.elem
+		}
+		return decodeABITuple(types, data)
+	case abiTuple:
+		return decodeABITuple(t.components, data)
+	}
+	return nil, fmt.Errorf("unsupported ABI type %s", t.name)
+}
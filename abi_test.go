@@ -0,0 +1,293 @@
+package tronwallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestABIProto_RoundTrip(t *testing.T) {
+	// Solidity compiler output decodes directly into ABIEntry.
+	solc := `[
+		{"inputs":[{"internalType":"uint256","name":"supply","type":"uint256"}],"stateMutability":"nonpayable","type":"constructor"},
+		{"inputs":[{"internalType":"address","name":"owner","type":"address"}],"name":"balanceOf","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+		{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"from","type":"address"},{"indexed":true,"internalType":"address","name":"to","type":"address"},{"indexed":false,"internalType":"uint256","name":"value","type":"uint256"}],"name":"Transfer","type":"event"}
+	]`
+	var entries []ABIEntry
+	if err := json.Unmarshal([]byte(solc), &entries); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if !reflect.DeepEqual(entries, testABI) {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	b, err := marshalABI(entries)
+	if err != nil {
+		t.Fatalf("marshalABI error: %v", err)
+	}
+	back, err := parseABI(b)
+	if err != nil || !reflect.DeepEqual(back, entries) {
+		t.Fatalf("parseABI = %+v, %v", back, err)
+	}
+	if _, err := parseABI([]byte{0x0a, 0x02, 0x18}); err == nil {
+		t.Fatalf("expected error for a truncated entry")
+	}
+	if _, err := parseABI([]byte{0x0a, 0x02, 0x22, 0x00, 0x0a, 0x01}); err == nil {
+		t.Fatalf("expected error for a truncated message")
+	}
+}
+
+func TestABIEnumNames(t *testing.T) {
+	cases := []struct {
+		got, want string
+	}{
+		{abiEntryTypeName(2), "Function"},
+		{abiEntryTypeName(0), "UnknownEntryType"},
+		{abiEntryTypeName(9), "EntryType(9)"},
+		{abiStateMutabilityName(4), "Payable"},
+		{abiStateMutabilityName(0), "UnknownMutabilityType"},
+		{abiStateMutabilityName(-1), "StateMutabilityType(-1)"},
+	}
+	for _, c := range cases {
+		if c.got != c.want {
+			t.Fatalf("got %q want %q", c.got, c.want)
+		}
+	}
+	if v, err := abiEnum(abiEntryTypes, "entry type", "Receive"); err != nil || v != 5 {
+		t.Fatalf("abiEnum = %d, %v", v, err)
+	}
+}
+
+// abiWords concatenates 32-byte words given in hex: plain words are padded
+// on the left and words starting with "r" on the right.
+func abiWords(t *testing.T, words ...string) []byte {
+	t.Helper()
+	var out []byte
+	for _, w := range words {
+		if rest, ok := strings.CutPrefix(w, "r"); ok {
+			w = rest + strings.Repeat("0", 64-len(rest))
+		} else {
+			w = strings.Repeat("0", 64-len(w)) + w
+		}
+		b, err := hex.DecodeString(w)
+		if err != nil {
+			t.Fatalf("bad word %q", w)
+		}
+		out = append(out, b...)
+	}
+	return out
+}
+
+func TestEncodeABI_SolidityVectors(t *testing.T) {
+	p := func(types ...string) []ABIParam {
+		var params []ABIParam
+		for _, typ := range types {
+			params = append(params, ABIParam{Type: typ})
+		}
+		return params
+	}
+	cases := []struct {
+		name     string
+		inputs   []ABIParam
+		args     []any
+		selector string
+		want     []byte
+	}{
+		{"baz", p("uint32", "bool"), []any{69, true}, "cdcd77c0", abiWords(t, "45", "1")},
+		{"bar", p("bytes3[2]"), []any{[][]byte{[]byte("abc"), []byte("def")}}, "fce353f6", abiWords(t, "r616263", "r646566")},
+		{"sam", p("bytes", "bool", "uint256[]"), []any{[]byte("dave"), true, []int{1, 2, 3}}, "a5643bf2",
+			abiWords(t, "60", "1", "a0", "4", "r64617665", "3", "1", "2", "3")},
+		{"f", p("uint", "uint32[]", "bytes10", "bytes"), []any{big.NewInt(0x123), []uint32{0x456, 0x789}, []byte("1234567890"), "Hello, world!"}, "8be65246",
+			abiWords(t, "123", "80", "r31323334353637383930", "e0", "2", "456", "789", "d", "r48656c6c6f2c20776f726c6421")},
+		{"g", p("uint256[][]", "string[]"), []any{[][]int{{1, 2}, {3}}, []string{"one", "two", "three"}}, "2289b18c",
+			abiWords(t, "40", "140", "2", "40", "a0", "2", "1", "2", "1", "3", "3", "60", "a0", "e0", "3", "r6f6e65", "3", "r74776f", "5", "r7468726565")},
+	}
+	for _, c := range cases {
+		e := ABIEntry{Type: "function", Name: c.name, Inputs: c.inputs}
+		data, err := e.EncodeCall(c.args...)
+		if err != nil {
+			t.Fatalf("%s: EncodeCall error: %v", c.name, err)
+		}
+		if got := hex.EncodeToString(data[:4]); got != c.selector {
+			t.Fatalf("%s: selector %s want %s (%s)", c.name, got, c.selector, e.Signature())
+		}
+		if !bytes.Equal(data[4:], c.want) {
+			t.Fatalf("%s: encoding mismatch:\n got %x\nwant %x", c.name, data[4:], c.want)
+		}
+		values, err := e.DecodeCall(data)
+		if err != nil {
+			t.Fatalf("%s: DecodeCall error: %v", c.name, err)
+		}
+		again, err := e.EncodeCall(values...)
+		if err != nil || !bytes.Equal(again, data) {
+			t.Fatalf("%s: re-encoding decoded values failed: %v", c.name, err)
+		}
+	}
+}
+
+func TestEncodeABI_TuplesAndAddresses(t *testing.T) {
+	e := ABIEntry{Type: "function", Name: "f", Inputs: []ABIParam{
+		{Name: "order", Type: "tuple", Components: []ABIParam{{Name: "id", Type: "uint256"}, {Name: "note", Type: "string"}}},
+		{Name: "to", Type: "address"},
+		{Name: "delta", Type: "int8"},
+	}}
+	if e.Signature() != "f((uint256,string),address,int8)" || hex.EncodeToString(e.Selector()) != "8b1e0804" {
+		t.Fatalf("unexpected signature %s", e.Signature())
+	}
+	want := abiWords(t, "60", "b6e708a39781c96bd399c7657780ff9fe9f052a8", strings.Repeat("f", 64), "2a", "40", "2", "r6869")
+	for _, order := range []any{
+		[]any{42, "hi"},
+		map[string]any{"id": big.NewInt(42), "note": "hi"},
+	} {
+		// Base58 and hex addresses encode the same 20-byte account hash.
+		for _, to := range []string{"TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", "41b6e708a39781c96bd399c7657780ff9fe9f052a8"} {
+			data, err := EncodeABI(e.Inputs, order, to, -1)
+			if err != nil || !bytes.Equal(data, want) {
+				t.Fatalf("EncodeABI = %x, %v\nwant %x", data, err, want)
+			}
+		}
+	}
+	values, err := DecodeABI(e.Inputs, want)
+	if err != nil {
+		t.Fatalf("DecodeABI error: %v", err)
+	}
+	if !reflect.DeepEqual(values, []any{[]any{big.NewInt(42), "hi"}, "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", big.NewInt(-1)}) {
+		t.Fatalf("unexpected values %#v", values)
+	}
+
+	// trcToken is a uint256 with its own name in signatures.
+	deposit := ABIEntry{Type: "function", Name: "deposit", Inputs: []ABIParam{{Type: "trcToken"}, {Type: "address"}}}
+	data, err := deposit.EncodeCall("1002000", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK")
+	if err != nil || hex.EncodeToString(data[:4]) != "124ef4c6" || !bytes.Equal(data[4:36], abiWords(t, "f4a10")) {
+		t.Fatalf("deposit calldata %x, %v", data, err)
+	}
+}
+
+func TestEncodeABI_Rejects(t *testing.T) {
+	cases := []struct {
+		typ   string
+		value any
+	}{
+		{"uint8", 256},
+		{"uint8", -1},
+		{"int8", 128},
+		{"uint256", "0x10"},
+		{"uint256", (*big.Int)(nil)},
+		{"address", "TNotAnAddress"},
+		{"address", 1},
+		{"bool", 1},
+		{"bytes4", []byte{1, 2, 3}},
+		{"bytes", 1},
+		{"uint8[2]", []int{1}},
+		{"uint8[]", 1},
+		{"uint8[]", []int{1, 256}},
+		{"fixed128x18", 1},
+		{"uint7", 1},
+		{"bytes33", []byte{}},
+		{"uint[0]", []int{}},
+		{"uint[x]", []int{}},
+		{"tuple", []any{}},
+		{"uint256[65536][65536]", nil},
+	}
+	for _, c := range cases {
+		if _, err := EncodeABI([]ABIParam{{Type: c.typ}}, c.value); err == nil {
+			t.Fatalf("%s %v: expected error", c.typ, c.value)
+		}
+	}
+	tuple := []ABIParam{{Type: "tuple", Components: []ABIParam{{Name: "a", Type: "bool"}}}}
+	for _, v := range []any{map[string]any{"b": true}, []any{true, false}, true, []any{1}} {
+		if _, err := EncodeABI(tuple, v); err == nil {
+			t.Fatalf("tuple %v: expected error", v)
+		}
+	}
+	if _, err := EncodeABI(tuple); err == nil {
+		t.Fatalf("expected error for a missing value")
+	}
+	if _, err := (ABIEntry{Name: "f", Inputs: tuple}).EncodeCall(false); err == nil {
+		t.Fatalf("expected error from EncodeCall")
+	}
+}
+
+func TestDecodeABI_Rejects(t *testing.T) {
+	cases := []struct {
+		typ  string
+		data []byte
+	}{
+		{"uint256", abiWords(t)[:0]},
+		{"uint8", abiWords(t, "100")},
+		{"int8", abiWords(t, "80")},
+		{"address", abiWords(t, "1"+strings.Repeat("0", 40))},
+		{"bool", abiWords(t, "2")},
+		{"bytes2", abiWords(t, "r616263")},
+		{"bytes", abiWords(t, "20", "21", "r61")},
+		{"bytes", abiWords(t, "20", strings.Repeat("f", 64))},
+		{"bytes", abiWords(t, "40")},
+		{"bytes", abiWords(t, "20", "3", "r616263ff")},
+		{"string", abiWords(t, "20", "1", "r6162")},
+		{"string", abiWords(t, "20")},
+		{"uint256[]", abiWords(t, "20", "ffffff")},
+		{"uint256[]", abiWords(t, "20")},
+		{"string[2]", abiWords(t, "20", "40")},
+		{"uint256[2]", abiWords(t, "1")},
+		{"unknown", abiWords(t, "1")},
+	}
+	for _, c := range cases {
+		if _, err := DecodeABI([]ABIParam{{Type: c.typ}}, c.data); err == nil {
+			t.Fatalf("%s %x: expected error", c.typ, c.data)
+		} else if c.typ != "unknown" && !errors.Is(err, ErrABIData) {
+			t.Fatalf("%s: expected ErrABIData, got %v", c.typ, err)
+		}
+	}
+	e := ABIEntry{Type: "function", Name: "balanceOf", Inputs: []ABIParam{{Type: "address"}}, Outputs: []ABIParam{{Type: "uint256"}}}
+	if _, err := e.DecodeCall([]byte{1, 2, 3, 4}); !errors.Is(err, ErrABIData) {
+		t.Fatalf("expected ErrABIData for a foreign selector, got %v", err)
+	}
+	if out, err := e.DecodeOutput(abiWords(t, "3e8")); err != nil || out[0].(*big.Int).Int64() != 1000 {
+		t.Fatalf("DecodeOutput = %v, %v", out, err)
+	}
+}
+
+func TestParseABI(t *testing.T) {
+	node := `{"entrys":[
+		{"inputs":[{"name":"supply","type":"uint256"}],"stateMutability":"Nonpayable","type":"Constructor"},
+		{"constant":true,"inputs":[{"name":"owner","type":"address"}],"name":"balanceOf","outputs":[{"type":"uint256"}],"stateMutability":"View","type":"Function"},
+		{"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"name":"value","type":"uint256"}],"name":"Transfer","type":"Event"}
+	]}`
+	for _, data := range []string{node, `{"bytecode":"6080","abi":` + node + `}`} {
+		entries, err := ParseABI([]byte(data))
+		if err != nil {
+			t.Fatalf("ParseABI error: %v", err)
+		}
+		want := append([]ABIEntry(nil), testABI...)
+		want[1].Constant = true
+		if !reflect.DeepEqual(entries, want) {
+			t.Fatalf("unexpected entries %+v", entries)
+		}
+	}
+	entries, err := ParseABI([]byte(`[{"name":"transfer","inputs":[{"type":"address"},{"type":"uint256"}]},{"name":"transfer","inputs":[{"type":"address"},{"type":"uint256"},{"type":"bytes"}]},{"type":"receive","stateMutability":"payable"}]`))
+	if err != nil || entries[0].Type != "function" {
+		t.Fatalf("ParseABI = %+v, %v", entries, err)
+	}
+	if _, err := FindABIFunction(entries, "transfer"); err == nil {
+		t.Fatalf("expected error for an overloaded name")
+	}
+	f, err := FindABIFunction(entries, "transfer(address,uint256)")
+	if err != nil || !bytes.Equal(f.Selector(), trc20Transfer.Selector()) {
+		t.Fatalf("FindABIFunction = %+v, %v", f, err)
+	}
+	if _, err := FindABIFunction(entries, "approve"); err == nil {
+		t.Fatalf("expected error for a missing function")
+	}
+	for _, bad := range []string{`[`, `{"entrys":1}`, `[{"type":"modifier"}]`, `[{"stateMutability":"mutable"}]`, `[{"inputs":[{"type":"uint7"}]}]`} {
+		if _, err := ParseABI([]byte(bad)); err == nil {
+			t.Fatalf("ParseABI(%s): expected error", bad)
+		}
+	}
+	if e := (ABIEntry{Name: "f", Inputs: []ABIParam{{Type: "uint"}, {Type: "weird"}}}); e.Signature() != "f(uint256,weird)" {
+		t.Fatalf("unexpected signature %s", e.Signature())
+	}
+}
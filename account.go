@@ -0,0 +1,109 @@
+package tronwallet
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// TronDerivationPath returns the BIP44 path used by TronWallet.Derive for the
+// given account index, m/44'/195'/0'/0/index.
+func TronDerivationPath(index uint32) string {
+	return fmt.Sprintf("m/44'/195'/0'/0/%d", index)
+}
+
+// Account is a single TRON key together with the information callers usually
+// need alongside it: the derivation path and index it came from, its public
+// key and its address. Accounts derived from a TronWallet carry their path;
+// imported accounts have an empty path. Account implements Signer.
+type Account struct {
+	path      string
+	index     uint32
+	publicKey *ecdsa.PublicKey
+	address   string
+	priv      *ecdsa.PrivateKey
+}
+
+// Account derives the key at index and returns it as an Account.
+func (w *TronWallet) Account(index uint32) (*Account, error) {
+	priv, err := w.Derive(index)
+	if err != nil {
+		return nil, err
+	}
+	a := NewAccount(priv)
+	a.path = TronDerivationPath(index)
+	a.index = index
+	return a, nil
+}
+
+// NewAccount wraps a private key, such as one returned by TronWallet.Derive
+// or an imported key that has no wallet behind it, as an Account.
+func NewAccount(priv *ecdsa.PrivateKey) *Account {
+	return &Account{
+		publicKey: &priv.PublicKey,
+		address:   TronAddressFromPrivate(priv),
+		priv:      priv,
+	}
+}
+
+// AccountFromHex imports a hex-encoded 32-byte private key (with or without
+// a 0x prefix) as an Account.
+func AccountFromHex(s string) (*Account, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, errors.New("invalid private key hex")
+	}
+	if len(b) != 32 {
+		return nil, errors.New("invalid private key length")
+	}
+	var k secp256k1.ModNScalar
+	if k.SetByteSlice(b) || k.IsZero() {
+		return nil, errors.New("invalid private key")
+	}
+	return NewAccount(secp256k1.NewPrivateKey(&k).ToECDSA()), nil
+}
+
+// Path returns the BIP44 derivation path, or "" for imported keys.
+func (a *Account) Path() string {
+	return a.path
+}
+
+// Index returns the account index within the wallet (0 for imported keys).
+func (a *Account) Index() uint32 {
+	return a.index
+}
+
+// PublicKey returns the secp256k1 public key of the account.
+func (a *Account) PublicKey() *ecdsa.PublicKey {
+	return a.publicKey
+}
+
+// Address returns the Base58-encoded TRON address of the account.
+func (a *Account) Address() string {
+	return a.address
+}
+
+// IsImported reports whether the account was imported from a bare private key
+// rather than derived from a wallet.
+func (a *Account) IsImported() bool {
+	return a.path == ""
+}
+
+// PrivateKey returns the account's private key.
+func (a *Account) PrivateKey() *ecdsa.PrivateKey {
+	return a.priv
+}
+
+// PrivateKeyHex returns the hexadecimal encoding of the account's private key.
+func (a *Account) PrivateKeyHex() string {
+	return PrivateKeyToHex(a.priv)
+}
+
+// SignHash signs a 32-byte hash with the account's key; see SignHash.
+func (a *Account) SignHash(hash []byte) ([]byte, error) {
+	return SignHash(a.priv, hash)
+}
@@ -0,0 +1,85 @@
+package tronwallet
+
+import (
+	"testing"
+
+	secpecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+func TestWalletAccount(t *testing.T) {
+	w, err := RestoreWallet(testMnemonic)
+	if err != nil {
+		t.Fatalf("RestoreWallet error: %v", err)
+	}
+	a, err := w.Account(2)
+	if err != nil {
+		t.Fatalf("Account error: %v", err)
+	}
+	if a.Path() != "m/44'/195'/0'/0/2" || a.Index() != 2 {
+		t.Fatalf("unexpected path/index %q %d", a.Path(), a.Index())
+	}
+	if a.IsImported() {
+		t.Fatalf("derived account reported as imported")
+	}
+	if a.Address() != "TYJPRrdB5APNeRs4R7fYZSwW3TcrTKw2gx" {
+		t.Fatalf("unexpected address %s", a.Address())
+	}
+	priv, _ := w.Derive(2)
+	if a.PrivateKeyHex() != PrivateKeyToHex(priv) || a.PrivateKey().D.Cmp(priv.D) != 0 {
+		t.Fatalf("private key mismatch")
+	}
+	if a.PublicKey().X.Cmp(priv.PublicKey.X) != 0 {
+		t.Fatalf("public key mismatch")
+	}
+}
+
+func TestAccountFromHex(t *testing.T) {
+	a, err := AccountFromHex("0xb5a4cea271ff424d7c31dc12a3e43e401df7a40d7412a15750f3f0b6b5449a28")
+	if err != nil {
+		t.Fatalf("AccountFromHex error: %v", err)
+	}
+	if !a.IsImported() || a.Path() != "" {
+		t.Fatalf("expected imported account")
+	}
+	if a.Address() != "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH" {
+		t.Fatalf("unexpected address %s", a.Address())
+	}
+
+	for _, bad := range []string{
+		"zz",
+		"0102",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", // curve order
+	} {
+		if _, err := AccountFromHex(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestAccountSignHash(t *testing.T) {
+	w, _ := RestoreWallet(testMnemonic)
+	a, _ := w.Account(0)
+	hash := make([]byte, 32)
+	hash[0] = 0x01
+
+	sig, err := a.SignHash(hash)
+	if err != nil {
+		t.Fatalf("SignHash error: %v", err)
+	}
+	if len(sig) != 65 || (sig[64] != 27 && sig[64] != 28) {
+		t.Fatalf("unexpected signature %x", sig)
+	}
+	compact := append([]byte{sig[64]}, sig[:64]...)
+	pub, _, err := secpecdsa.RecoverCompact(compact, hash)
+	if err != nil {
+		t.Fatalf("RecoverCompact error: %v", err)
+	}
+	if TronAddressFromPublic(pub.ToECDSA()) != a.Address() {
+		t.Fatalf("recovered address mismatch")
+	}
+
+	if _, err := a.SignHash(hash[:31]); err == nil {
+		t.Fatalf("expected error for short hash")
+	}
+}
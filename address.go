@@ -1,40 +1,109 @@
 package tronwallet
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
 
 	"github.com/btcsuite/btcutil/base58"
 	"golang.org/x/crypto/sha3"
 )
 
+// AddressLength is the length of a raw TRON address: the 0x41 version byte
+// followed by the 20-byte account hash.
+const AddressLength = 21
+
+// addressPrefix is the version byte of TRON mainnet addresses.
+const addressPrefix = 0x41
+
+// ErrInvalidAddress is returned when a string is not a valid TRON address.
+var ErrInvalidAddress = errors.New("invalid TRON address")
+
 // TronAddressFromPrivate returns the Base58-encoded Tron address for the
 // provided ECDSA private key. The function computes the uncompressed public
 // key, hashes the X||Y bytes with Keccak-256, takes the last 20 bytes, prefixes
 // with the Tron version byte 0x41, appends a 4-byte checksum (double SHA-256),
 // and encodes the result with Base58.
 func TronAddressFromPrivate(priv *ecdsa.PrivateKey) string {
-	pub := pubUncompressed(priv)
+	return TronAddressFromPublic(&priv.PublicKey)
+}
+
+// TronAddressFromPublic returns the Base58-encoded Tron address for the
+// provided ECDSA public key, using the same derivation as
+// TronAddressFromPrivate.
+func TronAddressFromPublic(pubKey *ecdsa.PublicKey) string {
+	pub := pubKeyUncompressed(pubKey)
 
 	h := sha3.NewLegacyKeccak256()
 	h.Write(pub[1:]) // skip 0x04
 	digest := h.Sum(nil)
 
 	raw := append([]byte{0x41}, digest[12:]...)
+	return encodeAddress(raw)
+}
 
+// encodeAddress Base58Check-encodes a raw 21-byte address.
+func encodeAddress(raw []byte) string {
 	sum1 := sha256.Sum256(raw)
 	sum2 := sha256.Sum256(sum1[:])
 
-	full := append(raw, sum2[:4]...)
+	full := append(append([]byte(nil), raw...), sum2[:4]...)
 	return base58.Encode(full)
 }
 
+// EncodeAddress returns the Base58 form of a raw 21-byte TRON address.
+func EncodeAddress(raw []byte) (string, error) {
+	if len(raw) != AddressLength || raw[0] != addressPrefix {
+		return "", ErrInvalidAddress
+	}
+	return encodeAddress(raw), nil
+}
+
+// DecodeAddress parses a TRON address in Base58 form ("T...") or hex form
+// ("41..." with an optional 0x prefix) and returns the raw 21-byte address.
+// The Base58 checksum is verified.
+func DecodeAddress(s string) ([]byte, error) {
+	if h := strings.TrimPrefix(s, "0x"); len(h) == 2*AddressLength {
+		raw, err := hex.DecodeString(h)
+		if err != nil || raw[0] != addressPrefix {
+			return nil, ErrInvalidAddress
+		}
+		return raw, nil
+	}
+	full := base58.Decode(s)
+	if len(full) != AddressLength+4 || full[0] != addressPrefix {
+		return nil, ErrInvalidAddress
+	}
+	raw := full[:AddressLength]
+	sum1 := sha256.Sum256(raw)
+	sum2 := sha256.Sum256(sum1[:])
+	if !bytes.Equal(full[AddressLength:], sum2[:4]) {
+		return nil, ErrInvalidAddress
+	}
+	return raw, nil
+}
+
+// IsValidAddress reports whether s is a valid TRON address in Base58 or hex
+// form.
+func IsValidAddress(s string) bool {
+	_, err := DecodeAddress(s)
+	return err == nil
+}
+
 // pubUncompressed returns the uncompressed public key bytes for the given
 // private key in the format 0x04 || X || Y, where X and Y are 32-byte big-endian
 // coordinates. This utility pads coordinates with leading zeros if necessary.
 func pubUncompressed(priv *ecdsa.PrivateKey) []byte {
-	x := priv.PublicKey.X.Bytes()
-	y := priv.PublicKey.Y.Bytes()
+	return pubKeyUncompressed(&priv.PublicKey)
+}
+
+// pubKeyUncompressed is the public-key counterpart of pubUncompressed.
+func pubKeyUncompressed(pub *ecdsa.PublicKey) []byte {
+	x := pub.X.Bytes()
+	y := pub.Y.Bytes()
 
 	for len(x) < 32 {
 		x = append([]byte{0x00}, x...)
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"crypto/ecdsa"
 	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"math/big"
 	"testing"
 
@@ -104,3 +106,53 @@ func TestPubUncompressed_ManualSmallCoordinates(t *testing.T) {
 		t.Fatalf("expected X and Y to be 32 bytes each")
 	}
 }
+
+func TestTronAddressFromPublic(t *testing.T) {
+	w, err := RestoreWallet(testMnemonic)
+	if err != nil {
+		t.Fatalf("RestoreWallet error: %v", err)
+	}
+	priv, err := w.Derive(0)
+	if err != nil {
+		t.Fatalf("Derive error: %v", err)
+	}
+	if got := TronAddressFromPublic(&priv.PublicKey); got != "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH" {
+		t.Fatalf("unexpected address %s", got)
+	}
+}
+
+func TestDecodeAndEncodeAddress(t *testing.T) {
+	const b58 = "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH"
+	raw, err := DecodeAddress(b58)
+	if err != nil {
+		t.Fatalf("DecodeAddress error: %v", err)
+	}
+	if len(raw) != AddressLength || raw[0] != 0x41 {
+		t.Fatalf("unexpected raw address %x", raw)
+	}
+	fromHex, err := DecodeAddress("0x" + hex.EncodeToString(raw))
+	if err != nil || !bytes.Equal(fromHex, raw) {
+		t.Fatalf("hex form mismatch: %x (%v)", fromHex, err)
+	}
+	if enc, err := EncodeAddress(raw); err != nil || enc != b58 {
+		t.Fatalf("EncodeAddress mismatch: %s (%v)", enc, err)
+	}
+	if !IsValidAddress(b58) || IsValidAddress("TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdh") {
+		t.Fatalf("IsValidAddress gave wrong result")
+	}
+
+	for _, bad := range []string{
+		"",
+		"TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdh", // checksum
+		"42" + hex.EncodeToString(raw[1:]),   // wrong prefix
+		"41zz" + hex.EncodeToString(raw[2:]), // bad hex
+		base58.Encode(append([]byte{0x00}, raw[1:]...)),
+	} {
+		if _, err := DecodeAddress(bad); !errors.Is(err, ErrInvalidAddress) {
+			t.Fatalf("expected ErrInvalidAddress for %q, got %v", bad, err)
+		}
+	}
+	if _, err := EncodeAddress(raw[:20]); !errors.Is(err, ErrInvalidAddress) {
+		t.Fatalf("expected ErrInvalidAddress for short raw address")
+	}
+}
@@ -0,0 +1,195 @@
+package tronwallet
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultAirgapFragmentLen is a fragment size whose frames fit comfortably
+// in a QR code that phone and laptop cameras scan reliably.
+const DefaultAirgapFragmentLen = 200
+
+// airgapScheme prefixes every air-gap frame.
+const airgapScheme = "tronwallet:"
+
+// AirgapKind identifies the payload carried by air-gap frames.
+type AirgapKind string
+
+// Payload kinds for air-gap frames.
+const (
+	// AirgapUnsignedTransaction carries a Transaction.Marshal encoding
+	// without signatures, from the online machine to the signer.
+	AirgapUnsignedTransaction AirgapKind = "unsigned-tx"
+	// AirgapSignedTransaction carries a Transaction.Marshal encoding with
+	// signatures, from the signer back to the online machine.
+	AirgapSignedTransaction AirgapKind = "signed-tx"
+	// AirgapPartialTransaction carries a PartialTransaction.Marshal
+	// container between multisig participants.
+	AirgapPartialTransaction AirgapKind = "partial-tx"
+)
+
+// ErrAirgapFrame is returned for text that is not a valid air-gap frame.
+var ErrAirgapFrame = errors.New("invalid air-gap frame")
+
+// validAirgapKind reports whether k is a non-empty string of lowercase
+// letters, digits and hyphens.
+func validAirgapKind(k AirgapKind) bool {
+	if k == "" {
+		return false
+	}
+	for _, r := range k {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// AirgapEncoder turns a payload into an endless loop of text frames, to be
+// shown one after another as an animated QR code:
+//
+//	tronwallet:<kind>/<seq>-<count>/<length>-<crc32>/<base64url fragment>
+//
+// Frames after the first count mix fragments with fountain codes, so the
+// receiving camera can start at any point and miss frames.
+type AirgapEncoder struct {
+	kind     AirgapKind
+	fountain *FountainEncoder
+}
+
+// NewAirgapEncoder splits payload into fragments of at most maxFragmentLen
+// bytes.
+func NewAirgapEncoder(kind AirgapKind, payload []byte, maxFragmentLen int) (*AirgapEncoder, error) {
+	if !validAirgapKind(kind) {
+		return nil, fmt.Errorf("invalid air-gap kind %q", kind)
+	}
+	f, err := NewFountainEncoder(payload, maxFragmentLen)
+	if err != nil {
+		return nil, err
+	}
+	return &AirgapEncoder{kind: kind, fountain: f}, nil
+}
+
+// NewTransactionAirgapEncoder encodes tx as AirgapSignedTransaction if it
+// has signatures and AirgapUnsignedTransaction otherwise.
+func NewTransactionAirgapEncoder(tx *Transaction, maxFragmentLen int) (*AirgapEncoder, error) {
+	kind := AirgapUnsignedTransaction
+	if len(tx.Signatures) > 0 {
+		kind = AirgapSignedTransaction
+	}
+	return NewAirgapEncoder(kind, tx.Marshal(), maxFragmentLen)
+}
+
+// SeqLen returns the number of fragments, the fewest frames a receiver
+// needs.
+func (e *AirgapEncoder) SeqLen() int { return e.fountain.SeqLen() }
+
+// NextFrame returns the text of the next frame.
+func (e *AirgapEncoder) NextFrame() string {
+	p := e.fountain.NextPart()
+	return fmt.Sprintf("%s%s/%d-%d/%d-%08x/%s", airgapScheme, e.kind, p.SeqNum, p.SeqLen,
+		p.MessageLen, p.Checksum, base64.RawURLEncoding.EncodeToString(p.Data))
+}
+
+// NextQR returns the next frame as a QR code.
+func (e *AirgapEncoder) NextQR(level QRErrorCorrection) (*QRCode, error) {
+	return EncodeQR([]byte(e.NextFrame()), level)
+}
+
+// AirgapDecoder collects scanned frames until the payload is complete.
+type AirgapDecoder struct {
+	kind     AirgapKind
+	fountain *FountainDecoder
+}
+
+// NewAirgapDecoder returns an empty decoder.
+func NewAirgapDecoder() *AirgapDecoder {
+	return &AirgapDecoder{fountain: NewFountainDecoder()}
+}
+
+// Receive adds the text of a scanned frame. Frames of another payload are
+// rejected with ErrFountainMismatch.
+func (d *AirgapDecoder) Receive(frame string) error {
+	kind, part, err := parseAirgapFrame(strings.TrimSpace(frame))
+	if err != nil {
+		return err
+	}
+	if d.kind != "" && kind != d.kind {
+		return ErrFountainMismatch
+	}
+	if err := d.fountain.Receive(part); err != nil {
+		return err
+	}
+	d.kind = kind
+	return nil
+}
+
+func parseAirgapFrame(frame string) (AirgapKind, FountainPart, error) {
+	var p FountainPart
+	rest, ok := strings.CutPrefix(frame, airgapScheme)
+	fields := strings.Split(rest, "/")
+	if !ok || len(fields) != 4 || !validAirgapKind(AirgapKind(fields[0])) {
+		return "", p, ErrAirgapFrame
+	}
+	seq, count, ok1 := strings.Cut(fields[1], "-")
+	length, checksum, ok2 := strings.Cut(fields[2], "-")
+	if !ok1 || !ok2 || len(checksum) != 8 {
+		return "", p, ErrAirgapFrame
+	}
+	var nums [4]uint64
+	for i, s := range []string{seq, count, length, checksum} {
+		base := 10
+		if i == 3 {
+			base = 16
+		}
+		n, err := strconv.ParseUint(s, base, 32)
+		if err != nil {
+			return "", p, ErrAirgapFrame
+		}
+		nums[i] = n
+	}
+	data, err := base64.RawURLEncoding.DecodeString(fields[3])
+	if err != nil {
+		return "", p, ErrAirgapFrame
+	}
+	p = FountainPart{
+		SeqNum:     uint32(nums[0]),
+		SeqLen:     uint32(nums[1]),
+		MessageLen: uint32(nums[2]),
+		Checksum:   uint32(nums[3]),
+		Data:       data,
+	}
+	return AirgapKind(fields[0]), p, nil
+}
+
+// Complete reports whether the payload has been reassembled.
+func (d *AirgapDecoder) Complete() bool { return d.fountain.Complete() }
+
+// Progress returns the fraction of the payload recovered so far, from 0
+// to 1, for display while scanning.
+func (d *AirgapDecoder) Progress() float64 { return d.fountain.Progress() }
+
+// Result returns the payload kind and bytes once Complete reports true.
+func (d *AirgapDecoder) Result() (AirgapKind, []byte, error) {
+	payload, err := d.fountain.Message()
+	if err != nil {
+		return "", nil, err
+	}
+	return d.kind, payload, nil
+}
+
+// Transaction parses a completed AirgapUnsignedTransaction or
+// AirgapSignedTransaction payload.
+func (d *AirgapDecoder) Transaction() (*Transaction, error) {
+	kind, payload, err := d.Result()
+	if err != nil {
+		return nil, err
+	}
+	if kind != AirgapUnsignedTransaction && kind != AirgapSignedTransaction {
+		return nil, fmt.Errorf("air-gap payload is %s, not a transaction", kind)
+	}
+	return ParseTransaction(payload)
+}
@@ -0,0 +1,156 @@
+package tronwallet
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// scanFrames feeds every other QR frame of e into a new decoder, as a
+// camera that misses frames would, until the payload is complete.
+func scanFrames(t *testing.T, e *AirgapEncoder) *AirgapDecoder {
+	t.Helper()
+	d := NewAirgapDecoder()
+	for i := 0; !d.Complete(); i++ {
+		if i > 20*e.SeqLen() {
+			t.Fatalf("payload not decoded after %d frames", i)
+		}
+		q, err := e.NextQR(QRMedium)
+		if err != nil {
+			t.Fatalf("NextQR error: %v", err)
+		}
+		if i%2 == 1 {
+			continue
+		}
+		if q.Version() > 15 {
+			t.Fatalf("frame needs QR version %d", q.Version())
+		}
+		if err := d.Receive(string(decodeQRForTest(t, q))); err != nil {
+			t.Fatalf("Receive error: %v", err)
+		}
+	}
+	return d
+}
+
+func TestAirgap_OfflineSigningLoop(t *testing.T) {
+	// Online machine: build the unsigned transaction and show it.
+	opts := testTxOptions()
+	opts.FeeLimit = 100_000_000
+	tx, err := NewTRC20TransferTransaction(opts, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", testUSDT, 0, "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", big.NewInt(1_500_000))
+	if err != nil {
+		t.Fatalf("NewTRC20TransferTransaction error: %v", err)
+	}
+	out, err := NewTransactionAirgapEncoder(tx, 64)
+	if err != nil {
+		t.Fatalf("NewTransactionAirgapEncoder error: %v", err)
+	}
+	if out.SeqLen() < 2 {
+		t.Fatalf("expected a multi-frame payload, got %d frames", out.SeqLen())
+	}
+
+	// Cold wallet: scan, review and sign with a derived key.
+	cold := scanFrames(t, out)
+	if kind, _, _ := cold.Result(); kind != AirgapUnsignedTransaction {
+		t.Fatalf("unexpected kind %q", kind)
+	}
+	unsigned, err := cold.Transaction()
+	if err != nil {
+		t.Fatalf("Transaction error: %v", err)
+	}
+	if s, err := SummarizeTransaction(unsigned); err != nil || s.Opaque || s.Method != "transfer(address,uint256)" {
+		t.Fatalf("unexpected summary %+v, %v", s, err)
+	}
+	w, _ := RestoreWallet(testMnemonic)
+	priv, _ := w.Derive(0)
+	if err := unsigned.SignWithKey(priv); err != nil {
+		t.Fatalf("SignWithKey error: %v", err)
+	}
+	back, err := NewTransactionAirgapEncoder(unsigned, DefaultAirgapFragmentLen)
+	if err != nil {
+		t.Fatalf("NewTransactionAirgapEncoder error: %v", err)
+	}
+
+	// Online machine: scan the signed result.
+	online := scanFrames(t, back)
+	signed, err := online.Transaction()
+	if err != nil {
+		t.Fatalf("Transaction error: %v", err)
+	}
+	if kind, _, _ := online.Result(); kind != AirgapSignedTransaction {
+		t.Fatalf("unexpected kind %q", kind)
+	}
+	if !bytes.Equal(signed.RawData, tx.RawData) || len(signed.Signatures) != 1 {
+		t.Fatalf("signed transaction does not match")
+	}
+	if err := VerifySignature(signed.ID(), signed.Signatures[0], "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH"); err != nil {
+		t.Fatalf("VerifySignature error: %v", err)
+	}
+}
+
+func TestAirgap_PartialTransactionPayload(t *testing.T) {
+	_, tx, keys := testMultisig(t)
+	p, _ := NewPartialTransaction(tx, 2, 2, keys)
+	container, _ := p.Marshal()
+	e, err := NewAirgapEncoder(AirgapPartialTransaction, container, DefaultAirgapFragmentLen)
+	if err != nil {
+		t.Fatalf("NewAirgapEncoder error: %v", err)
+	}
+	d := scanFrames(t, e)
+	kind, payload, err := d.Result()
+	if err != nil || kind != AirgapPartialTransaction || !bytes.Equal(payload, container) {
+		t.Fatalf("unexpected result %q, %v", kind, err)
+	}
+	if _, err := d.Transaction(); err == nil {
+		t.Fatalf("expected error for a non-transaction payload")
+	}
+}
+
+func TestAirgap_Errors(t *testing.T) {
+	if _, err := NewAirgapEncoder("Bad Kind", []byte("x"), 10); err == nil {
+		t.Fatalf("expected error for invalid kind")
+	}
+	if _, err := NewAirgapEncoder("", []byte("x"), 10); err == nil {
+		t.Fatalf("expected error for empty kind")
+	}
+	if _, err := NewAirgapEncoder(AirgapSignedTransaction, nil, 10); err == nil {
+		t.Fatalf("expected error for empty payload")
+	}
+	if _, err := NewAirgapDecoder().Transaction(); !errors.Is(err, ErrFountainPending) {
+		t.Fatalf("expected ErrFountainPending, got %v", err)
+	}
+
+	e, _ := NewAirgapEncoder(AirgapUnsignedTransaction, []byte("hello world"), 4)
+	frame := e.NextFrame()
+	if frame != "tronwallet:unsigned-tx/1-3/11-0d4a1185/aGVsbA" {
+		t.Fatalf("unexpected frame %q", frame)
+	}
+	bad := []string{
+		"",
+		"ur:unsigned-tx/1-3/11-0d4a1185/aGVsbA",
+		"tronwallet:unsigned-tx/1-3/11-0d4a1185",
+		"tronwallet:Unsigned/1-3/11-0d4a1185/aGVsbA",
+		"tronwallet:unsigned-tx/1/11-0d4a1185/aGVsbA",
+		"tronwallet:unsigned-tx/1-3/11-0d4a11/aGVsbA",
+		"tronwallet:unsigned-tx/x-3/11-0d4a1185/aGVsbA",
+		"tronwallet:unsigned-tx/1-3/11-0d4a1185/aGVsbA==",
+	}
+	for _, f := range bad {
+		if err := NewAirgapDecoder().Receive(f); !errors.Is(err, ErrAirgapFrame) {
+			t.Fatalf("%q: expected ErrAirgapFrame, got %v", f, err)
+		}
+	}
+	if err := NewAirgapDecoder().Receive("tronwallet:unsigned-tx/1-9/11-0d4a1185/aGVsbA"); !errors.Is(err, ErrFountainPart) {
+		t.Fatalf("expected ErrFountainPart, got %v", err)
+	}
+
+	d := NewAirgapDecoder()
+	if err := d.Receive(frame + "\n"); err != nil {
+		t.Fatalf("Receive error: %v", err)
+	}
+	other := strings.Replace(e.NextFrame(), "unsigned-tx", "signed-tx", 1)
+	if err := d.Receive(other); !errors.Is(err, ErrFountainMismatch) {
+		t.Fatalf("expected ErrFountainMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,293 @@
+package tronwallet
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Contract types, as numbered in java-tron's Tron.proto.
+const (
+	TransferContractType        ContractType = 1
+	TransferAssetContractType   ContractType = 2
+	VoteWitnessContractType     ContractType = 4
+	WithdrawBalanceContractType ContractType = 13
+	CreateSmartContractType     ContractType = 30
+	TriggerSmartContractType    ContractType = 31
+
+	AccountPermissionUpdateContractType ContractType = 46
+
+	FreezeBalanceV2ContractType        ContractType = 54
+	UnfreezeBalanceV2ContractType      ContractType = 55
+	WithdrawExpireUnfreezeContractType ContractType = 56
+	DelegateResourceContractType       ContractType = 57
+	UnDelegateResourceContractType     ContractType = 58
+	CancelAllUnfreezeV2ContractType    ContractType = 59
+)
+
+// contractTypeNames maps contract types to their protobuf message names.
+var contractTypeNames = map[ContractType]string{
+	TransferContractType:        "TransferContract",
+	TransferAssetContractType:   "TransferAssetContract",
+	VoteWitnessContractType:     "VoteWitnessContract",
+	WithdrawBalanceContractType: "WithdrawBalanceContract",
+	CreateSmartContractType:     "CreateSmartContract",
+	TriggerSmartContractType:    "TriggerSmartContract",
+
+	AccountPermissionUpdateContractType: "AccountPermissionUpdateContract",
+
+	FreezeBalanceV2ContractType:        "FreezeBalanceV2Contract",
+	UnfreezeBalanceV2ContractType:      "UnfreezeBalanceV2Contract",
+	WithdrawExpireUnfreezeContractType: "WithdrawExpireUnfreezeContract",
+	DelegateResourceContractType:       "DelegateResourceContract",
+	UnDelegateResourceContractType:     "UnDelegateResourceContract",
+	CancelAllUnfreezeV2ContractType:    "CancelAllUnfreezeV2Contract",
+}
+
+// contractParsers decodes the parameter of each supported contract type.
+var contractParsers = map[ContractType]func([]byte) (ContractParameter, error){
+	TransferContractType:        parseTransferContract,
+	TransferAssetContractType:   parseTransferAssetContract,
+	VoteWitnessContractType:     parseVoteWitnessContract,
+	WithdrawBalanceContractType: parseWithdrawBalanceContract,
+	CreateSmartContractType:     parseCreateSmartContract,
+	TriggerSmartContractType:    parseTriggerSmartContract,
+
+	AccountPermissionUpdateContractType: parseAccountPermissionUpdateContract,
+
+	FreezeBalanceV2ContractType:        parseFreezeBalanceV2Contract,
+	UnfreezeBalanceV2ContractType:      parseUnfreezeBalanceV2Contract,
+	WithdrawExpireUnfreezeContractType: parseWithdrawExpireUnfreezeContract,
+	DelegateResourceContractType:       parseDelegateResourceContract,
+	UnDelegateResourceContractType:     parseUnDelegateResourceContract,
+	CancelAllUnfreezeV2ContractType:    parseCancelAllUnfreezeV2Contract,
+}
+
+// ErrInvalidAmount is returned when a transfer amount is not positive.
+var ErrInvalidAmount = errors.New("amount must be positive")
+
+// contractAddress decodes the address s of the named contract field.
+func contractAddress(name, s string) ([]byte, error) {
+	raw, err := DecodeAddress(s)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return raw, nil
+}
+
+// TransferContract sends Amount sun of TRX from OwnerAddress to ToAddress.
+// Addresses are Base58 or hex TRON addresses.
+type TransferContract struct {
+	OwnerAddress string
+	ToAddress    string
+	Amount       int64
+}
+
+// NewTransferTransaction builds an unsigned TRX transfer of amount sun.
+func NewTransferTransaction(opts TransactionOptions, from, to string, amount int64) (*Transaction, error) {
+	return NewTransaction(opts, &TransferContract{OwnerAddress: from, ToAddress: to, Amount: amount})
+}
+
+// ContractType implements ContractParameter.
+func (c *TransferContract) ContractType() ContractType { return TransferContractType }
+
+func (c *TransferContract) marshalProto() ([]byte, error) {
+	owner, err := contractAddress("owner address", c.OwnerAddress)
+	if err != nil {
+		return nil, err
+	}
+	to, err := contractAddress("to address", c.ToAddress)
+	if err != nil {
+		return nil, err
+	}
+	if string(owner) == string(to) {
+		return nil, errors.New("cannot transfer to the owner address")
+	}
+	if c.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	var w protoWriter
+	w.bytes(1, owner)
+	w.bytes(2, to)
+	w.int64(3, c.Amount)
+	return w.buf, nil
+}
+
+func parseTransferContract(b []byte) (ContractParameter, error) {
+	fields, err := parseProto(b)
+	if err != nil {
+		return nil, err
+	}
+	c := &TransferContract{}
+	for _, f := range fields {
+		switch f.Num {
+		case 1:
+			c.OwnerAddress, err = f.address()
+		case 2:
+			c.ToAddress, err = f.address()
+		case 3:
+			c.Amount, err = f.int64()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// TransferAssetContract sends Amount units of the TRC10 token AssetName from
+// OwnerAddress to ToAddress. Since java-tron's AllowSameTokenName proposal,
+// AssetName is the token ID in decimal, such as "1002000".
+type TransferAssetContract struct {
+	AssetName    string
+	OwnerAddress string
+	ToAddress    string
+	Amount       int64
+}
+
+// ContractType implements ContractParameter.
+func (c *TransferAssetContract) ContractType() ContractType { return TransferAssetContractType }
+
+func (c *TransferAssetContract) marshalProto() ([]byte, error) {
+	if !isTRC10TokenID(c.AssetName) {
+		return nil, fmt.Errorf("invalid TRC10 token ID %q", c.AssetName)
+	}
+	owner, err := contractAddress("owner address", c.OwnerAddress)
+	if err != nil {
+		return nil, err
+	}
+	to, err := contractAddress("to address", c.ToAddress)
+	if err != nil {
+		return nil, err
+	}
+	if string(owner) == string(to) {
+		return nil, errors.New("cannot transfer to the owner address")
+	}
+	if c.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	var w protoWriter
+	w.string(1, c.AssetName)
+	w.bytes(2, owner)
+	w.bytes(3, to)
+	w.int64(4, c.Amount)
+	return w.buf, nil
+}
+
+// isTRC10TokenID reports whether s is a decimal token ID without leading
+// zeros.
+func isTRC10TokenID(s string) bool {
+	if s == "" || s[0] == '0' || len(s) > 19 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func parseTransferAssetContract(b []byte) (ContractParameter, error) {
+	fields, err := parseProto(b)
+	if err != nil {
+		return nil, err
+	}
+	c := &TransferAssetContract{}
+	for _, f := range fields {
+		switch f.Num {
+		case 1:
+			var name []byte
+			name, err = f.bytes()
+			c.AssetName = string(name)
+		case 2:
+			c.OwnerAddress, err = f.address()
+		case 3:
+			c.ToAddress, err = f.address()
+		case 4:
+			c.Amount, err = f.int64()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// TriggerSmartContract calls ContractAddress with the ABI-encoded Data,
+// sending CallValue sun of TRX and CallTokenValue of TRC10 token TokenID.
+type TriggerSmartContract struct {
+	OwnerAddress    string
+	ContractAddress string
+	CallValue       int64
+	Data            []byte
+	CallTokenValue  int64
+	TokenID         int64
+}
+
+// NewTriggerSmartContractTransaction builds an unsigned smart contract call.
+// opts.FeeLimit must be set, since a call without a fee limit cannot pay for
+// energy.
+func NewTriggerSmartContractTransaction(opts TransactionOptions, owner, contract string, callValue int64, data []byte) (*Transaction, error) {
+	if opts.FeeLimit <= 0 {
+		return nil, fmt.Errorf("%w: smart contract calls need a fee limit", ErrInvalidTransaction)
+	}
+	return NewTransaction(opts, &TriggerSmartContract{
+		OwnerAddress:    owner,
+		ContractAddress: contract,
+		CallValue:       callValue,
+		Data:            data,
+	})
+}
+
+// ContractType implements ContractParameter.
+func (c *TriggerSmartContract) ContractType() ContractType { return TriggerSmartContractType }
+
+func (c *TriggerSmartContract) marshalProto() ([]byte, error) {
+	owner, err := contractAddress("owner address", c.OwnerAddress)
+	if err != nil {
+		return nil, err
+	}
+	contract, err := contractAddress("contract address", c.ContractAddress)
+	if err != nil {
+		return nil, err
+	}
+	if c.CallValue < 0 || c.CallTokenValue < 0 {
+		return nil, errors.New("call value must not be negative")
+	}
+	var w protoWriter
+	w.bytes(1, owner)
+	w.bytes(2, contract)
+	w.int64(3, c.CallValue)
+	w.bytes(4, c.Data)
+	w.int64(5, c.CallTokenValue)
+	w.int64(6, c.TokenID)
+	return w.buf, nil
+}
+
+func parseTriggerSmartContract(b []byte) (ContractParameter, error) {
+	fields, err := parseProto(b)
+	if err != nil {
+		return nil, err
+	}
+	c := &TriggerSmartContract{}
+	for _, f := range fields {
+		switch f.Num {
+		case 1:
+			c.OwnerAddress, err = f.address()
+		case 2:
+			c.ContractAddress, err = f.address()
+		case 3:
+			c.CallValue, err = f.int64()
+		case 4:
+			c.Data, err = f.bytes()
+		case 5:
+			c.CallTokenValue, err = f.int64()
+		case 6:
+			c.TokenID, err = f.int64()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
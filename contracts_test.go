@@ -0,0 +1,62 @@
+package tronwallet
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTransferContract_Validation(t *testing.T) {
+	from, to := "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK"
+	for name, c := range map[string]*TransferContract{
+		"bad owner": {OwnerAddress: "nope", ToAddress: to, Amount: 1},
+		"bad to":    {OwnerAddress: from, ToAddress: "nope", Amount: 1},
+		"self":      {OwnerAddress: from, ToAddress: from, Amount: 1},
+		"zero":      {OwnerAddress: from, ToAddress: to},
+	} {
+		if _, err := NewTransaction(testTxOptions(), c); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+	if _, err := NewTransferTransaction(testTxOptions(), from, to, -5); !errors.Is(err, ErrInvalidAmount) {
+		t.Fatalf("expected ErrInvalidAmount, got %v", err)
+	}
+	if _, err := NewTransferTransaction(testTxOptions(), from, "nope", 1); !errors.Is(err, ErrInvalidAddress) {
+		t.Fatalf("expected ErrInvalidAddress, got %v", err)
+	}
+	if TransferContractType.String() != "TransferContract" {
+		t.Fatalf("unexpected type name %s", TransferContractType)
+	}
+}
+
+func TestTriggerSmartContract_Validation(t *testing.T) {
+	opts := testTxOptions()
+	opts.FeeLimit = 1
+	owner := "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH"
+	if _, err := NewTriggerSmartContractTransaction(opts, "nope", testUSDT, 0, nil); !errors.Is(err, ErrInvalidAddress) {
+		t.Fatalf("expected ErrInvalidAddress, got %v", err)
+	}
+	if _, err := NewTriggerSmartContractTransaction(opts, owner, testUSDT, -1, nil); err == nil {
+		t.Fatalf("expected error for negative call value")
+	}
+
+	c := &TriggerSmartContract{OwnerAddress: owner, ContractAddress: testUSDT, CallValue: 5, Data: []byte{1}, CallTokenValue: 7, TokenID: 1000001}
+	tx, err := NewTransaction(opts, c)
+	if err != nil {
+		t.Fatalf("NewTransaction error: %v", err)
+	}
+	raw, err := tx.Raw()
+	if err != nil {
+		t.Fatalf("Raw error: %v", err)
+	}
+	got := raw.Contracts[0].Parameter.(*TriggerSmartContract)
+	if !reflect.DeepEqual(got, c) {
+		t.Fatalf("round trip mismatch %+v", got)
+	}
+	if _, err := parseTriggerSmartContract([]byte{0x20, 0x01}); err == nil {
+		t.Fatalf("expected wire type error")
+	}
+	if _, err := parseTriggerSmartContract([]byte{0x0a}); err == nil {
+		t.Fatalf("expected truncation error")
+	}
+}
@@ -0,0 +1,259 @@
+package tronwallet
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// maxContractNameSize is the longest contract name java-tron accepts.
+const maxContractNameSize = 32
+
+// SmartContract is the contract being deployed by CreateSmartContract.
+// ContractAddress is assigned by the network and left empty when deploying.
+type SmartContract struct {
+	OriginAddress   string
+	ContractAddress string
+	ABI             []ABIEntry
+	// Bytecode is the creation code followed by the ABI-encoded constructor
+	// arguments.
+	Bytecode  []byte
+	CallValue int64
+	// ConsumeUserResourcePercent is the share of each call's energy, 0 to
+	// 100, paid by the caller; the origin pays the rest, up to
+	// OriginEnergyLimit per call.
+	ConsumeUserResourcePercent int64
+	Name                       string
+	OriginEnergyLimit          int64
+}
+
+// CreateSmartContract deploys NewContract from OwnerAddress, which must be
+// its origin.
+type CreateSmartContract struct {
+	OwnerAddress   string
+	NewContract    SmartContract
+	CallTokenValue int64
+	TokenID        int64
+}
+
+// DeployOptions describes a contract to deploy with
+// NewCreateSmartContractTransaction.
+type DeployOptions struct {
+	Name     string
+	ABI      []ABIEntry
+	Bytecode []byte
+	// ConstructorArgs are the ABI-encoded constructor arguments, appended
+	// to Bytecode. Encode them with EncodeABI and the constructor's inputs.
+	ConstructorArgs []byte
+	// CallValue is the TRX, in sun, sent to a payable constructor.
+	CallValue int64
+	// ConsumeUserResourcePercent is the share of energy callers pay, 0 to
+	// 100. With 100 the deployer never pays for calls.
+	ConsumeUserResourcePercent int64
+	// OriginEnergyLimit caps the energy the deployer pays per call. It
+	// must be positive.
+	OriginEnergyLimit int64
+}
+
+// NewCreateSmartContractTransaction builds an unsigned deployment of a
+// contract from owner and returns it with the address the contract will
+// have once the transaction is confirmed. opts.FeeLimit must be set to pay
+// for the deployment's energy.
+func NewCreateSmartContractTransaction(opts TransactionOptions, owner string, d DeployOptions) (*Transaction, string, error) {
+	if opts.FeeLimit <= 0 {
+		return nil, "", fmt.Errorf("%w: contract deployments need a fee limit", ErrInvalidTransaction)
+	}
+	bytecode := append(append([]byte(nil), d.Bytecode...), d.ConstructorArgs...)
+	tx, err := NewTransaction(opts, &CreateSmartContract{
+		OwnerAddress: owner,
+		NewContract: SmartContract{
+			OriginAddress:              owner,
+			ABI:                        d.ABI,
+			Bytecode:                   bytecode,
+			CallValue:                  d.CallValue,
+			ConsumeUserResourcePercent: d.ConsumeUserResourcePercent,
+			Name:                       d.Name,
+			OriginEnergyLimit:          d.OriginEnergyLimit,
+		},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	address, err := PredictContractAddress(tx)
+	if err != nil {
+		return nil, "", err
+	}
+	return tx, address, nil
+}
+
+// PredictContractAddress returns the address java-tron assigns to the
+// contract deployed by tx: 0x41 followed by the last 20 bytes of
+// Keccak-256(txID || owner address). Signing does not change it, since the
+// transaction ID covers only the raw data.
+func PredictContractAddress(tx *Transaction) (string, error) {
+	raw, err := tx.Raw()
+	if err != nil {
+		return "", err
+	}
+	if len(raw.Contracts) != 1 {
+		return "", fmt.Errorf("%w: expected one contract, got %d", ErrInvalidTransaction, len(raw.Contracts))
+	}
+	c, ok := raw.Contracts[0].Parameter.(*CreateSmartContract)
+	if !ok {
+		return "", fmt.Errorf("%w: %s does not deploy a contract", ErrInvalidTransaction, raw.Contracts[0].Parameter.ContractType())
+	}
+	return contractAddressFor(tx.ID(), c.OwnerAddress)
+}
+
+// contractAddressFor derives a deployed contract's address from the
+// deploying transaction's ID and owner.
+func contractAddressFor(txID []byte, owner string) (string, error) {
+	raw, err := DecodeAddress(owner)
+	if err != nil {
+		return "", err
+	}
+	hash := keccak256(txID, raw)
+	return encodeAddress(append([]byte{addressPrefix}, hash[12:]...)), nil
+}
+
+// ContractType implements ContractParameter.
+func (c *CreateSmartContract) ContractType() ContractType { return CreateSmartContractType }
+
+func (c *CreateSmartContract) marshalProto() ([]byte, error) {
+	owner, err := contractAddress("owner address", c.OwnerAddress)
+	if err != nil {
+		return nil, err
+	}
+	sc, err := c.NewContract.marshal(owner)
+	if err != nil {
+		return nil, err
+	}
+	if c.CallTokenValue < 0 {
+		return nil, errors.New("call token value must not be negative")
+	}
+	var w protoWriter
+	w.bytes(1, owner)
+	w.message(2, sc)
+	w.int64(3, c.CallTokenValue)
+	w.int64(4, c.TokenID)
+	return w.buf, nil
+}
+
+// marshal validates the contract as java-tron does for a deployment by
+// owner and encodes it.
+func (sc *SmartContract) marshal(owner []byte) ([]byte, error) {
+	origin, err := contractAddress("origin address", sc.OriginAddress)
+	if err != nil {
+		return nil, err
+	}
+	if string(origin) != string(owner) {
+		return nil, errors.New("origin address must be the owner address")
+	}
+	var address []byte
+	if sc.ContractAddress != "" {
+		if address, err = contractAddress("contract address", sc.ContractAddress); err != nil {
+			return nil, err
+		}
+	}
+	if len(sc.Bytecode) == 0 {
+		return nil, errors.New("missing bytecode")
+	}
+	if len(sc.Name) > maxContractNameSize {
+		return nil, fmt.Errorf("contract name is longer than %d bytes", maxContractNameSize)
+	}
+	if sc.CallValue < 0 {
+		return nil, errors.New("call value must not be negative")
+	}
+	if sc.ConsumeUserResourcePercent < 0 || sc.ConsumeUserResourcePercent > 100 {
+		return nil, errors.New("consume user resource percent must be between 0 and 100")
+	}
+	if sc.OriginEnergyLimit <= 0 {
+		return nil, errors.New("origin energy limit must be positive")
+	}
+	abi, err := marshalABI(sc.ABI)
+	if err != nil {
+		return nil, err
+	}
+	var w protoWriter
+	w.bytes(1, origin)
+	w.bytes(2, address)
+	w.message(3, abi)
+	w.bytes(4, sc.Bytecode)
+	w.int64(5, sc.CallValue)
+	w.int64(6, sc.ConsumeUserResourcePercent)
+	w.string(7, sc.Name)
+	w.int64(8, sc.OriginEnergyLimit)
+	return w.buf, nil
+}
+
+func parseCreateSmartContract(b []byte) (ContractParameter, error) {
+	fields, err := parseProto(b)
+	if err != nil {
+		return nil, err
+	}
+	c := &CreateSmartContract{}
+	for _, f := range fields {
+		switch f.Num {
+		case 1:
+			c.OwnerAddress, err = f.address()
+		case 2:
+			var sc *SmartContract
+			if sc, err = parseSmartContract(f); err == nil {
+				c.NewContract = *sc
+			}
+		case 3:
+			c.CallTokenValue, err = f.int64()
+		case 4:
+			c.TokenID, err = f.int64()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func parseSmartContract(f protoField) (*SmartContract, error) {
+	b, err := f.bytes()
+	if err != nil {
+		return nil, err
+	}
+	fields, err := parseProto(b)
+	if err != nil {
+		return nil, err
+	}
+	sc := &SmartContract{}
+	for _, f := range fields {
+		switch f.Num {
+		case 1:
+			sc.OriginAddress, err = f.address()
+		case 2:
+			sc.ContractAddress, err = f.address()
+		case 3:
+			var abi []byte
+			if abi, err = f.bytes(); err == nil {
+				sc.ABI, err = parseABI(abi)
+			}
+		case 4:
+			sc.Bytecode, err = f.bytes()
+		case 5:
+			sc.CallValue, err = f.int64()
+		case 6:
+			sc.ConsumeUserResourcePercent, err = f.int64()
+		case 7:
+			sc.Name, err = f.string()
+		case 8:
+			sc.OriginEnergyLimit, err = f.int64()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sc, nil
+}
+
+// codeHash returns the SHA-256 of the bytecode, for display.
+func (sc *SmartContract) codeHash() []byte {
+	h := sha256.Sum256(sc.Bytecode)
+	return h[:]
+}
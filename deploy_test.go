@@ -0,0 +1,151 @@
+package tronwallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testABI is the ABI of the contract deployed in the tests below.
+var testABI = []ABIEntry{
+	{Type: "constructor", Inputs: []ABIParam{{Name: "supply", Type: "uint256"}}, StateMutability: "nonpayable"},
+	{Type: "function", Name: "balanceOf", Inputs: []ABIParam{{Name: "owner", Type: "address"}}, Outputs: []ABIParam{{Type: "uint256"}}, StateMutability: "view"},
+	{Type: "event", Name: "Transfer", Inputs: []ABIParam{{Name: "from", Type: "address", Indexed: true}, {Name: "to", Type: "address", Indexed: true}, {Name: "value", Type: "uint256"}}},
+}
+
+func testDeployOptions(t *testing.T) DeployOptions {
+	t.Helper()
+	args, err := EncodeABI(testABI[0].Inputs, 1000)
+	if err != nil {
+		t.Fatalf("EncodeABI error: %v", err)
+	}
+	code, _ := hex.DecodeString("6080604052348015600e575f80fd5b50603e80601a5f395ff3fe60806040525f80fdfe")
+	return DeployOptions{
+		Name:                       "Escrow",
+		ABI:                        testABI,
+		Bytecode:                   code,
+		ConstructorArgs:            args,
+		ConsumeUserResourcePercent: 100,
+		OriginEnergyLimit:          10_000_000,
+	}
+}
+
+func TestNewCreateSmartContractTransaction(t *testing.T) {
+	opts := testTxOptions()
+	opts.FeeLimit = 1_000_000_000
+	tx, address, err := NewCreateSmartContractTransaction(opts, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", testDeployOptions(t))
+	if err != nil {
+		t.Fatalf("NewCreateSmartContractTransaction error: %v", err)
+	}
+	want := "0a02abcd2208112233445566778840e0a499ffbc315acd02081e12c8020a30747970652e676f6f676c65617069732e636f6d2f70726f746f636f6c2e437265617465536d617274436f6e74726163741293020a1541c8599111f29c1e1e061265b4af93ea1f274ad78a12f9010a1541c8599111f29c1e1e061265b4af93ea1f274ad78a1a8b010a1722111206737570706c791a0775696e74323536300140030a2c1a0962616c616e63654f66221012056f776e65721a07616464726573732a091a0775696e74323536300240020a421a085472616e7366657222110801120466726f6d1a0761646472657373220f08011202746f1a07616464726573732210120576616c75651a0775696e74323536300322436080604052348015600e575f80fd5b50603e80601a5f395ff3fe60806040525f80fdfe00000000000000000000000000000000000000000000000000000000000003e830643a06457363726f774080ade2047080d095ffbc3190018094ebdc03"
+	if got := hex.EncodeToString(tx.RawData); got != want {
+		t.Fatalf("raw data mismatch:\n got %s\nwant %s", got, want)
+	}
+	if address != "TDcvMEBadzdEdFocT9uVR9iF9tKbp3q71v" {
+		t.Fatalf("predicted address = %s", address)
+	}
+
+	// Signing with a derived key does not move the contract address.
+	w, _ := RestoreWallet(testMnemonic)
+	priv, _ := w.Derive(0)
+	if err := tx.SignWithKey(priv); err != nil {
+		t.Fatalf("SignWithKey error: %v", err)
+	}
+	if err := VerifySignature(tx.ID(), tx.Signatures[0], "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH"); err != nil {
+		t.Fatalf("VerifySignature error: %v", err)
+	}
+	if again, err := PredictContractAddress(tx); err != nil || again != address {
+		t.Fatalf("PredictContractAddress = %s, %v", again, err)
+	}
+
+	raw, _ := tx.Raw()
+	c, ok := raw.Contracts[0].Parameter.(*CreateSmartContract)
+	if !ok {
+		t.Fatalf("unexpected parameter %T", raw.Contracts[0].Parameter)
+	}
+	if !reflect.DeepEqual(c.NewContract.ABI, testABI) || c.NewContract.Name != "Escrow" || len(c.NewContract.Bytecode) != 35+32 {
+		t.Fatalf("unexpected parsed contract %+v", c.NewContract)
+	}
+
+	s, err := SummarizeTransaction(tx)
+	if err != nil || s.Opaque || s.Contract != address || s.From != "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH" {
+		t.Fatalf("unexpected summary %v:\n%s", err, s)
+	}
+	if out := s.String(); !strings.Contains(out, "67 bytes, SHA-256 ") || !strings.Contains(out, "100%") {
+		t.Fatalf("unexpected summary:\n%s", out)
+	}
+
+	e, err := EstimateFee(tx, MainnetChainParameters, FeeOptions{Energy: 500_000, Resources: AccountResources{FreeBandwidth: 600}})
+	if err != nil || e.EnergyFee != 105_000_000 || e.FeeLimitTooLow {
+		t.Fatalf("unexpected fee estimate %+v, %v", e, err)
+	}
+
+	data, err := tx.JSON(true)
+	if err != nil {
+		t.Fatalf("JSON error: %v", err)
+	}
+	for _, frag := range []string{`"entrys":[{"inputs":[{"name":"supply","type":"uint256"}],"stateMutability":"Nonpayable","type":"Constructor"}`, `"stateMutability":"View"`, `"indexed":true`, `"name":"Escrow"`, `"origin_energy_limit":10000000`} {
+		if !strings.Contains(string(data), frag) {
+			t.Fatalf("JSON is missing %s:\n%s", frag, data)
+		}
+	}
+	if back, err := ParseTransactionJSON(data); err != nil || string(back.RawData) != string(tx.RawData) {
+		t.Fatalf("JSON round trip failed: %v", err)
+	}
+}
+
+func TestNewCreateSmartContractTransaction_Rejects(t *testing.T) {
+	opts := testTxOptions()
+	opts.FeeLimit = 1_000_000_000
+	owner := "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH"
+	if _, _, err := NewCreateSmartContractTransaction(testTxOptions(), owner, testDeployOptions(t)); !errors.Is(err, ErrInvalidTransaction) {
+		t.Fatalf("expected ErrInvalidTransaction without a fee limit, got %v", err)
+	}
+	cases := map[string]func(*DeployOptions){
+		"no bytecode":      func(d *DeployOptions) { d.Bytecode, d.ConstructorArgs = nil, nil },
+		"long name":        func(d *DeployOptions) { d.Name = strings.Repeat("n", 33) },
+		"call value":       func(d *DeployOptions) { d.CallValue = -1 },
+		"resource percent": func(d *DeployOptions) { d.ConsumeUserResourcePercent = 101 },
+		"energy limit":     func(d *DeployOptions) { d.OriginEnergyLimit = 0 },
+		"entry type":       func(d *DeployOptions) { d.ABI = []ABIEntry{{Type: "modifier"}} },
+		"mutability":       func(d *DeployOptions) { d.ABI = []ABIEntry{{Type: "function", StateMutability: "mutable"}} },
+	}
+	for name, edit := range cases {
+		d := testDeployOptions(t)
+		edit(&d)
+		if _, _, err := NewCreateSmartContractTransaction(opts, owner, d); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+	if _, _, err := NewCreateSmartContractTransaction(opts, "bad", testDeployOptions(t)); err == nil {
+		t.Fatalf("expected error for bad owner")
+	}
+
+	// Hand-built contracts must be deployed by their origin.
+	c := &CreateSmartContract{
+		OwnerAddress: owner,
+		NewContract:  SmartContract{OriginAddress: "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", Bytecode: []byte{0x60}, OriginEnergyLimit: 1},
+	}
+	if _, err := NewTransaction(opts, c); err == nil {
+		t.Fatalf("expected error for a foreign origin")
+	}
+	c.NewContract.OriginAddress = owner
+	c.NewContract.ContractAddress = "bad"
+	if _, err := NewTransaction(opts, c); err == nil {
+		t.Fatalf("expected error for a bad contract address")
+	}
+	c.NewContract.ContractAddress = ""
+	c.CallTokenValue = -1
+	if _, err := NewTransaction(opts, c); err == nil {
+		t.Fatalf("expected error for a negative call token value")
+	}
+
+	if _, err := PredictContractAddress(testTransfer(t)); !errors.Is(err, ErrInvalidTransaction) {
+		t.Fatalf("expected ErrInvalidTransaction for a transfer, got %v", err)
+	}
+	if _, err := PredictContractAddress(&Transaction{RawData: []byte{0xff}}); err == nil {
+		t.Fatalf("expected error for invalid raw data")
+	}
+}
@@ -0,0 +1,208 @@
+package tronwallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownEvent is returned by DecodeEventLog when no event definition
+// matches a log.
+var ErrUnknownEvent = errors.New("log matches no known event")
+
+// Event definitions of the TRC20 and TRC721 standards. The TRC721 Transfer
+// has the same signature as the TRC20 one but indexes the token ID, so the
+// two are told apart by the number of topics.
+var (
+	TRC20Events = []ABIEntry{
+		{Type: "event", Name: "Transfer", Inputs: []ABIParam{{Name: "from", Type: "address", Indexed: true}, {Name: "to", Type: "address", Indexed: true}, {Name: "value", Type: "uint256"}}},
+		{Type: "event", Name: "Approval", Inputs: []ABIParam{{Name: "owner", Type: "address", Indexed: true}, {Name: "spender", Type: "address", Indexed: true}, {Name: "value", Type: "uint256"}}},
+	}
+	TRC721Events = []ABIEntry{
+		{Type: "event", Name: "Transfer", Inputs: []ABIParam{{Name: "from", Type: "address", Indexed: true}, {Name: "to", Type: "address", Indexed: true}, {Name: "tokenId", Type: "uint256", Indexed: true}}},
+		{Type: "event", Name: "Approval", Inputs: []ABIParam{{Name: "owner", Type: "address", Indexed: true}, {Name: "approved", Type: "address", Indexed: true}, {Name: "tokenId", Type: "uint256", Indexed: true}}},
+		{Type: "event", Name: "ApprovalForAll", Inputs: []ABIParam{{Name: "owner", Type: "address", Indexed: true}, {Name: "operator", Type: "address", Indexed: true}, {Name: "approved", Type: "bool"}}},
+	}
+)
+
+// EventLog is a log emitted by a contract, as listed under "log" in the
+// response of /wallet/gettransactioninfobyid. Address is the Base58 address
+// of the emitting contract.
+type EventLog struct {
+	Address string
+	Topics  [][]byte
+	Data    []byte
+}
+
+// eventLogJSON is the node's log format: hex strings, with the contract
+// address as its 20-byte account hash.
+type eventLogJSON struct {
+	Address string   `json:"address"`
+	Topics  []string `json:"topics"`
+	Data    string   `json:"data"`
+}
+
+// UnmarshalJSON decodes a log in the node's format. Hex may carry a "0x"
+// prefix, as from the Ethereum-compatible JSON-RPC, and the address may be
+// the bare 20-byte hash nodes return or any form DecodeAddress accepts.
+func (l *EventLog) UnmarshalJSON(data []byte) error {
+	var j eventLogJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	var out EventLog
+	raw, err := decodeLogAddress(j.Address)
+	if err != nil {
+		return fmt.Errorf("log address: %w", err)
+	}
+	out.Address = encodeAddress(raw)
+	for i, t := range j.Topics {
+		topic, err := hex.DecodeString(strings.TrimPrefix(t, "0x"))
+		if err != nil || len(topic) != 32 {
+			return fmt.Errorf("log topic %d is not 32 bytes of hex", i)
+		}
+		out.Topics = append(out.Topics, topic)
+	}
+	if out.Data, err = hex.DecodeString(strings.TrimPrefix(j.Data, "0x")); err != nil {
+		return fmt.Errorf("log data: %w", err)
+	}
+	*l = out
+	return nil
+}
+
+// decodeLogAddress accepts a 20-byte hex account hash as well as full
+// TRON addresses.
+func decodeLogAddress(s string) ([]byte, error) {
+	if h := strings.TrimPrefix(s, "0x"); len(h) == 2*(AddressLength-1) {
+		raw, err := hex.DecodeString(h)
+		if err != nil {
+			return nil, ErrInvalidAddress
+		}
+		return append([]byte{addressPrefix}, raw...), nil
+	}
+	return DecodeAddress(s)
+}
+
+// ParseTransactionInfoLogs returns the logs of a /wallet/gettransactioninfobyid
+// response. Transactions without logs return none.
+func ParseTransactionInfoLogs(data []byte) ([]EventLog, error) {
+	var info struct {
+		Log []EventLog `json:"log"`
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, err
+	}
+	return info.Log, nil
+}
+
+// EventArg is a decoded event field. Indexed fields of dynamic types, such
+// as string and bytes, are logged as the Keccak-256 of their value, which
+// is returned as a 32-byte []byte.
+type EventArg struct {
+	Name    string
+	Type    string
+	Indexed bool
+	Value   any
+}
+
+// Event is a log decoded against an ABI event definition. Arg values use
+// the types DecodeABI returns, so addresses are Base58 strings.
+type Event struct {
+	// Address is the contract that emitted the event.
+	Address   string
+	Name      string
+	Signature string
+	Args      []EventArg
+}
+
+// Value returns the value of the named field.
+func (e *Event) Value(name string) (any, bool) {
+	for _, a := range e.Args {
+		if a.Name == name {
+			return a.Value, true
+		}
+	}
+	return nil, false
+}
+
+// EventTopic returns the first topic of logs of the event: the Keccak-256
+// of its signature.
+func (e ABIEntry) EventTopic() []byte {
+	return keccak256([]byte(e.Signature()))
+}
+
+// DecodeEventLog decodes log with the first event in entries whose topic
+// and number of indexed fields match it, such as TRC20Events. Anonymous
+// events have no topic of their own and are tried last. It returns
+// ErrUnknownEvent when none match and ErrABIData when the matching event's
+// fields do not decode. Logs do not prove which contract emitted them was
+// the one expected; check Event.Address.
+func DecodeEventLog(entries []ABIEntry, log EventLog) (*Event, error) {
+	for _, anonymous := range []bool{false, true} {
+		for _, e := range entries {
+			if e.Type != "event" || e.Anonymous != anonymous {
+				continue
+			}
+			topics := log.Topics
+			if !anonymous {
+				if len(topics) == 0 || !bytes.Equal(topics[0], e.EventTopic()) {
+					continue
+				}
+				topics = topics[1:]
+			}
+			indexed := 0
+			for _, p := range e.Inputs {
+				if p.Indexed {
+					indexed++
+				}
+			}
+			if indexed != len(topics) {
+				continue
+			}
+			ev, err := decodeEvent(e, log.Address, topics, log.Data)
+			if err != nil && anonymous {
+				continue
+			}
+			return ev, err
+		}
+	}
+	return nil, ErrUnknownEvent
+}
+
+// decodeEvent decodes indexed fields from topics and the others from data.
+func decodeEvent(e ABIEntry, address string, topics [][]byte, data []byte) (*Event, error) {
+	ev := &Event{Address: address, Name: e.Name, Signature: e.Signature()}
+	var unindexed []ABIParam
+	for _, p := range e.Inputs {
+		if !p.Indexed {
+			unindexed = append(unindexed, p)
+		}
+	}
+	values, err := DecodeABI(unindexed, data)
+	if err != nil {
+		return nil, fmt.Errorf("event %s: %w", ev.Signature, err)
+	}
+	for _, p := range e.Inputs {
+		arg := EventArg{Name: p.Name, Type: p.Type, Indexed: p.Indexed}
+		if p.Indexed {
+			t, err := parseABIType(p.Type, p.Components)
+			if err != nil {
+				return nil, err
+			}
+			topic := topics[0]
+			topics = topics[1:]
+			if t.dynamic() || t.kind == abiArray || t.kind == abiTuple {
+				arg.Value = append([]byte(nil), topic...)
+			} else if arg.Value, err = t.decode(topic); err != nil {
+				return nil, fmt.Errorf("event %s field %s: %w", ev.Signature, p.Name, err)
+			}
+		} else {
+			arg.Value, values = values[0], values[1:]
+		}
+		ev.Args = append(ev.Args, arg)
+	}
+	return ev, nil
+}
@@ -0,0 +1,165 @@
+package tronwallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testTransactionInfo is a /wallet/gettransactioninfobyid response for a
+// 1.5 USDT transfer from account 0 to account 1 of testMnemonic.
+const testTransactionInfo = `{
+	"id": "00",
+	"contract_address": "41a614f803b6fd780986a42c78ec9c7f77e6ded13c",
+	"log": [{
+		"address": "a614f803b6fd780986a42c78ec9c7f77e6ded13c",
+		"topics": [
+			"ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+			"000000000000000000000000c8599111f29c1e1e061265b4af93ea1f274ad78a",
+			"000000000000000000000000b6e708a39781c96bd399c7657780ff9fe9f052a8"
+		],
+		"data": "000000000000000000000000000000000000000000000000000000000016e360"
+	}]
+}`
+
+func TestDecodeEventLog_TRC20Transfer(t *testing.T) {
+	logs, err := ParseTransactionInfoLogs([]byte(testTransactionInfo))
+	if err != nil || len(logs) != 1 {
+		t.Fatalf("ParseTransactionInfoLogs = %v, %v", logs, err)
+	}
+	if logs[0].Address != testUSDT {
+		t.Fatalf("log address = %s", logs[0].Address)
+	}
+	ev, err := DecodeEventLog(TRC20Events, logs[0])
+	if err != nil {
+		t.Fatalf("DecodeEventLog error: %v", err)
+	}
+	want := &Event{
+		Address:   testUSDT,
+		Name:      "Transfer",
+		Signature: "Transfer(address,address,uint256)",
+		Args: []EventArg{
+			{Name: "from", Type: "address", Indexed: true, Value: "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH"},
+			{Name: "to", Type: "address", Indexed: true, Value: "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK"},
+			{Name: "value", Type: "uint256", Value: big.NewInt(1_500_000)},
+		},
+	}
+	if !reflect.DeepEqual(ev, want) {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+	if v, ok := ev.Value("to"); !ok || v != "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK" {
+		t.Fatalf("Value(to) = %v, %v", v, ok)
+	}
+	if _, ok := ev.Value("memo"); ok {
+		t.Fatalf("Value of a missing field should not be found")
+	}
+
+	// The TRC20 Transfer has three topics, so TRC721 definitions do not match.
+	if _, err := DecodeEventLog(TRC721Events, logs[0]); !errors.Is(err, ErrUnknownEvent) {
+		t.Fatalf("expected ErrUnknownEvent, got %v", err)
+	}
+	if _, err := ParseTransactionInfoLogs([]byte(`{"id":"00"}`)); err != nil {
+		t.Fatalf("transaction info without logs: %v", err)
+	}
+}
+
+func TestDecodeEventLog_TRC721(t *testing.T) {
+	topic := func(s string) []byte {
+		b, _ := hex.DecodeString(strings.Repeat("0", 64-len(s)) + s)
+		return b
+	}
+	transfer := EventLog{
+		Address: testUSDT,
+		Topics:  [][]byte{TRC721Events[0].EventTopic(), topic("c8599111f29c1e1e061265b4af93ea1f274ad78a"), topic("b6e708a39781c96bd399c7657780ff9fe9f052a8"), topic("7")},
+	}
+	ev, err := DecodeEventLog(append(append([]ABIEntry(nil), TRC20Events...), TRC721Events...), transfer)
+	if err != nil {
+		t.Fatalf("DecodeEventLog error: %v", err)
+	}
+	if id, _ := ev.Value("tokenId"); id.(*big.Int).Int64() != 7 || !ev.Args[2].Indexed {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+
+	approval := EventLog{
+		Topics: [][]byte{TRC721Events[2].EventTopic(), topic("c8599111f29c1e1e061265b4af93ea1f274ad78a"), topic("b6e708a39781c96bd399c7657780ff9fe9f052a8")},
+		Data:   topic("1"),
+	}
+	if ev, err = DecodeEventLog(TRC721Events, approval); err != nil || ev.Name != "ApprovalForAll" || ev.Args[2].Value != true {
+		t.Fatalf("DecodeEventLog = %+v, %v", ev, err)
+	}
+
+	// A matching event with malformed fields is reported, not skipped.
+	approval.Data = topic("2")
+	if _, err := DecodeEventLog(TRC721Events, approval); !errors.Is(err, ErrABIData) {
+		t.Fatalf("expected ErrABIData, got %v", err)
+	}
+	transfer.Topics[1] = topic(strings.Repeat("f", 64))
+	if _, err := DecodeEventLog(TRC721Events, transfer); !errors.Is(err, ErrABIData) {
+		t.Fatalf("expected ErrABIData for a dirty address topic, got %v", err)
+	}
+	if _, err := DecodeEventLog(TRC721Events, EventLog{}); !errors.Is(err, ErrUnknownEvent) {
+		t.Fatalf("expected ErrUnknownEvent for a log without topics, got %v", err)
+	}
+}
+
+func TestDecodeEventLog_IndexedDynamicAndAnonymous(t *testing.T) {
+	entries, err := ParseABI([]byte(`[
+		{"type":"event","name":"Named","inputs":[{"name":"key","type":"string","indexed":true},{"name":"value","type":"string"}]},
+		{"type":"event","name":"Anon","anonymous":true,"inputs":[{"name":"who","type":"address","indexed":true},{"name":"amount","type":"uint64"}]}
+	]`))
+	if err != nil {
+		t.Fatalf("ParseABI error: %v", err)
+	}
+	data, _ := EncodeABI([]ABIParam{{Type: "string"}}, "blue")
+	keyHash := keccak256([]byte("color"))
+	ev, err := DecodeEventLog(entries, EventLog{Topics: [][]byte{entries[0].EventTopic(), keyHash}, Data: data})
+	if err != nil {
+		t.Fatalf("DecodeEventLog error: %v", err)
+	}
+	if key, _ := ev.Value("key"); !bytes.Equal(key.([]byte), keyHash) {
+		t.Fatalf("indexed string should decode to its hash, got %v", key)
+	}
+	if v, _ := ev.Value("value"); v != "blue" {
+		t.Fatalf("value = %v", v)
+	}
+
+	who, _ := abiAddressWord("TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK")
+	amount, _ := EncodeABI([]ABIParam{{Type: "uint64"}}, 9)
+	ev, err = DecodeEventLog(entries, EventLog{Topics: [][]byte{who}, Data: amount})
+	if err != nil || ev.Name != "Anon" || ev.Args[0].Value != "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK" {
+		t.Fatalf("DecodeEventLog = %+v, %v", ev, err)
+	}
+	// Anonymous events that fail to decode are not a match.
+	if _, err := DecodeEventLog(entries, EventLog{Topics: [][]byte{who}}); !errors.Is(err, ErrUnknownEvent) {
+		t.Fatalf("expected ErrUnknownEvent, got %v", err)
+	}
+}
+
+func TestEventLog_UnmarshalJSON(t *testing.T) {
+	var l EventLog
+	good := `{"address":"0xa614f803b6fd780986a42c78ec9c7f77e6ded13c","topics":["0x` + strings.Repeat("00", 32) + `"],"data":"0x01"}`
+	if err := l.UnmarshalJSON([]byte(good)); err != nil || l.Address != testUSDT || len(l.Topics) != 1 || !bytes.Equal(l.Data, []byte{1}) {
+		t.Fatalf("UnmarshalJSON = %+v, %v", l, err)
+	}
+	if err := l.UnmarshalJSON([]byte(`{"address":"` + testUSDT + `"}`)); err != nil || l.Address != testUSDT {
+		t.Fatalf("Base58 log address: %+v, %v", l, err)
+	}
+	for _, bad := range []string{
+		`[`,
+		`{"address":"zz14f803b6fd780986a42c78ec9c7f77e6ded13c"}`,
+		`{"address":"nope"}`,
+		`{"address":"a614f803b6fd780986a42c78ec9c7f77e6ded13c","topics":["00"]}`,
+		`{"address":"a614f803b6fd780986a42c78ec9c7f77e6ded13c","data":"zz"}`,
+	} {
+		if err := l.UnmarshalJSON([]byte(bad)); err == nil {
+			t.Fatalf("UnmarshalJSON(%s): expected error", bad)
+		}
+	}
+	if _, err := ParseTransactionInfoLogs([]byte(`{"log":[{"address":"nope"}]}`)); err == nil {
+		t.Fatalf("expected error for a bad log")
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ryanbekhen/tronwallet"
+)
+
+// This example runs a signing daemon for one account of the wallet whose
+// mnemonic is read from the TRONWALLET_MNEMONIC environment variable. Clients
+// connect with tronwallet.DialRemoteSigner using the same socket path.
+func main() {
+	socket := flag.String("socket", "tronwallet-signer.sock", "Unix socket path")
+	index := flag.Uint("index", 0, "account index to serve")
+	flag.Parse()
+
+	w, err := tronwallet.RestoreWallet(os.Getenv("TRONWALLET_MNEMONIC"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	account, err := w.Account(uint32(*index))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	srv := tronwallet.NewSignerServer(account)
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		srv.Close()
+	}()
+
+	fmt.Println("Serving", account.Address(), "on", *socket)
+	if err := srv.ListenAndServe(*socket); err != nil {
+		log.Fatal(err)
+	}
+}
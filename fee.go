@@ -0,0 +1,255 @@
+package tronwallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Per-transaction bandwidth overheads added by java-tron on top of the
+// serialized transaction: each contract reserves room for its result, and
+// each 65-byte signature costs two bytes of protobuf framing.
+const (
+	resultBandwidth    = 64
+	signatureBandwidth = 67
+)
+
+// ChainParameters are the network settings that price resources, as
+// returned by a node's /wallet/getchainparameters. All fees are in sun.
+type ChainParameters struct {
+	// TransactionFee is burned per byte of bandwidth not covered by staked
+	// or free bandwidth.
+	TransactionFee int64
+	// EnergyFee is burned per unit of energy not covered by staked energy.
+	EnergyFee int64
+	// CreateAccountFee is burned instead of bandwidth when a transaction
+	// activates a new account and the sender has too little staked bandwidth.
+	CreateAccountFee int64
+	// CreateNewAccountFeeInSystemContract is charged on top of the
+	// bandwidth whenever a transfer activates a new account.
+	CreateNewAccountFeeInSystemContract int64
+	// MemoFee is charged for transactions with a non-empty memo.
+	MemoFee int64
+	// MultiSignFee is charged for transactions with more than one signature.
+	MultiSignFee int64
+	// UpdateAccountPermissionFee is charged for AccountPermissionUpdate.
+	UpdateAccountPermissionFee int64
+	// FreeNetLimit is the free bandwidth each account gets per day.
+	FreeNetLimit int64
+	// MaxFeeLimit is the largest fee_limit the network accepts.
+	MaxFeeLimit int64
+}
+
+// MainnetChainParameters are mainnet's values in late 2024. Committee
+// proposals change them; fetch the live values with
+// /wallet/getchainparameters and ParseChainParameters when it matters.
+var MainnetChainParameters = ChainParameters{
+	TransactionFee:                      1000,
+	EnergyFee:                           210,
+	CreateAccountFee:                    100_000,
+	CreateNewAccountFeeInSystemContract: 1_000_000,
+	MemoFee:                             1_000_000,
+	MultiSignFee:                        1_000_000,
+	UpdateAccountPermissionFee:          100_000_000,
+	FreeNetLimit:                        600,
+	MaxFeeLimit:                         15_000_000_000,
+}
+
+// chainParameterKeys maps node parameter names to ChainParameters fields.
+var chainParameterKeys = map[string]func(*ChainParameters) *int64{
+	"getTransactionFee":                      func(p *ChainParameters) *int64 { return &p.TransactionFee },
+	"getEnergyFee":                           func(p *ChainParameters) *int64 { return &p.EnergyFee },
+	"getCreateAccountFee":                    func(p *ChainParameters) *int64 { return &p.CreateAccountFee },
+	"getCreateNewAccountFeeInSystemContract": func(p *ChainParameters) *int64 { return &p.CreateNewAccountFeeInSystemContract },
+	"getMemoFee":                             func(p *ChainParameters) *int64 { return &p.MemoFee },
+	"getMultiSignFee":                        func(p *ChainParameters) *int64 { return &p.MultiSignFee },
+	"getUpdateAccountPermissionFee":          func(p *ChainParameters) *int64 { return &p.UpdateAccountPermissionFee },
+	"getFreeNetLimit":                        func(p *ChainParameters) *int64 { return &p.FreeNetLimit },
+	"getMaxFeeLimit":                         func(p *ChainParameters) *int64 { return &p.MaxFeeLimit },
+}
+
+// ParseChainParameters reads the JSON response of /wallet/getchainparameters.
+// Parameters missing from the response, which the node omits when zero, are
+// left at zero.
+func ParseChainParameters(data []byte) (ChainParameters, error) {
+	var resp struct {
+		ChainParameter []struct {
+			Key   string `json:"key"`
+			Value int64  `json:"value"`
+		} `json:"chainParameter"`
+	}
+	var p ChainParameters
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return p, err
+	}
+	if resp.ChainParameter == nil {
+		return p, errors.New("response has no chainParameter list")
+	}
+	for _, kv := range resp.ChainParameter {
+		if field, ok := chainParameterKeys[kv.Key]; ok {
+			*field(&p) = kv.Value
+		}
+	}
+	return p, nil
+}
+
+// AccountResources is the sender's remaining resources for the day, from
+// /wallet/getaccountresource: freeNetLimit - freeNetUsed, NetLimit - NetUsed
+// and EnergyLimit - EnergyUsed.
+type AccountResources struct {
+	FreeBandwidth   int64
+	StakedBandwidth int64
+	StakedEnergy    int64
+}
+
+// FeeOptions describes what EstimateFee cannot learn from the transaction.
+type FeeOptions struct {
+	// Signatures is the number of signatures the transaction will carry,
+	// such as the threshold of a multisig permission. It defaults to the
+	// signatures already present, or one.
+	Signatures int
+	// Resources is the sender's remaining bandwidth and energy. The zero
+	// value assumes none, so every byte and unit of energy is burned.
+	Resources AccountResources
+	// AccountExists reports whether the recipient of a TRX or TRC10
+	// transfer is already activated. If nil, recipients are assumed to
+	// exist.
+	AccountExists func(address string) (bool, error)
+	// Energy is the estimated energy of a smart contract call or
+	// deployment, from /wallet/estimateenergy or
+	// /wallet/triggerconstantcontract. The caller is assumed to pay all of
+	// it.
+	Energy int64
+}
+
+// FeeEstimate is the predicted cost of a transaction. Fees are in sun.
+type FeeEstimate struct {
+	Bandwidth int64
+	// BandwidthFee is burned for bandwidth not covered by the account's
+	// staked or free bandwidth.
+	BandwidthFee int64
+	// NewAccount is set when the transaction activates its recipient, and
+	// ActivationFee is the extra cost of doing so.
+	NewAccount    bool
+	ActivationFee int64
+	// MemoBandwidth is the part of Bandwidth taken by the memo, and MemoFee
+	// the flat fee charged for having one.
+	MemoBandwidth int64
+	MemoFee       int64
+	MultiSignFee  int64
+	// ContractFee is a flat fee charged by the contract type itself, such
+	// as UpdateAccountPermissionFee.
+	ContractFee int64
+	Energy      int64
+	// EnergyFee is burned for energy not covered by staked energy.
+	EnergyFee int64
+	// FeeLimitTooLow is set when EnergyFee exceeds the transaction's
+	// fee_limit, so the call would run out of energy and fail.
+	FeeLimitTooLow bool
+	// Total is the TRX burned or charged, in sun.
+	Total int64
+}
+
+// EstimateBandwidth returns the bandwidth java-tron charges for tx once it
+// carries the given number of signatures: its serialized size plus 64 bytes
+// per contract and 67 bytes per signature still to be added.
+func EstimateBandwidth(tx *Transaction, signatures int) (int64, error) {
+	raw, err := tx.Raw()
+	if err != nil {
+		return 0, err
+	}
+	size := int64(len(tx.Marshal()) + resultBandwidth*len(raw.Contracts))
+	if missing := signatures - len(tx.Signatures); missing > 0 {
+		size += int64(missing * signatureBandwidth)
+	}
+	return size, nil
+}
+
+// EstimateFee predicts the TRX tx will burn under params, following
+// java-tron's resource rules: staked bandwidth is used first, then free
+// bandwidth, then TRX is burned. A transfer that activates a new account
+// uses staked bandwidth or burns CreateAccountFee instead, and always pays
+// CreateNewAccountFeeInSystemContract.
+func EstimateFee(tx *Transaction, params ChainParameters, opts FeeOptions) (*FeeEstimate, error) {
+	raw, err := tx.Raw()
+	if err != nil {
+		return nil, err
+	}
+	if len(raw.Contracts) != 1 {
+		return nil, fmt.Errorf("%w: expected one contract, got %d", ErrInvalidTransaction, len(raw.Contracts))
+	}
+	if opts.Energy < 0 {
+		return nil, errors.New("energy must not be negative")
+	}
+	signatures := opts.Signatures
+	if signatures == 0 {
+		signatures = max(len(tx.Signatures), 1)
+	}
+	e := &FeeEstimate{}
+	if e.Bandwidth, err = EstimateBandwidth(tx, signatures); err != nil {
+		return nil, err
+	}
+	res := opts.Resources
+
+	var to string
+	switch c := raw.Contracts[0].Parameter.(type) {
+	case *TransferContract:
+		to = c.ToAddress
+	case *TransferAssetContract:
+		to = c.ToAddress
+	}
+	if to != "" && opts.AccountExists != nil {
+		exists, err := opts.AccountExists(to)
+		if err != nil {
+			return nil, err
+		}
+		e.NewAccount = !exists
+	}
+
+	switch {
+	case e.NewAccount:
+		if res.StakedBandwidth < e.Bandwidth {
+			e.ActivationFee = params.CreateAccountFee
+		}
+		e.ActivationFee += params.CreateNewAccountFeeInSystemContract
+	case res.StakedBandwidth >= e.Bandwidth, res.FreeBandwidth >= e.Bandwidth:
+	default:
+		e.BandwidthFee = e.Bandwidth * params.TransactionFee
+	}
+	if len(raw.Data) > 0 {
+		var w protoWriter
+		w.bytes(10, raw.Data)
+		e.MemoBandwidth = int64(len(w.buf))
+		e.MemoFee = params.MemoFee
+	}
+	if signatures > 1 {
+		e.MultiSignFee = params.MultiSignFee
+	}
+
+	switch raw.Contracts[0].Parameter.ContractType() {
+	case AccountPermissionUpdateContractType:
+		e.ContractFee = params.UpdateAccountPermissionFee
+	case CreateSmartContractType, TriggerSmartContractType:
+		e.Energy = opts.Energy
+		if burned := e.Energy - res.StakedEnergy; burned > 0 {
+			e.EnergyFee = burned * params.EnergyFee
+		}
+		e.FeeLimitTooLow = e.EnergyFee > raw.FeeLimit
+	}
+	e.Total = e.BandwidthFee + e.ActivationFee + e.MemoFee + e.MultiSignFee + e.ContractFee + e.EnergyFee
+	return e, nil
+}
+
+// SuggestFeeLimit returns a fee_limit covering energy at params.EnergyFee
+// with marginPercent headroom for state changes between estimation and
+// execution, capped at params.MaxFeeLimit when that is set.
+func SuggestFeeLimit(energy int64, params ChainParameters, marginPercent int64) (int64, error) {
+	if energy < 0 || marginPercent < 0 {
+		return 0, errors.New("energy and margin must not be negative")
+	}
+	limit := energy * params.EnergyFee * (100 + marginPercent) / 100
+	if params.MaxFeeLimit > 0 && limit > params.MaxFeeLimit {
+		limit = params.MaxFeeLimit
+	}
+	return limit, nil
+}
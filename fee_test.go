@@ -0,0 +1,167 @@
+package tronwallet
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func testTransfer(t *testing.T) *Transaction {
+	t.Helper()
+	tx, err := NewTransferTransaction(testTxOptions(), "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", 1_000_000)
+	if err != nil {
+		t.Fatalf("NewTransferTransaction error: %v", err)
+	}
+	return tx
+}
+
+func TestEstimateBandwidth(t *testing.T) {
+	tx := testTransfer(t)
+	// 136 bytes serialized + 64 result + 67 per signature
+	for sigs, want := range map[int]int64{0: 200, 1: 267, 2: 334} {
+		if got, err := EstimateBandwidth(tx, sigs); err != nil || got != want {
+			t.Fatalf("%d signatures: got %d, %v want %d", sigs, got, err, want)
+		}
+	}
+	w, _ := RestoreWallet(testMnemonic)
+	priv, _ := w.Derive(0)
+	if err := tx.SignWithKey(priv); err != nil {
+		t.Fatalf("SignWithKey error: %v", err)
+	}
+	if got, _ := EstimateBandwidth(tx, 1); got != 267 {
+		t.Fatalf("signed transaction: got %d want 267", got)
+	}
+	if got, _ := EstimateBandwidth(tx, 0); got != 267 {
+		t.Fatalf("existing signatures must be counted, got %d", got)
+	}
+	if _, err := EstimateBandwidth(&Transaction{RawData: []byte{0xff}}, 1); err == nil {
+		t.Fatalf("expected error for invalid raw data")
+	}
+}
+
+func TestEstimateFee_Transfer(t *testing.T) {
+	tx := testTransfer(t)
+	activated := func(string) (bool, error) { return true, nil }
+	fresh := func(addr string) (bool, error) {
+		if addr != "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK" {
+			t.Fatalf("unexpected lookup of %s", addr)
+		}
+		return false, nil
+	}
+	cases := []struct {
+		name       string
+		opts       FeeOptions
+		total      int64
+		newAccount bool
+	}{
+		{"burn", FeeOptions{}, 267_000, false},
+		{"free bandwidth", FeeOptions{Resources: AccountResources{FreeBandwidth: 600}, AccountExists: activated}, 0, false},
+		{"staked bandwidth", FeeOptions{Resources: AccountResources{StakedBandwidth: 267}}, 0, false},
+		{"multisig", FeeOptions{Signatures: 2, Resources: AccountResources{FreeBandwidth: 600}}, 1_000_000, false},
+		{"multisig burn", FeeOptions{Signatures: 2, Resources: AccountResources{FreeBandwidth: 300}}, 1_334_000, false},
+		{"new account", FeeOptions{Resources: AccountResources{FreeBandwidth: 600}, AccountExists: fresh}, 1_100_000, true},
+		{"new account staked", FeeOptions{Resources: AccountResources{StakedBandwidth: 5000}, AccountExists: fresh}, 1_000_000, true},
+	}
+	for _, c := range cases {
+		e, err := EstimateFee(tx, MainnetChainParameters, c.opts)
+		if err != nil {
+			t.Fatalf("%s: EstimateFee error: %v", c.name, err)
+		}
+		if e.Total != c.total {
+			t.Fatalf("%s: total %d want %d (%+v)", c.name, e.Total, c.total, e)
+		}
+		if e.NewAccount != c.newAccount {
+			t.Fatalf("%s: unexpected NewAccount %v", c.name, e.NewAccount)
+		}
+	}
+
+	lookupErr := errors.New("node unreachable")
+	if _, err := EstimateFee(tx, MainnetChainParameters, FeeOptions{AccountExists: func(string) (bool, error) { return false, lookupErr }}); !errors.Is(err, lookupErr) {
+		t.Fatalf("expected lookup error, got %v", err)
+	}
+	if _, err := EstimateFee(tx, MainnetChainParameters, FeeOptions{Energy: -1}); err == nil {
+		t.Fatalf("expected error for negative energy")
+	}
+	if _, err := EstimateFee(&Transaction{RawData: []byte{0xff}}, MainnetChainParameters, FeeOptions{}); err == nil {
+		t.Fatalf("expected error for invalid raw data")
+	}
+	empty, _ := (&TransactionRaw{Expiration: 1}).Marshal()
+	if _, err := EstimateFee(&Transaction{RawData: empty}, MainnetChainParameters, FeeOptions{}); !errors.Is(err, ErrInvalidTransaction) {
+		t.Fatalf("expected ErrInvalidTransaction, got %v", err)
+	}
+}
+
+func TestEstimateFee_MemoAndEnergy(t *testing.T) {
+	raw, _ := testTransfer(t).Raw()
+	raw.Data = []byte("invoice 42")
+	data, _ := raw.Marshal()
+	e, err := EstimateFee(&Transaction{RawData: data}, MainnetChainParameters, FeeOptions{Resources: AccountResources{FreeBandwidth: 600}})
+	if err != nil || e.MemoFee != 1_000_000 || e.Total != 1_000_000 {
+		t.Fatalf("unexpected memo estimate %+v, %v", e, err)
+	}
+
+	opts := testTxOptions()
+	opts.FeeLimit = 5_000_000
+	call, _ := NewTRC20TransferTransaction(opts, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", testUSDT, 0, "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", big.NewInt(1))
+	// TRC20 recipients are never activated by the transfer itself.
+	never := func(string) (bool, error) { t.Fatalf("unexpected account lookup"); return false, nil }
+	e, err = EstimateFee(call, MainnetChainParameters, FeeOptions{
+		Energy:        30_000,
+		Resources:     AccountResources{FreeBandwidth: 600, StakedEnergy: 10_000},
+		AccountExists: never,
+	})
+	if err != nil {
+		t.Fatalf("EstimateFee error: %v", err)
+	}
+	if e.Energy != 30_000 || e.EnergyFee != 4_200_000 || e.FeeLimitTooLow || e.Total != 4_200_000 {
+		t.Fatalf("unexpected energy estimate %+v", e)
+	}
+	e, _ = EstimateFee(call, MainnetChainParameters, FeeOptions{Energy: 30_000})
+	if !e.FeeLimitTooLow || e.EnergyFee != 6_300_000 {
+		t.Fatalf("expected fee limit warning, got %+v", e)
+	}
+}
+
+func TestParseChainParameters(t *testing.T) {
+	resp := `{"chainParameter":[
+		{"key":"getMaintenanceTimeInterval","value":21600000},
+		{"key":"getCreateAccountFee","value":100000},
+		{"key":"getTransactionFee","value":1000},
+		{"key":"getCreateNewAccountFeeInSystemContract","value":1000000},
+		{"key":"getEnergyFee","value":210},
+		{"key":"getMemoFee","value":1000000},
+		{"key":"getMultiSignFee","value":1000000},
+		{"key":"getUpdateAccountPermissionFee","value":100000000},
+		{"key":"getFreeNetLimit","value":600},
+		{"key":"getMaxFeeLimit","value":15000000000},
+		{"key":"getAllowTvmFreeze"}
+	]}`
+	p, err := ParseChainParameters([]byte(resp))
+	if err != nil {
+		t.Fatalf("ParseChainParameters error: %v", err)
+	}
+	if p != MainnetChainParameters {
+		t.Fatalf("unexpected parameters %+v", p)
+	}
+	if _, err := ParseChainParameters([]byte(`{}`)); err == nil {
+		t.Fatalf("expected error for missing list")
+	}
+	if _, err := ParseChainParameters([]byte(`[`)); err == nil {
+		t.Fatalf("expected JSON error")
+	}
+}
+
+func TestSuggestFeeLimit(t *testing.T) {
+	if got, err := SuggestFeeLimit(65_000, MainnetChainParameters, 20); err != nil || got != 16_380_000 {
+		t.Fatalf("got %d, %v want 16380000", got, err)
+	}
+	if got, _ := SuggestFeeLimit(1_000_000_000, MainnetChainParameters, 0); got != MainnetChainParameters.MaxFeeLimit {
+		t.Fatalf("expected cap at MaxFeeLimit, got %d", got)
+	}
+	if got, _ := SuggestFeeLimit(1_000_000_000, ChainParameters{EnergyFee: 100}, 0); got != 100_000_000_000 {
+		t.Fatalf("expected no cap without MaxFeeLimit, got %d", got)
+	}
+	if _, err := SuggestFeeLimit(-1, MainnetChainParameters, 0); err == nil {
+		t.Fatalf("expected error for negative energy")
+	}
+}
@@ -0,0 +1,343 @@
+package tronwallet
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math/bits"
+)
+
+// maxFountainMessageLen bounds the message size a FountainDecoder accepts, so
+// a hostile first part cannot make it allocate without limit. java-tron
+// rejects transactions over 500 KB, so real payloads stay well below it.
+const maxFountainMessageLen = 1 << 20
+
+// maxFountainSeqLen bounds the number of fragments of a message. Each mixed
+// part selects up to this many fragments, so it also bounds the work and
+// memory spent on a single part.
+const maxFountainSeqLen = 4096
+
+// maxFountainMixedParts bounds the mixed parts a FountainDecoder keeps while
+// waiting for their fragments. The oldest are dropped first; a later part
+// can always stand in for them.
+const maxFountainMixedParts = 1024
+
+// maxFountainMixedSize bounds the bytes of data and fragment indexes held
+// by the waiting mixed parts, counting each index as 8 bytes, so large or
+// high-degree parts cannot exhaust memory within maxFountainMixedParts.
+const maxFountainMixedSize = 8 << 20
+
+// Errors returned by FountainDecoder.
+var (
+	ErrFountainPart     = errors.New("invalid fountain part")
+	ErrFountainMismatch = errors.New("fountain part belongs to a different message")
+	ErrFountainChecksum = errors.New("fountain message checksum mismatch")
+	ErrFountainPending  = errors.New("fountain message is not complete")
+)
+
+// FountainPart is one part of a message split by a FountainEncoder. Parts
+// 1 to SeqLen carry one fragment each; later parts XOR a pseudo-random
+// selection of fragments, so a receiver can recover from any large enough
+// set of parts, whichever ones it missed.
+type FountainPart struct {
+	SeqNum     uint32
+	SeqLen     uint32
+	MessageLen uint32
+	// Checksum is the CRC-32 (IEEE) of the whole message.
+	Checksum uint32
+	Data     []byte
+}
+
+// FountainEncoder splits a message into an endless sequence of fountain
+// code parts, as in the BC-UR multipart format: fragments are mixed with a
+// Luby transform whose degrees follow the ideal soliton distribution,
+// chosen by a xoshiro256** generator seeded from the part number and
+// message checksum.
+type FountainEncoder struct {
+	fragments [][]byte
+	checksum  uint32
+	length    uint32
+	seqNum    uint32
+}
+
+// NewFountainEncoder splits message into fragments of at most
+// maxFragmentLen bytes. The fragment length is then evened out so the last
+// fragment needs little padding.
+func NewFountainEncoder(message []byte, maxFragmentLen int) (*FountainEncoder, error) {
+	if len(message) == 0 || len(message) > maxFountainMessageLen {
+		return nil, fmt.Errorf("message must be 1 to %d bytes", maxFountainMessageLen)
+	}
+	if maxFragmentLen <= 0 {
+		return nil, errors.New("fragment length must be positive")
+	}
+	count := (len(message) + maxFragmentLen - 1) / maxFragmentLen
+	if count > maxFountainSeqLen {
+		return nil, fmt.Errorf("message needs %d fragments, more than %d; use a larger fragment length", count, maxFountainSeqLen)
+	}
+	fragmentLen := (len(message) + count - 1) / count
+	padded := make([]byte, count*fragmentLen)
+	copy(padded, message)
+	e := &FountainEncoder{checksum: crc32.ChecksumIEEE(message), length: uint32(len(message))}
+	for i := 0; i < count; i++ {
+		e.fragments = append(e.fragments, padded[i*fragmentLen:(i+1)*fragmentLen])
+	}
+	return e, nil
+}
+
+// SeqLen returns the number of fragments. At least this many parts are
+// needed to decode the message.
+func (e *FountainEncoder) SeqLen() int { return len(e.fragments) }
+
+// NextPart returns the next part in sequence, starting from part 1.
+func (e *FountainEncoder) NextPart() FountainPart {
+	e.seqNum++
+	return e.Part(e.seqNum)
+}
+
+// Part returns the part with the given sequence number (from 1).
+func (e *FountainEncoder) Part(seqNum uint32) FountainPart {
+	data := make([]byte, len(e.fragments[0]))
+	for _, i := range fountainIndexes(seqNum, uint32(len(e.fragments)), e.checksum) {
+		xorBytes(data, e.fragments[i])
+	}
+	return FountainPart{
+		SeqNum:     seqNum,
+		SeqLen:     uint32(len(e.fragments)),
+		MessageLen: e.length,
+		Checksum:   e.checksum,
+		Data:       data,
+	}
+}
+
+// fountainIndexes returns the fragments mixed into part seqNum.
+func fountainIndexes(seqNum, seqLen, checksum uint32) []int {
+	if seqNum >= 1 && seqNum <= seqLen {
+		return []int{int(seqNum - 1)}
+	}
+	var seed [8]byte
+	binary.BigEndian.PutUint32(seed[:4], seqNum)
+	binary.BigEndian.PutUint32(seed[4:], checksum)
+	rng := newXoshiro256(sha256.Sum256(seed[:]))
+
+	n := int(seqLen)
+	var total float64
+	for i := 1; i <= n; i++ {
+		total += 1 / float64(i)
+	}
+	degree, r := n, rng.float()*total
+	for i := 1; i <= n; i++ {
+		if r -= 1 / float64(i); r < 0 {
+			degree = i
+			break
+		}
+	}
+	// A Fisher-Yates shuffle of 0..n-1 stopped after degree swaps. Only the
+	// swapped positions are stored, so a part costs O(degree) memory.
+	swapped := make(map[int]int, 2*degree)
+	at := func(i int) int {
+		if v, ok := swapped[i]; ok {
+			return v
+		}
+		return i
+	}
+	indexes := make([]int, degree)
+	for i := range indexes {
+		j := i + int(rng.float()*float64(n-i))
+		indexes[i], swapped[j] = at(j), at(i)
+	}
+	return indexes
+}
+
+// xoshiro256 is the xoshiro256** generator, used so encoder and decoder
+// derive the same fragment selection on any platform.
+type xoshiro256 [4]uint64
+
+func newXoshiro256(seed [32]byte) *xoshiro256 {
+	var s xoshiro256
+	for i := range s {
+		s[i] = binary.BigEndian.Uint64(seed[8*i:])
+	}
+	return &s
+}
+
+func (s *xoshiro256) next() uint64 {
+	result := bits.RotateLeft64(s[1]*5, 7) * 9
+	t := s[1] << 17
+	s[2] ^= s[0]
+	s[3] ^= s[1]
+	s[1] ^= s[2]
+	s[0] ^= s[3]
+	s[2] ^= t
+	s[3] = bits.RotateLeft64(s[3], 45)
+	return result
+}
+
+// float returns a uniform value in [0, 1).
+func (s *xoshiro256) float() float64 {
+	return float64(s.next()>>11) / (1 << 53)
+}
+
+func xorBytes(dst, src []byte) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
+
+// mixedPart is a received part that still combines several unknown
+// fragments.
+type mixedPart struct {
+	seqNum  uint32
+	indexes []int
+	data    []byte
+}
+
+// size approximates the memory held by a waiting mixed part.
+func (m mixedPart) size() int { return len(m.data) + 8*len(m.indexes) }
+
+// FountainDecoder reassembles a message from fountain parts received in any
+// order, with duplicates and gaps. It peels known fragments out of mixed
+// parts until every fragment is recovered.
+type FountainDecoder struct {
+	// params holds SeqLen, MessageLen and Checksum of the first part.
+	params      *FountainPart
+	fragmentLen int
+	fragments   [][]byte
+	known       int
+	mixed       []mixedPart
+	mixedSize   int
+	message     []byte
+	err         error
+}
+
+// NewFountainDecoder returns an empty decoder.
+func NewFountainDecoder() *FountainDecoder {
+	return &FountainDecoder{}
+}
+
+// Receive adds a part. Parts of a different message are rejected with
+// ErrFountainMismatch; repeated parts and parts received after completion
+// are ignored.
+func (d *FountainDecoder) Receive(p FountainPart) error {
+	if p.SeqNum == 0 || p.SeqLen == 0 || p.SeqLen > maxFountainSeqLen || p.MessageLen == 0 || p.MessageLen > maxFountainMessageLen ||
+		len(p.Data) == 0 || (p.MessageLen+uint32(len(p.Data))-1)/uint32(len(p.Data)) != p.SeqLen {
+		return ErrFountainPart
+	}
+	if d.params == nil {
+		d.params = &FountainPart{SeqLen: p.SeqLen, MessageLen: p.MessageLen, Checksum: p.Checksum}
+		d.fragmentLen = len(p.Data)
+		d.fragments = make([][]byte, p.SeqLen)
+	} else if p.SeqLen != d.params.SeqLen || p.MessageLen != d.params.MessageLen || p.Checksum != d.params.Checksum || len(p.Data) != d.fragmentLen {
+		return ErrFountainMismatch
+	}
+	if d.Complete() || d.waiting(p.SeqNum) {
+		return d.err
+	}
+
+	// A repeated part whose fragments are all known reduces to nothing, so
+	// only the waiting mixed parts need to be checked for duplicates.
+	part := mixedPart{seqNum: p.SeqNum, indexes: fountainIndexes(p.SeqNum, p.SeqLen, p.Checksum), data: append([]byte(nil), p.Data...)}
+	queue := []mixedPart{part}
+	for len(queue) > 0 {
+		part, queue = d.reduce(queue[0]), queue[1:]
+		switch len(part.indexes) {
+		case 0:
+		case 1:
+			i := part.indexes[0]
+			d.fragments[i] = part.data
+			d.known++
+			// Peel the new fragment out of the waiting mixed parts.
+			var waiting []mixedPart
+			for _, m := range d.mixed {
+				if containsIndex(m.indexes, i) {
+					queue = append(queue, m)
+					d.mixedSize -= m.size()
+				} else {
+					waiting = append(waiting, m)
+				}
+			}
+			d.mixed = waiting
+		default:
+			d.mixed = append(d.mixed, part)
+			d.mixedSize += part.size()
+			for len(d.mixed) > maxFountainMixedParts || d.mixedSize > maxFountainMixedSize {
+				d.mixedSize -= d.mixed[0].size()
+				d.mixed = d.mixed[1:]
+			}
+		}
+	}
+	if d.known == len(d.fragments) {
+		d.finish()
+	}
+	return d.err
+}
+
+// reduce removes the already known fragments from part.
+func (d *FountainDecoder) reduce(part mixedPart) mixedPart {
+	var left []int
+	for _, i := range part.indexes {
+		if d.fragments[i] != nil {
+			xorBytes(part.data, d.fragments[i])
+		} else {
+			left = append(left, i)
+		}
+	}
+	part.indexes = left
+	return part
+}
+
+// waiting reports whether part seqNum is among the waiting mixed parts.
+func (d *FountainDecoder) waiting(seqNum uint32) bool {
+	for _, m := range d.mixed {
+		if m.seqNum == seqNum {
+			return true
+		}
+	}
+	return false
+}
+
+func containsIndex(indexes []int, i int) bool {
+	for _, j := range indexes {
+		if j == i {
+			return true
+		}
+	}
+	return false
+}
+
+// finish joins the fragments and checks the message checksum.
+func (d *FountainDecoder) finish() {
+	var msg []byte
+	for _, f := range d.fragments {
+		msg = append(msg, f...)
+	}
+	msg = msg[:d.params.MessageLen]
+	if crc32.ChecksumIEEE(msg) != d.params.Checksum {
+		d.err = ErrFountainChecksum
+	}
+	d.message = msg
+	d.mixed, d.mixedSize = nil, 0
+}
+
+// Complete reports whether every fragment has been recovered.
+func (d *FountainDecoder) Complete() bool { return d.message != nil }
+
+// Progress returns the fraction of fragments recovered so far, from 0 to 1.
+func (d *FountainDecoder) Progress() float64 {
+	if d.params == nil {
+		return 0
+	}
+	return float64(d.known) / float64(len(d.fragments))
+}
+
+// Message returns the reassembled message once Complete reports true.
+func (d *FountainDecoder) Message() ([]byte, error) {
+	if !d.Complete() {
+		return nil, ErrFountainPending
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return d.message, nil
+}
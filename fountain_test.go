@@ -0,0 +1,185 @@
+package tronwallet
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestXoshiro256_ReferenceOutput(t *testing.T) {
+	// first outputs of the reference xoshiro256** for state {1, 2, 3, 4}
+	s := xoshiro256{1, 2, 3, 4}
+	for i, want := range []uint64{11520, 0, 1509978240, 1215971899390074240} {
+		if got := s.next(); got != want {
+			t.Fatalf("output %d: got %d want %d", i, got, want)
+		}
+	}
+}
+
+func testFountainMessage(n int) []byte {
+	msg := make([]byte, n)
+	for i := range msg {
+		msg[i] = byte(i*7 + i/256)
+	}
+	return msg
+}
+
+func TestFountain_RecoversFromMixedPartsOnly(t *testing.T) {
+	msg := testFountainMessage(1000)
+	e, err := NewFountainEncoder(msg, 64)
+	if err != nil {
+		t.Fatalf("NewFountainEncoder error: %v", err)
+	}
+	if e.SeqLen() != 16 || len(e.Part(1).Data) != 63 {
+		t.Fatalf("unexpected split: %d fragments of %d bytes", e.SeqLen(), len(e.Part(1).Data))
+	}
+	// Skip every simple part, as if the camera started late.
+	d := NewFountainDecoder()
+	seq := uint32(e.SeqLen())
+	for !d.Complete() {
+		seq++
+		if seq > 20*uint32(e.SeqLen()) {
+			t.Fatalf("not decoded after %d parts, progress %.2f", seq, d.Progress())
+		}
+		if err := d.Receive(e.Part(seq)); err != nil {
+			t.Fatalf("Receive error: %v", err)
+		}
+	}
+	got, err := d.Message()
+	if err != nil || !bytes.Equal(got, msg) {
+		t.Fatalf("message mismatch: %v", err)
+	}
+	if err := d.Receive(e.Part(seq + 1)); err != nil {
+		t.Fatalf("parts after completion should be ignored, got %v", err)
+	}
+}
+
+func TestFountain_BoundsMixedParts(t *testing.T) {
+	e, _ := NewFountainEncoder(testFountainMessage(1100), 1)
+	d := NewFountainDecoder()
+	seq := uint32(e.SeqLen())
+	for i := 0; i < 2*maxFountainMixedParts; i++ {
+		seq++
+		if err := d.Receive(e.Part(seq)); err != nil {
+			t.Fatalf("Receive error: %v", err)
+		}
+	}
+	if len(d.mixed) != maxFountainMixedParts {
+		t.Fatalf("expected %d waiting parts, got %d", maxFountainMixedParts, len(d.mixed))
+	}
+	last := d.mixed[len(d.mixed)-1]
+	if err := d.Receive(e.Part(last.seqNum)); err != nil || len(d.mixed) != maxFountainMixedParts || d.mixed[len(d.mixed)-1].seqNum != last.seqNum {
+		t.Fatalf("a repeated waiting part should be ignored: %v", err)
+	}
+
+	// Dropped parts do not prevent decoding once the simple parts arrive.
+	for i := uint32(1); i <= uint32(e.SeqLen()); i++ {
+		d.Receive(e.Part(i))
+	}
+	if got, err := d.Message(); err != nil || !bytes.Equal(got, testFountainMessage(1100)) {
+		t.Fatalf("message mismatch: %v", err)
+	}
+}
+
+func TestFountain_BoundsMixedSize(t *testing.T) {
+	// Large mixed parts that never reduce to a single fragment.
+	const seqLen, fragmentLen = 64, maxFountainMessageLen / 64
+	d := NewFountainDecoder()
+	for seq := uint32(seqLen + 1); len(d.mixed) < maxFountainMixedParts && seq < 4*maxFountainMixedParts; seq++ {
+		if len(fountainIndexes(seq, seqLen, 0)) < 2 {
+			continue
+		}
+		p := FountainPart{SeqNum: seq, SeqLen: seqLen, MessageLen: maxFountainMessageLen, Data: make([]byte, fragmentLen)}
+		if err := d.Receive(p); err != nil {
+			t.Fatalf("Receive error: %v", err)
+		}
+	}
+	size := 0
+	for _, m := range d.mixed {
+		size += m.size()
+	}
+	if size != d.mixedSize || size > maxFountainMixedSize || len(d.mixed) < maxFountainMixedSize/(2*fragmentLen) {
+		t.Fatalf("%d waiting parts hold %d bytes (tracked %d)", len(d.mixed), size, d.mixedSize)
+	}
+}
+
+func TestFountain_LossyInOrderStream(t *testing.T) {
+	msg := testFountainMessage(300)
+	e, _ := NewFountainEncoder(msg, 50)
+	d := NewFountainDecoder()
+	if d.Progress() != 0 {
+		t.Fatalf("expected no progress before the first part")
+	}
+	if _, err := d.Message(); !errors.Is(err, ErrFountainPending) {
+		t.Fatalf("expected ErrFountainPending, got %v", err)
+	}
+	for i := 0; !d.Complete(); i++ {
+		p := e.NextPart()
+		if i%3 == 1 {
+			continue // dropped frame
+		}
+		if err := d.Receive(p); err != nil {
+			t.Fatalf("Receive error: %v", err)
+		}
+		if err := d.Receive(p); err != nil {
+			t.Fatalf("duplicate part should be ignored, got %v", err)
+		}
+	}
+	if d.Progress() != 1 {
+		t.Fatalf("expected full progress, got %v", d.Progress())
+	}
+	if got, _ := d.Message(); !bytes.Equal(got, msg) {
+		t.Fatalf("message mismatch")
+	}
+}
+
+func TestFountain_Errors(t *testing.T) {
+	if _, err := NewFountainEncoder(nil, 10); err == nil {
+		t.Fatalf("expected error for empty message")
+	}
+	if _, err := NewFountainEncoder([]byte("x"), 0); err == nil {
+		t.Fatalf("expected error for zero fragment length")
+	}
+	if _, err := NewFountainEncoder(testFountainMessage(maxFountainSeqLen+1), 1); err == nil {
+		t.Fatalf("expected error for too many fragments")
+	}
+
+	e, _ := NewFountainEncoder(testFountainMessage(100), 30)
+	good := e.Part(1)
+	bad := []FountainPart{
+		{SeqNum: 0, SeqLen: good.SeqLen, MessageLen: good.MessageLen, Data: good.Data},
+		{SeqNum: 1, SeqLen: 0, MessageLen: good.MessageLen, Data: good.Data},
+		{SeqNum: 1, SeqLen: good.SeqLen, MessageLen: 0, Data: good.Data},
+		{SeqNum: 1, SeqLen: good.SeqLen, MessageLen: maxFountainMessageLen + 1, Data: good.Data},
+		{SeqNum: 1, SeqLen: good.SeqLen, MessageLen: good.MessageLen},
+		{SeqNum: 1, SeqLen: good.SeqLen + 1, MessageLen: good.MessageLen, Data: good.Data},
+		{SeqNum: 1, SeqLen: maxFountainSeqLen + 1, MessageLen: maxFountainSeqLen + 1, Data: []byte{1}},
+	}
+	for i, p := range bad {
+		if err := NewFountainDecoder().Receive(p); !errors.Is(err, ErrFountainPart) {
+			t.Fatalf("case %d: expected ErrFountainPart, got %v", i, err)
+		}
+	}
+
+	d := NewFountainDecoder()
+	if err := d.Receive(good); err != nil {
+		t.Fatalf("Receive error: %v", err)
+	}
+	other, _ := NewFountainEncoder(testFountainMessage(101), 30)
+	if err := d.Receive(other.Part(2)); !errors.Is(err, ErrFountainMismatch) {
+		t.Fatalf("expected ErrFountainMismatch, got %v", err)
+	}
+
+	// A corrupted fragment is caught by the message checksum.
+	d = NewFountainDecoder()
+	for seq := uint32(1); seq <= good.SeqLen; seq++ {
+		p := e.Part(seq)
+		if seq == 2 {
+			p.Data[0] ^= 1
+		}
+		d.Receive(p)
+	}
+	if _, err := d.Message(); !errors.Is(err, ErrFountainChecksum) {
+		t.Fatalf("expected ErrFountainChecksum, got %v", err)
+	}
+}
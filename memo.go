@@ -0,0 +1,174 @@
+package tronwallet
+
+import (
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+// MaxMemoSize is the largest memo, in bytes, that NewTransaction accepts.
+// java-tron itself only bounds the whole transaction, but every memo byte
+// costs bandwidth, so longer memos are almost always a mistake.
+const MaxMemoSize = 1024
+
+const (
+	// encryptedMemoPrefix marks memos written by EncryptMemo, followed by
+	// base64url of ephemeral public key(33) || nonce(24) || ciphertext.
+	encryptedMemoPrefix = "twmemo1:"
+	encryptedMemoInfo   = "tronwallet memo v1"
+	memoNonceSize       = chacha20poly1305.NonceSizeX
+)
+
+var (
+	// ErrMemoTooLarge is returned for memos longer than MaxMemoSize.
+	ErrMemoTooLarge = fmt.Errorf("memo is longer than %d bytes", MaxMemoSize)
+	// ErrMemoNotEncrypted is returned by DecryptMemo for plain memos.
+	ErrMemoNotEncrypted = errors.New("memo is not encrypted")
+	// ErrMemoDecrypt is returned when an encrypted memo was not encrypted
+	// to the given key or was modified.
+	ErrMemoDecrypt = errors.New("memo: wrong key or tampered data")
+)
+
+// ParseMemo converts user input to memo bytes: "0x"-prefixed input is
+// decoded as hex and anything else is taken as UTF-8 text.
+func ParseMemo(s string) ([]byte, error) {
+	var memo []byte
+	if h, ok := strings.CutPrefix(s, "0x"); ok {
+		var err error
+		if memo, err = hex.DecodeString(h); err != nil {
+			return nil, fmt.Errorf("invalid hex memo: %w", err)
+		}
+	} else {
+		if !utf8.ValidString(s) {
+			return nil, errors.New("memo is not valid UTF-8")
+		}
+		memo = []byte(s)
+	}
+	if len(memo) > MaxMemoSize {
+		return nil, ErrMemoTooLarge
+	}
+	return memo, nil
+}
+
+// FormatMemo renders memo bytes for display: printable UTF-8 as text and
+// anything else, or text that itself starts with "0x", as "0x"-prefixed hex,
+// so that ParseMemo(FormatMemo(m)) returns m.
+func FormatMemo(memo []byte) string {
+	if isPrintableMemo(memo) && !strings.HasPrefix(string(memo), "0x") {
+		return string(memo)
+	}
+	if len(memo) == 0 {
+		return ""
+	}
+	return "0x" + hex.EncodeToString(memo)
+}
+
+func isPrintableMemo(memo []byte) bool {
+	if !utf8.Valid(memo) {
+		return false
+	}
+	for _, r := range string(memo) {
+		if !unicode.IsPrint(r) && r != '\n' && r != '\t' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsEncryptedMemo reports whether memo was produced by EncryptMemo.
+func IsEncryptedMemo(memo []byte) bool {
+	return strings.HasPrefix(string(memo), encryptedMemoPrefix)
+}
+
+// EncryptMemo encrypts plaintext so only the holder of recipient's private
+// key can read it. The scheme is ECIES over secp256k1: an ephemeral key
+// agrees on a secret with recipient, HKDF-SHA256 derives the key and
+// XChaCha20-Poly1305 seals the text. The result is printable, so explorers
+// show it as text, and adds about 100 bytes to the memo.
+//
+// A TRON address does not reveal its public key; recover it from a
+// transaction the recipient signed with RecoverPublicKey.
+func EncryptMemo(recipient *ecdsa.PublicKey, plaintext []byte) ([]byte, error) {
+	if recipient == nil || recipient.X == nil || recipient.Y == nil {
+		return nil, errors.New("invalid recipient public key")
+	}
+	pub, err := secp256k1.ParsePubKey(pubKeyUncompressed(recipient))
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient public key: %w", err)
+	}
+	eph, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	ephPub := eph.PubKey().SerializeCompressed()
+	aead, err := memoCipher(secp256k1.GenerateSharedSecret(eph, pub), ephPub, pub)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, memoNonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	payload := append(append(append([]byte(nil), ephPub...), nonce...), aead.Seal(nil, nonce, plaintext, ephPub)...)
+	memo := []byte(encryptedMemoPrefix + base64.RawURLEncoding.EncodeToString(payload))
+	if len(memo) > MaxMemoSize {
+		return nil, ErrMemoTooLarge
+	}
+	return memo, nil
+}
+
+// DecryptMemo opens a memo written by EncryptMemo with the recipient's
+// private key.
+func DecryptMemo(priv *ecdsa.PrivateKey, memo []byte) ([]byte, error) {
+	if !validPrivateKey(priv) {
+		return nil, errInvalidPrivateKey
+	}
+	encoded, ok := strings.CutPrefix(string(memo), encryptedMemoPrefix)
+	if !ok {
+		return nil, ErrMemoNotEncrypted
+	}
+	payload, err := base64.RawURLEncoding.Strict().DecodeString(encoded)
+	if err != nil || len(payload) < 33+memoNonceSize+chacha20poly1305.Overhead {
+		return nil, ErrMemoDecrypt
+	}
+	ephPub, nonce, sealed := payload[:33], payload[33:33+memoNonceSize], payload[33+memoNonceSize:]
+	eph, err := secp256k1.ParsePubKey(ephPub)
+	if err != nil {
+		return nil, ErrMemoDecrypt
+	}
+	key := secp256k1.PrivKeyFromBytes(PrivateKeyToBytes(priv))
+	defer key.Zero()
+	aead, err := memoCipher(secp256k1.GenerateSharedSecret(key, eph), ephPub, key.PubKey())
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, nonce, sealed, ephPub)
+	if err != nil {
+		return nil, ErrMemoDecrypt
+	}
+	return plaintext, nil
+}
+
+// memoCipher derives the memo AEAD from the ECDH secret, binding both
+// public keys into the key derivation.
+func memoCipher(secret, ephPub []byte, recipient *secp256k1.PublicKey) (cipher.AEAD, error) {
+	salt := append(append([]byte(nil), ephPub...), recipient.SerializeCompressed()...)
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, []byte(encryptedMemoInfo)), key); err != nil {
+		return nil, err
+	}
+	return chacha20poly1305.NewX(key)
+}
@@ -0,0 +1,164 @@
+package tronwallet
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseAndFormatMemo(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    []byte
+		display string
+	}{
+		{"12345678", []byte("12345678"), "12345678"},
+		{"Zahlung für Rechnung\n42", []byte("Zahlung für Rechnung\n42"), "Zahlung für Rechnung\n42"},
+		{"0x00ff", []byte{0x00, 0xff}, "0x00ff"},
+		{"0x3078616263", []byte("0xabc"), "0x3078616263"},
+		{"0x07", []byte{0x07}, "0x07"},
+		{"", []byte{}, ""},
+	}
+	for _, c := range cases {
+		got, err := ParseMemo(c.in)
+		if err != nil || !bytes.Equal(got, c.want) {
+			t.Fatalf("ParseMemo(%q) = %x, %v want %x", c.in, got, err, c.want)
+		}
+		if d := FormatMemo(got); d != c.display {
+			t.Fatalf("FormatMemo(%x) = %q want %q", got, d, c.display)
+		}
+	}
+	for _, bad := range []string{"0xzz", "\xff", strings.Repeat("a", MaxMemoSize+1)} {
+		if _, err := ParseMemo(bad); err == nil {
+			t.Fatalf("ParseMemo(%.10q): expected error", bad)
+		}
+	}
+	if _, err := ParseMemo(strings.Repeat("a", MaxMemoSize)); err != nil {
+		t.Fatalf("memo of MaxMemoSize bytes should be accepted: %v", err)
+	}
+}
+
+func TestNewTransaction_Memo(t *testing.T) {
+	opts := testTxOptions()
+	opts.Memo = []byte("deposit 1234")
+	tx, err := NewTransferTransaction(opts, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", 1_000_000)
+	if err != nil {
+		t.Fatalf("NewTransferTransaction error: %v", err)
+	}
+	want := "0a02abcd2208112233445566778840e0a499ffbc31520c6465706f73697420313233345a67080112630a2d747970652e676f6f676c65617069732e636f6d2f70726f746f636f6c2e5472616e73666572436f6e747261637412320a1541c8599111f29c1e1e061265b4af93ea1f274ad78a121541b6e708a39781c96bd399c7657780ff9fe9f052a818c0843d7080d095ffbc31"
+	if got := hex.EncodeToString(tx.RawData); got != want {
+		t.Fatalf("raw data mismatch:\n got %s\nwant %s", got, want)
+	}
+	if memo, err := tx.Memo(); err != nil || string(memo) != "deposit 1234" {
+		t.Fatalf("Memo = %q, %v", memo, err)
+	}
+	if _, err := (&Transaction{RawData: []byte{0xff}}).Memo(); err == nil {
+		t.Fatalf("expected error for invalid raw data")
+	}
+
+	e, err := EstimateFee(tx, MainnetChainParameters, FeeOptions{Resources: AccountResources{FreeBandwidth: 600}})
+	if err != nil || e.MemoBandwidth != 14 || e.Bandwidth != 281 || e.Total != 1_000_000 {
+		t.Fatalf("unexpected memo fee estimate %+v, %v", e, err)
+	}
+
+	opts.Memo = make([]byte, MaxMemoSize+1)
+	if _, err := NewTransferTransaction(opts, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", 1); !errors.Is(err, ErrMemoTooLarge) {
+		t.Fatalf("expected ErrMemoTooLarge, got %v", err)
+	}
+}
+
+func TestEncryptMemo_RoundTrip(t *testing.T) {
+	w, _ := RestoreWallet(testMnemonic)
+	sender, _ := w.Derive(0)
+	recipient, _ := w.Derive(1)
+
+	// The sender learns the recipient's public key from a signed transaction.
+	tx := testTransfer(t)
+	if err := tx.SignWithKey(recipient); err != nil {
+		t.Fatalf("SignWithKey error: %v", err)
+	}
+	pub, err := RecoverPublicKey(tx.ID(), tx.Signatures[0])
+	if err != nil {
+		t.Fatalf("RecoverPublicKey error: %v", err)
+	}
+	memo, err := EncryptMemo(pub, []byte("invoice 42"))
+	if err != nil {
+		t.Fatalf("EncryptMemo error: %v", err)
+	}
+	if !IsEncryptedMemo(memo) || !isPrintableMemo(memo) || strings.Contains(string(memo), "invoice") {
+		t.Fatalf("unexpected encrypted memo %q", memo)
+	}
+	again, _ := EncryptMemo(pub, []byte("invoice 42"))
+	if bytes.Equal(memo, again) {
+		t.Fatalf("encryption must be randomized")
+	}
+	plain, err := DecryptMemo(recipient, memo)
+	if err != nil || string(plain) != "invoice 42" {
+		t.Fatalf("DecryptMemo = %q, %v", plain, err)
+	}
+
+	if _, err := DecryptMemo(sender, memo); !errors.Is(err, ErrMemoDecrypt) {
+		t.Fatalf("expected ErrMemoDecrypt for the wrong key, got %v", err)
+	}
+	tampered := append([]byte(nil), memo...)
+	if i := len(tampered) - 10; tampered[i] == 'A' {
+		tampered[i] = 'B'
+	} else {
+		tampered[i] = 'A'
+	}
+	bad := map[string][]byte{
+		"tampered":  tampered,
+		"truncated": memo[:40],
+		"base64":    []byte(encryptedMemoPrefix + "!!!"),
+		"ephemeral": []byte(encryptedMemoPrefix + strings.Repeat("A", 120)),
+	}
+	for name, m := range bad {
+		if _, err := DecryptMemo(recipient, m); !errors.Is(err, ErrMemoDecrypt) {
+			t.Fatalf("%s: expected ErrMemoDecrypt, got %v", name, err)
+		}
+	}
+	if _, err := DecryptMemo(recipient, []byte("plain")); !errors.Is(err, ErrMemoNotEncrypted) {
+		t.Fatalf("expected ErrMemoNotEncrypted, got %v", err)
+	}
+	if _, err := EncryptMemo(pub, make([]byte, MaxMemoSize)); !errors.Is(err, ErrMemoTooLarge) {
+		t.Fatalf("expected ErrMemoTooLarge, got %v", err)
+	}
+	offCurve := *pub
+	offCurve.Y = offCurve.X
+	if _, err := EncryptMemo(&offCurve, []byte("x")); err == nil {
+		t.Fatalf("expected error for an invalid public key")
+	}
+	if _, err := EncryptMemo(nil, []byte("x")); err == nil {
+		t.Fatalf("expected error for a nil public key")
+	}
+	if _, err := EncryptMemo(&ecdsa.PublicKey{}, []byte("x")); err == nil {
+		t.Fatalf("expected error for an empty public key")
+	}
+	if _, err := DecryptMemo(nil, memo); !errors.Is(err, errInvalidPrivateKey) {
+		t.Fatalf("expected errInvalidPrivateKey for a nil key, got %v", err)
+	}
+	if _, err := DecryptMemo(&ecdsa.PrivateKey{}, memo); !errors.Is(err, errInvalidPrivateKey) {
+		t.Fatalf("expected errInvalidPrivateKey for an empty key, got %v", err)
+	}
+
+	// Summaries do not show ciphertext as if it were a readable memo.
+	opts := testTxOptions()
+	opts.Memo = memo
+	withMemo, _ := NewTransferTransaction(opts, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", 1)
+	s, _ := SummarizeTransaction(withMemo)
+	if out := s.String(); !strings.Contains(out, "Memo:          encrypted, ") {
+		t.Fatalf("unexpected summary:\n%s", out)
+	}
+	s, _ = SummarizeTransaction(testTransfer(t))
+	if strings.Contains(s.String(), "Memo:") {
+		t.Fatalf("summary without memo should have no memo line:\n%s", s)
+	}
+	opts.Memo = []byte{0, 1}
+	withMemo, _ = NewTransferTransaction(opts, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", 1)
+	if s, _ = SummarizeTransaction(withMemo); !strings.Contains(s.String(), "Memo:          0x0001") {
+		t.Fatalf("binary memo should be shown as hex:\n%s", s)
+	}
+}
@@ -0,0 +1,84 @@
+package tronwallet
+
+import (
+	"crypto/ecdsa"
+	"strconv"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// tronMessagePrefix is the TIP-191 prefix used by TronWeb's signMessageV2.
+const tronMessagePrefix = "\x19TRON Signed Message:\n"
+
+// tronMessageHeaderV1 is the fixed header used by TronWeb's legacy
+// trx.sign(hexMessage), which always declares a 32-byte message.
+const tronMessageHeaderV1 = tronMessagePrefix + "32"
+
+// HashMessageV2 returns the TIP-191 digest signed by TronWeb's
+// signMessageV2: Keccak-256 of the prefix, the decimal byte length of the
+// message and the message itself.
+func HashMessageV2(message []byte) []byte {
+	return keccak256([]byte(tronMessagePrefix), []byte(strconv.Itoa(len(message))), message)
+}
+
+// HashMessageV1 returns the digest signed by TronWeb's legacy trx.sign for a
+// hex-encoded message; message holds the decoded bytes.
+func HashMessageV1(message []byte) []byte {
+	return keccak256([]byte(tronMessageHeaderV1), message)
+}
+
+// SignMessageV2 signs message the way TronWeb's signMessageV2 does and
+// returns the 65-byte r || s || v signature. TronWeb prints it as
+// "0x" followed by the hex encoding.
+func SignMessageV2(priv *ecdsa.PrivateKey, message []byte) ([]byte, error) {
+	return SignHash(priv, HashMessageV2(message))
+}
+
+// SignMessageV2With is SignMessageV2 for any Signer.
+func SignMessageV2With(s Signer, message []byte) ([]byte, error) {
+	return s.SignHash(HashMessageV2(message))
+}
+
+// RecoverMessageV2Address returns the address that signed message with
+// signMessageV2, matching TronWeb's verifyMessageV2.
+func RecoverMessageV2Address(message, sig []byte) (string, error) {
+	return RecoverAddress(HashMessageV2(message), sig)
+}
+
+// VerifyMessageV2 checks that sig is a signMessageV2 signature of message by
+// address.
+func VerifyMessageV2(message, sig []byte, address string) error {
+	return VerifySignature(HashMessageV2(message), sig, address)
+}
+
+// SignMessageV1 signs message with the legacy TronWeb trx.sign scheme, where
+// message is the raw bytes of the hex string passed to TronWeb.
+func SignMessageV1(priv *ecdsa.PrivateKey, message []byte) ([]byte, error) {
+	return SignHash(priv, HashMessageV1(message))
+}
+
+// SignMessageV1With is SignMessageV1 for any Signer.
+func SignMessageV1With(s Signer, message []byte) ([]byte, error) {
+	return s.SignHash(HashMessageV1(message))
+}
+
+// RecoverMessageV1Address returns the address that signed message with the
+// legacy trx.sign scheme.
+func RecoverMessageV1Address(message, sig []byte) (string, error) {
+	return RecoverAddress(HashMessageV1(message), sig)
+}
+
+// VerifyMessageV1 checks a legacy trx.sign signature of message by address,
+// matching TronWeb's trx.verifyMessage.
+func VerifyMessageV1(message, sig []byte, address string) error {
+	return VerifySignature(HashMessageV1(message), sig, address)
+}
+
+// keccak256 returns the legacy Keccak-256 digest of the concatenated inputs.
+func keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
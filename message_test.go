@@ -0,0 +1,212 @@
+package tronwallet
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"golang.org/x/crypto/sha3"
+)
+
+func TestKeccak256_EmptyVector(t *testing.T) {
+	if got := hex.EncodeToString(keccak256()); got != "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470" {
+		t.Fatalf("unexpected keccak256 of empty input %s", got)
+	}
+}
+
+func TestHashMessage_Prefixes(t *testing.T) {
+	msg := []byte("hello TRON ✓") // length counts bytes, not runes
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte("\x19TRON Signed Message:\n14hello TRON ✓"))
+	if !bytes.Equal(HashMessageV2(msg), h.Sum(nil)) {
+		t.Fatalf("HashMessageV2 mismatch")
+	}
+
+	raw, _ := hex.DecodeString("deadbeef")
+	h = sha3.NewLegacyKeccak256()
+	h.Write(append([]byte("\x19TRON Signed Message:\n32"), raw...))
+	if !bytes.Equal(HashMessageV1(raw), h.Sum(nil)) {
+		t.Fatalf("HashMessageV1 mismatch")
+	}
+}
+
+func TestSignMessageV2_RoundTrip(t *testing.T) {
+	w, _ := RestoreWallet(testMnemonic)
+	priv, _ := w.Derive(0)
+	addr := TronAddressFromPrivate(priv)
+	msg := []byte("login nonce 8f1c")
+
+	sig, err := SignMessageV2(priv, msg)
+	if err != nil {
+		t.Fatalf("SignMessageV2 error: %v", err)
+	}
+	if len(sig) != 65 || (sig[64] != 27 && sig[64] != 28) {
+		t.Fatalf("unexpected signature %x", sig)
+	}
+	got, err := RecoverMessageV2Address(msg, sig)
+	if err != nil || got != addr {
+		t.Fatalf("RecoverMessageV2Address = %s, %v", got, err)
+	}
+	if err := VerifyMessageV2(msg, sig, addr); err != nil {
+		t.Fatalf("VerifyMessageV2 error: %v", err)
+	}
+	if err := VerifyMessageV2([]byte("login nonce 8f1d"), sig, addr); !errors.Is(err, ErrSignerMismatch) {
+		t.Fatalf("expected ErrSignerMismatch, got %v", err)
+	}
+	// a V1 signature must not verify as V2
+	sigV1, _ := SignMessageV1(priv, msg)
+	if err := VerifyMessageV2(msg, sigV1, addr); !errors.Is(err, ErrSignerMismatch) {
+		t.Fatalf("expected ErrSignerMismatch across schemes, got %v", err)
+	}
+}
+
+func TestSignMessageV1_RoundTrip(t *testing.T) {
+	w, _ := RestoreWallet(testMnemonic)
+	priv, _ := w.Derive(1)
+	addr := TronAddressFromPrivate(priv)
+	msg, _ := hex.DecodeString("0123456789abcdef")
+
+	sig, err := SignMessageV1(priv, msg)
+	if err != nil {
+		t.Fatalf("SignMessageV1 error: %v", err)
+	}
+	got, err := RecoverMessageV1Address(msg, sig)
+	if err != nil || got != addr {
+		t.Fatalf("RecoverMessageV1Address = %s, %v", got, err)
+	}
+	if err := VerifyMessageV1(msg, sig, addr); err != nil {
+		t.Fatalf("VerifyMessageV1 error: %v", err)
+	}
+	if _, err := SignMessageV1(nil, msg); err == nil {
+		t.Fatalf("expected error for nil key")
+	}
+	if _, err := SignMessageV2(nil, msg); err == nil {
+		t.Fatalf("expected error for nil key")
+	}
+}
+
+// TestSignMessage_Vectors checks fixed signatures for the key
+// keccak256("cow"). They were computed outside this package with an
+// independent secp256k1 implementation of TronWeb's scheme, using RFC 6979
+// nonces and low-s signatures as ethers' SigningKey does. The signatures
+// produced by TronWeb itself are checked by TestSignMessage_TronWebVectors.
+func TestSignMessage_Vectors(t *testing.T) {
+	priv := secp256k1.PrivKeyFromBytes(keccak256([]byte("cow"))).ToECDSA()
+	addr := tronAddr(t, "CD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")
+	v1, _ := hex.DecodeString("0123456789abcdef")
+	cases := []struct {
+		name       string
+		hashFn     func([]byte) []byte
+		sign       func(*ecdsa.PrivateKey, []byte) ([]byte, error)
+		verify     func(message, sig []byte, address string) error
+		msg        []byte
+		hash, want string
+	}{
+		{
+			name:   "signMessageV2",
+			hashFn: HashMessageV2,
+			sign:   SignMessageV2,
+			verify: VerifyMessageV2,
+			msg:    []byte("hello world"),
+			hash:   "cf02daeb2bea196ed5692322a66ed50080ce74ff8cb711199f1b04f3c13bc10d",
+			want: "c525991eb013863c28c4f84486d81b1e29db6f2328a28c4d63607e59e784667b" +
+				"789bff0a5aa4def174b6aa3ce3f0c021ff4b932d0d7ea482c7163965d755a537" + "1c",
+		},
+		{
+			name:   "trx.sign",
+			hashFn: HashMessageV1,
+			sign:   SignMessageV1,
+			verify: VerifyMessageV1,
+			msg:    v1,
+			hash:   "a8384528314d5900ed9a60f043f937a974ebc9672e274c1fbfaabefbe96fedf0",
+			want: "b2c7c3b9a2403e23703313091147d6386040af9b54c7e576a51b5c44ebe1a318" +
+				"46fc091da40aaf25d5f14f76891d3b48993177f709184e72d1b89d397bdb58ca" + "1b",
+		},
+	}
+	for _, c := range cases {
+		if got := hex.EncodeToString(c.hashFn(c.msg)); got != c.hash {
+			t.Fatalf("%s: unexpected hash %s", c.name, got)
+		}
+		sig, err := c.sign(priv, c.msg)
+		if err != nil {
+			t.Fatalf("%s: sign error: %v", c.name, err)
+		}
+		if got := hex.EncodeToString(sig); got != c.want {
+			t.Fatalf("%s: unexpected signature %s", c.name, got)
+		}
+		if err := c.verify(c.msg, sig, addr); err != nil {
+			t.Fatalf("%s: verify error: %v", c.name, err)
+		}
+	}
+}
+
+// tronWebVectors is the output of testdata/tronweb/vectors.js.
+type tronWebVectors struct {
+	TronWeb       string `json:"tronweb"`
+	Key           string `json:"key"`
+	SignMessageV2 []struct {
+		Message, Signature string
+	} `json:"signMessageV2"`
+	Sign []struct {
+		Message, Signature string
+	} `json:"sign"`
+	TypedData []struct {
+		Document          json.RawMessage
+		Digest, Signature string
+	} `json:"typedData"`
+}
+
+// loadTronWebVectors reads the signatures TronWeb itself produced. They are
+// generated with "npm install && npm run vectors" in testdata/tronweb, which
+// needs network access, so the test is skipped until vectors.json exists.
+func loadTronWebVectors(t *testing.T) (tronWebVectors, *ecdsa.PrivateKey) {
+	t.Helper()
+	data, err := os.ReadFile("testdata/tronweb/vectors.json")
+	if errors.Is(err, os.ErrNotExist) {
+		t.Skip("testdata/tronweb/vectors.json not generated")
+	}
+	if err != nil {
+		t.Fatalf("read vectors: %v", err)
+	}
+	var v tronWebVectors
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("decode vectors: %v", err)
+	}
+	a, err := AccountFromHex(v.Key)
+	if err != nil {
+		t.Fatalf("vector key: %v", err)
+	}
+	return v, a.PrivateKey()
+}
+
+func TestSignMessage_TronWebVectors(t *testing.T) {
+	v, priv := loadTronWebVectors(t)
+	for _, c := range v.SignMessageV2 {
+		sig, err := SignMessageV2(priv, []byte(c.Message))
+		if err != nil {
+			t.Fatalf("SignMessageV2 error: %v", err)
+		}
+		if got := "0x" + hex.EncodeToString(sig); !strings.EqualFold(got, c.Signature) {
+			t.Fatalf("tronweb %s signMessageV2(%q) = %s, got %s", v.TronWeb, c.Message, c.Signature, got)
+		}
+	}
+	for _, c := range v.Sign {
+		msg, err := hex.DecodeString(c.Message)
+		if err != nil {
+			t.Fatalf("vector message: %v", err)
+		}
+		sig, err := SignMessageV1(priv, msg)
+		if err != nil {
+			t.Fatalf("SignMessageV1 error: %v", err)
+		}
+		if got := "0x" + hex.EncodeToString(sig); !strings.EqualFold(got, c.Signature) {
+			t.Fatalf("tronweb %s trx.sign(%s) = %s, got %s", v.TronWeb, c.Message, c.Signature, got)
+		}
+	}
+}
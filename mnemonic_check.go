@@ -0,0 +1,264 @@
+package tronwallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+// ErrWeakMnemonic is returned by RestoreWallet when WithWeakMnemonicCheck is
+// set and the mnemonic is well known or has low entropy.
+var ErrWeakMnemonic = errors.New("weak or well-known mnemonic")
+
+// MnemonicStrength rates how safe a mnemonic is to hold funds.
+type MnemonicStrength int
+
+const (
+	// MnemonicCompromised means the phrase is publicly known and swept by bots.
+	MnemonicCompromised MnemonicStrength = iota
+	// MnemonicWeak means the phrase has a guessable structure.
+	MnemonicWeak
+	// MnemonicFair means the phrase has somewhat reduced entropy.
+	MnemonicFair
+	// MnemonicStrong means no weakness was detected.
+	MnemonicStrong
+)
+
+// String returns the lower-case name of the strength rating.
+func (s MnemonicStrength) String() string {
+	switch s {
+	case MnemonicCompromised:
+		return "compromised"
+	case MnemonicWeak:
+		return "weak"
+	case MnemonicFair:
+		return "fair"
+	case MnemonicStrong:
+		return "strong"
+	}
+	return fmt.Sprintf("MnemonicStrength(%d)", int(s))
+}
+
+// MnemonicReport is the result of CheckMnemonic.
+type MnemonicReport struct {
+	// KnownVector is true when the phrase is a published test vector or a
+	// widely used development mnemonic.
+	KnownVector bool
+	// RepeatedWords lists words that occur more often than random generation
+	// plausibly produces.
+	RepeatedWords []string
+	// Patterns describes low-entropy structures found in the phrase.
+	Patterns []string
+	// EntropyBits is a conservative estimate of the phrase's entropy.
+	EntropyBits float64
+	// Strength is the overall rating.
+	Strength MnemonicStrength
+}
+
+// Issues returns human-readable descriptions of every problem found.
+func (r *MnemonicReport) Issues() []string {
+	var issues []string
+	if r.KnownVector {
+		issues = append(issues, "mnemonic is a publicly known test vector")
+	}
+	if len(r.RepeatedWords) > 0 {
+		issues = append(issues, "repeated words: "+strings.Join(r.RepeatedWords, ", "))
+	}
+	return append(issues, r.Patterns...)
+}
+
+// knownMnemonicEntropies are the entropies of the BIP39 reference test
+// vectors (English).
+var knownMnemonicEntropies = []string{
+	"00000000000000000000000000000000",
+	"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+	"80808080808080808080808080808080",
+	"ffffffffffffffffffffffffffffffff",
+	"000000000000000000000000000000000000000000000000",
+	"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+	"808080808080808080808080808080808080808080808080",
+	"ffffffffffffffffffffffffffffffffffffffffffffffff",
+	"0000000000000000000000000000000000000000000000000000000000000000",
+	"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+	"8080808080808080808080808080808080808080808080808080808080808080",
+	"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+	"77c2b00716cec7213839159e404db50d",
+	"b63a9c59a6e641f288ebc103017f1da9f8290b3da6bdef7b",
+	"3e141609b97933b66a060dcddc71fad1d91677db872031e85f4c015c5e7e8982",
+	"0460ef47585604c5660618db2e6a7e7f",
+	"72f60ebac5dd8add8d2a25a797102c3ce21bc029c200076f",
+	"2c85efc7f24ee4573d2b81a6ec66cee209b2dcbd09d8eddc51e0215b0b68e416",
+	"eaebabb2383351fd31d703840b32e9e2",
+	"7ac45cfe7722ee6c7ba84fbc2d5bd61b45cb2fe5eb65aa78",
+	"4fa1a8bc3e6d80ee1316050e862c1812031493212b7ec3f3bb1b08f168cabeef",
+	"18ab19a9f54a9274f03e5209a2ac8a91",
+	"18a2e1d81b8ecfb2a333adcb0c17a5b9eb76cc5d05db91a4",
+	"15da872c95a13dd738fbf50e427583ad61f18fd99f628c417a61cf8343c90419",
+}
+
+// knownMnemonicPhrases are development mnemonics shipped as defaults by
+// common tooling.
+var knownMnemonicPhrases = []string{
+	"test test test test test test test test test test test junk",
+}
+
+var (
+	knownMnemonicsOnce sync.Once
+	knownMnemonics     map[string]bool
+)
+
+func isKnownMnemonic(normalized string) bool {
+	knownMnemonicsOnce.Do(func() {
+		knownMnemonics = make(map[string]bool, len(knownMnemonicEntropies)+len(knownMnemonicPhrases))
+		for _, e := range knownMnemonicEntropies {
+			ent, _ := hex.DecodeString(e)
+			if mn, err := bip39.NewMnemonic(ent); err == nil {
+				knownMnemonics[mn] = true
+			}
+		}
+		for _, mn := range knownMnemonicPhrases {
+			knownMnemonics[mn] = true
+		}
+	})
+	return knownMnemonics[normalized]
+}
+
+// CheckMnemonic inspects a valid BIP39 mnemonic for publicly known test
+// vectors, repeated words and low-entropy patterns, and rates its strength.
+func CheckMnemonic(mnemonic string) (*MnemonicReport, error) {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	normalized := strings.Join(words, " ")
+	if !bip39.IsMnemonicValid(normalized) {
+		return nil, errors.New("invalid mnemonic")
+	}
+	entropy, err := bip39.EntropyFromMnemonic(normalized)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &MnemonicReport{EntropyBits: float64(len(entropy) * 8)}
+	lower := func(bits float64) {
+		r.EntropyBits = math.Min(r.EntropyBits, bits)
+	}
+	r.KnownVector = isKnownMnemonic(normalized)
+
+	// Random generation rarely repeats a word more than once, so only flag
+	// triplicates or several distinct duplicates.
+	counts := map[string]int{}
+	for _, w := range words {
+		counts[w]++
+	}
+	manyDuplicates := len(words)-len(counts) > 2
+	for _, w := range words {
+		if c := counts[w]; c >= 3 || (c > 1 && manyDuplicates) {
+			r.RepeatedWords = appendUnique(r.RepeatedWords, w)
+		}
+	}
+	if len(r.RepeatedWords) > 0 {
+		lower(float64(len(counts)) * 11)
+	}
+
+	if p := bytePeriod(entropy); p < len(entropy) {
+		r.Patterns = append(r.Patterns, fmt.Sprintf("entropy repeats every %d byte(s)", p))
+		lower(float64(p) * 8)
+	}
+	if isArithmeticBytes(entropy) {
+		r.Patterns = append(r.Patterns, "entropy bytes form an arithmetic sequence")
+		lower(16)
+	}
+	distinct := map[byte]bool{}
+	for _, b := range entropy {
+		distinct[b] = true
+	}
+	if len(distinct) <= len(entropy)/4 {
+		r.Patterns = append(r.Patterns, fmt.Sprintf("entropy uses only %d distinct byte values", len(distinct)))
+		lower(float64(len(entropy)) * math.Log2(float64(len(distinct))+1))
+	}
+	if isArithmeticWords(words) {
+		r.Patterns = append(r.Patterns, "words follow the word list in sequence")
+		lower(22)
+	}
+
+	switch {
+	case r.KnownVector:
+		r.Strength = MnemonicCompromised
+		r.EntropyBits = 0
+	case r.EntropyBits < 96:
+		r.Strength = MnemonicWeak
+	case r.EntropyBits < 128:
+		r.Strength = MnemonicFair
+	default:
+		r.Strength = MnemonicStrong
+	}
+	return r, nil
+}
+
+// bytePeriod returns the length of the shortest repeating unit of b, or
+// len(b) if b does not repeat.
+func bytePeriod(b []byte) int {
+	for p := 1; p <= len(b)/2; p++ {
+		periodic := true
+		for i := p; i < len(b); i++ {
+			if b[i] != b[i-p] {
+				periodic = false
+				break
+			}
+		}
+		if periodic {
+			return p
+		}
+	}
+	return len(b)
+}
+
+// isArithmeticBytes reports whether consecutive bytes differ by the same
+// non-zero step, such as 00 01 02 03 ...
+func isArithmeticBytes(b []byte) bool {
+	step := b[1] - b[0]
+	if step == 0 {
+		return false
+	}
+	for i := 2; i < len(b); i++ {
+		if b[i]-b[i-1] != step {
+			return false
+		}
+	}
+	return true
+}
+
+// isArithmeticWords reports whether the word-list indexes of all words but
+// the checksum-bearing last one advance by a constant step.
+func isArithmeticWords(words []string) bool {
+	idx := make([]int, 0, len(words)-1)
+	for _, w := range words[:len(words)-1] {
+		i, ok := bip39.GetWordIndex(w)
+		if !ok {
+			return false
+		}
+		idx = append(idx, i)
+	}
+	step := idx[1] - idx[0]
+	if step == 0 {
+		return false
+	}
+	for i := 2; i < len(idx); i++ {
+		if idx[i]-idx[i-1] != step {
+			return false
+		}
+	}
+	return true
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
@@ -0,0 +1,156 @@
+package tronwallet
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+func TestCheckMnemonic_KnownVectors(t *testing.T) {
+	for _, mn := range []string{
+		testMnemonic,
+		"legal winner thank year wave sausage worth useful legal winner thank yellow",
+		"turtle front uncle idea crush write shrug there lottery flower risk shell",
+		"test test test test test test test test test test test junk",
+		"  ABANDON abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about ",
+	} {
+		r, err := CheckMnemonic(mn)
+		if err != nil {
+			t.Fatalf("CheckMnemonic(%q) error: %v", mn, err)
+		}
+		if !r.KnownVector || r.Strength != MnemonicCompromised || r.EntropyBits != 0 {
+			t.Fatalf("expected %q to be compromised, got %+v", mn, r)
+		}
+		if len(r.Issues()) == 0 {
+			t.Fatalf("expected issues for %q", mn)
+		}
+	}
+}
+
+func TestCheckMnemonic_Patterns(t *testing.T) {
+	mnemonicFor := func(entropy []byte) string {
+		mn, err := bip39.NewMnemonic(entropy)
+		if err != nil {
+			t.Fatalf("NewMnemonic error: %v", err)
+		}
+		return mn
+	}
+
+	periodic := make([]byte, 16)
+	for i := range periodic {
+		periodic[i] = []byte{0x12, 0x34, 0x56, 0x78}[i%4]
+	}
+	sequential := make([]byte, 16)
+	for i := range sequential {
+		sequential[i] = byte(i * 3)
+	}
+	sparse := []byte{1, 2, 1, 1, 2, 1, 9, 9, 2, 1, 9, 2, 1, 1, 2, 9}
+
+	for name, entropy := range map[string][]byte{"periodic": periodic, "sequential": sequential, "sparse": sparse} {
+		r, err := CheckMnemonic(mnemonicFor(entropy))
+		if err != nil {
+			t.Fatalf("%s: CheckMnemonic error: %v", name, err)
+		}
+		if r.KnownVector || len(r.Patterns) == 0 || r.Strength != MnemonicWeak {
+			t.Fatalf("%s: expected weak pattern, got %+v", name, r)
+		}
+	}
+
+	// word indexes 0, 5, 10, ... for the first 11 words
+	words := bip39.GetWordList()
+	seq := make([]string, 11)
+	for i := range seq {
+		seq[i] = words[i*5]
+	}
+	for _, last := range words {
+		mn := strings.Join(append(seq, last), " ")
+		if !bip39.IsMnemonicValid(mn) {
+			continue
+		}
+		r, err := CheckMnemonic(mn)
+		if err != nil {
+			t.Fatalf("CheckMnemonic error: %v", err)
+		}
+		if r.Strength != MnemonicWeak {
+			t.Fatalf("expected sequential words to be weak, got %+v", r)
+		}
+		break
+	}
+}
+
+func TestCheckMnemonic_RepeatedWordsAndStrong(t *testing.T) {
+	r, err := CheckMnemonic("board flee heavy tunnel powder denial science ski answer betray cargo cat")
+	if err != nil {
+		t.Fatalf("CheckMnemonic error: %v", err)
+	}
+	if !r.KnownVector {
+		t.Fatalf("expected BIP39 vector to be known")
+	}
+
+	w, err := NewWallet(Mnemonic24Words)
+	if err != nil {
+		t.Fatalf("NewWallet error: %v", err)
+	}
+	r, err = CheckMnemonic(w.Mnemonic)
+	if err != nil {
+		t.Fatalf("CheckMnemonic error: %v", err)
+	}
+	if r.Strength != MnemonicStrong || r.EntropyBits != 256 {
+		t.Fatalf("expected random mnemonic to be strong, got %+v", r)
+	}
+
+	// find a valid phrase where one word appears three times
+	words := strings.Fields(w.Mnemonic)
+	words[1], words[2] = words[0], words[0]
+	for _, last := range bip39.GetWordList() {
+		words[23] = last
+		mn := strings.Join(words, " ")
+		if !bip39.IsMnemonicValid(mn) {
+			continue
+		}
+		r, err = CheckMnemonic(mn)
+		if err != nil {
+			t.Fatalf("CheckMnemonic error: %v", err)
+		}
+		if len(r.RepeatedWords) == 0 || r.RepeatedWords[0] != words[0] {
+			t.Fatalf("expected repeated word %q, got %+v", words[0], r)
+		}
+		break
+	}
+
+	if _, err := CheckMnemonic("not a mnemonic"); err == nil {
+		t.Fatalf("expected error for invalid mnemonic")
+	}
+}
+
+func TestMnemonicStrength_String(t *testing.T) {
+	want := map[MnemonicStrength]string{
+		MnemonicCompromised:  "compromised",
+		MnemonicWeak:         "weak",
+		MnemonicFair:         "fair",
+		MnemonicStrong:       "strong",
+		MnemonicStrength(42): "MnemonicStrength(42)",
+	}
+	for s, name := range want {
+		if s.String() != name {
+			t.Fatalf("expected %q, got %q", name, s.String())
+		}
+	}
+}
+
+func TestRestoreWallet_WeakMnemonicCheck(t *testing.T) {
+	if _, err := RestoreWallet(testMnemonic); err != nil {
+		t.Fatalf("check must be opt-in, got %v", err)
+	}
+	_, err := RestoreWallet(testMnemonic, WithWeakMnemonicCheck())
+	if !errors.Is(err, ErrWeakMnemonic) {
+		t.Fatalf("expected ErrWeakMnemonic, got %v", err)
+	}
+
+	w, _ := NewWallet()
+	if _, err := RestoreWallet(w.Mnemonic, WithWeakMnemonicCheck()); err != nil {
+		t.Fatalf("random mnemonic rejected: %v", err)
+	}
+}
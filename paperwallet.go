@@ -0,0 +1,267 @@
+package tronwallet
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Paper wallet pages are laid out on A4 in PostScript points.
+const (
+	paperWidth       = 595
+	paperHeight      = 842
+	paperMargin      = 40
+	paperAccountH    = 170
+	paperQRModule    = 3
+	paperDefaultName = "TRON Paper Wallet"
+)
+
+// PaperWalletOptions controls what a paper wallet contains.
+type PaperWalletOptions struct {
+	// Title is printed at the top of the first page.
+	Title string
+	// IncludePrivateKey adds a QR code and hex text of each account's private
+	// key. Leave it off when the mnemonic alone is the backup.
+	IncludePrivateKey bool
+}
+
+// PaperWalletAccount is one derived account printed on a paper wallet.
+type PaperWalletAccount struct {
+	Index   uint32
+	Path    string
+	Address string
+
+	privateKeyHex string
+}
+
+// PaperWallet is a printable cold-storage backup of a wallet: the mnemonic in
+// a numbered word grid followed by the address (and optionally private key)
+// QR codes of the selected accounts.
+type PaperWallet struct {
+	Title    string
+	Words    []string
+	Accounts []PaperWalletAccount
+
+	includePrivateKey bool
+}
+
+// NewPaperWallet derives the accounts at indexes from w and prepares them for
+// rendering with SVG or PDF.
+func NewPaperWallet(w *TronWallet, indexes []uint32, opts PaperWalletOptions) (*PaperWallet, error) {
+	if w == nil || w.Mnemonic == "" {
+		return nil, errors.New("paper wallet requires a mnemonic wallet")
+	}
+	if len(indexes) == 0 {
+		return nil, errors.New("paper wallet requires at least one account index")
+	}
+	p := &PaperWallet{
+		Title:             opts.Title,
+		Words:             strings.Fields(w.Mnemonic),
+		includePrivateKey: opts.IncludePrivateKey,
+	}
+	if p.Title == "" {
+		p.Title = paperDefaultName
+	}
+	for _, idx := range indexes {
+		priv, err := w.Derive(idx)
+		if err != nil {
+			return nil, err
+		}
+		p.Accounts = append(p.Accounts, PaperWalletAccount{
+			Index:         idx,
+			Path:          TronDerivationPath(idx),
+			Address:       TronAddressFromPrivate(priv),
+			privateKeyHex: PrivateKeyToHex(priv),
+		})
+	}
+	return p, nil
+}
+
+// paperItem is a single drawing primitive. Coordinates use a top-left origin
+// on the page.
+type paperItem struct {
+	x, y  float64
+	size  float64 // font size, or module size for QR codes
+	mono  bool
+	text  string
+	qr    *QRCode
+	lineW float64 // horizontal rule width when > 0
+}
+
+// layout places every element on A4 pages.
+func (p *PaperWallet) layout() ([][]paperItem, error) {
+	var pages [][]paperItem
+	var page []paperItem
+	y := float64(paperMargin)
+
+	page = append(page,
+		paperItem{x: paperMargin, y: y + 18, size: 20, text: p.Title},
+		paperItem{x: paperMargin, y: y + 36, size: 9, text: "Keep this page secret. Anyone holding the recovery phrase controls the funds."},
+	)
+	y += 60
+
+	page = append(page, paperItem{x: paperMargin, y: y, size: 12, text: fmt.Sprintf("Recovery phrase (%d words)", len(p.Words))})
+	y += 20
+	const cols = 3
+	rows := (len(p.Words) + cols - 1) / cols
+	for i, word := range p.Words {
+		col, row := i/rows, i%rows
+		page = append(page, paperItem{
+			x: paperMargin + float64(col)*170, y: y + float64(row)*18,
+			size: 11, mono: true, text: fmt.Sprintf("%2d. %s", i+1, word),
+		})
+	}
+	y += float64(rows)*18 + 10
+
+	for _, a := range p.Accounts {
+		if y+paperAccountH > paperHeight-paperMargin {
+			pages = append(pages, page)
+			page, y = nil, paperMargin
+		}
+		page = append(page,
+			paperItem{x: paperMargin, y: y, lineW: paperWidth - 2*paperMargin},
+			paperItem{x: paperMargin, y: y + 18, size: 11, text: fmt.Sprintf("Account #%d  %s", a.Index, a.Path)},
+		)
+		addrQR, err := EncodeQR([]byte(a.Address), QRMedium)
+		if err != nil {
+			return nil, err
+		}
+		page = append(page,
+			paperItem{x: paperMargin, y: y + 28, size: paperQRModule, qr: addrQR},
+			paperItem{x: paperMargin, y: y + 28 + float64(addrQR.Size()*paperQRModule) + 14, size: 9, mono: true, text: a.Address},
+		)
+		if p.includePrivateKey {
+			keyQR, err := EncodeQR([]byte(a.privateKeyHex), QRMedium)
+			if err != nil {
+				return nil, err
+			}
+			kx := float64(paperWidth - paperMargin - keyQR.Size()*paperQRModule)
+			page = append(page,
+				paperItem{x: kx - 150, y: y + 40, size: 9, text: "Private key (secret)"},
+				paperItem{x: kx - 150, y: y + 56, size: 7, mono: true, text: a.privateKeyHex[:32]},
+				paperItem{x: kx - 150, y: y + 66, size: 7, mono: true, text: a.privateKeyHex[32:]},
+				paperItem{x: kx, y: y + 28, size: paperQRModule, qr: keyQR},
+			)
+		}
+		y += paperAccountH
+	}
+	return append(pages, page), nil
+}
+
+// SVG renders the paper wallet as a single SVG document with the A4 pages
+// stacked vertically.
+func (p *PaperWallet) SVG() ([]byte, error) {
+	pages, err := p.layout()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%dpt" height="%dpt" viewBox="0 0 %d %d">`,
+		paperWidth, paperHeight*len(pages), paperWidth, paperHeight*len(pages))
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/>`, paperWidth, paperHeight*len(pages))
+	for n, page := range pages {
+		offset := float64(n * paperHeight)
+		for _, it := range page {
+			switch {
+			case it.qr != nil:
+				fmt.Fprintf(&buf, `<path transform="translate(%g %g) scale(%g)" d="%s" fill="#000"/>`,
+					it.x, it.y+offset, it.size, it.qr.svgPath(0, 0))
+			case it.lineW > 0:
+				fmt.Fprintf(&buf, `<line x1="%g" y1="%g" x2="%g" y2="%g" stroke="#999" stroke-width="0.5"/>`,
+					it.x, it.y+offset, it.x+it.lineW, it.y+offset)
+			default:
+				family := "Helvetica, Arial, sans-serif"
+				if it.mono {
+					family = "Courier, monospace"
+				}
+				fmt.Fprintf(&buf, `<text x="%g" y="%g" font-family="%s" font-size="%g" xml:space="preserve">`,
+					it.x, it.y+offset, family, it.size)
+				xml.EscapeText(&buf, []byte(it.text))
+				buf.WriteString("</text>")
+			}
+		}
+	}
+	buf.WriteString("</svg>")
+	return buf.Bytes(), nil
+}
+
+// PDF renders the paper wallet as a PDF 1.4 document using the standard
+// Helvetica and Courier fonts, so no font data is embedded.
+func (p *PaperWallet) PDF() ([]byte, error) {
+	pages, err := p.layout()
+	if err != nil {
+		return nil, err
+	}
+
+	// objects 1-4 are fixed; each page adds a page object and a content stream
+	objects := []string{
+		"", // catalog, filled below
+		"", // page tree, filled below
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>",
+	}
+	var kids []string
+	for _, page := range pages {
+		var cs bytes.Buffer
+		for _, it := range page {
+			y := paperHeight - it.y
+			switch {
+			case it.qr != nil:
+				cs.WriteString("0 g\n")
+				for my := 0; my < it.qr.Size(); my++ {
+					for mx := 0; mx < it.qr.Size(); {
+						if !it.qr.Module(mx, my) {
+							mx++
+							continue
+						}
+						run := 0
+						for it.qr.Module(mx+run, my) {
+							run++
+						}
+						fmt.Fprintf(&cs, "%g %g %g %g re\n", it.x+float64(mx)*it.size, y-float64(my+1)*it.size, float64(run)*it.size, it.size)
+						mx += run
+					}
+				}
+				cs.WriteString("f\n")
+			case it.lineW > 0:
+				fmt.Fprintf(&cs, "0.6 G 0.5 w %g %g m %g %g l S\n", it.x, y, it.x+it.lineW, y)
+			default:
+				font := "/F1"
+				if it.mono {
+					font = "/F2"
+				}
+				fmt.Fprintf(&cs, "0 g BT %s %g Tf %g %g Td (%s) Tj ET\n", font, it.size, it.x, y, pdfEscape(it.text))
+			}
+		}
+		objects = append(objects, fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", cs.Len(), cs.String()))
+		contentRef := len(objects)
+		objects = append(objects, fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			paperWidth, paperHeight, contentRef))
+		kids = append(kids, fmt.Sprintf("%d 0 R", len(objects)))
+	}
+	objects[0] = "<< /Type /Catalog /Pages 2 0 R >>"
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes(), nil
+}
+
+// pdfEscape escapes a string for use in a PDF literal string.
+func pdfEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`).Replace(s)
+}
@@ -0,0 +1,104 @@
+package tronwallet
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNewPaperWallet(t *testing.T) {
+	w, err := RestoreWallet(testMnemonic)
+	if err != nil {
+		t.Fatalf("RestoreWallet error: %v", err)
+	}
+	p, err := NewPaperWallet(w, []uint32{0, 2}, PaperWalletOptions{})
+	if err != nil {
+		t.Fatalf("NewPaperWallet error: %v", err)
+	}
+	if p.Title != paperDefaultName || len(p.Words) != 12 || len(p.Accounts) != 2 {
+		t.Fatalf("unexpected paper wallet %+v", p)
+	}
+	if p.Accounts[0].Address != "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH" || p.Accounts[1].Path != "m/44'/195'/0'/0/2" {
+		t.Fatalf("unexpected accounts %+v", p.Accounts)
+	}
+
+	if _, err := NewPaperWallet(w, nil, PaperWalletOptions{}); err == nil {
+		t.Fatalf("expected error without indexes")
+	}
+	if _, err := NewPaperWallet(&TronWallet{}, []uint32{0}, PaperWalletOptions{}); err == nil {
+		t.Fatalf("expected error without mnemonic")
+	}
+}
+
+func TestPaperWallet_SVG(t *testing.T) {
+	w, _ := RestoreWallet(testMnemonic)
+	priv, _ := w.Derive(0)
+
+	p, _ := NewPaperWallet(w, []uint32{0}, PaperWalletOptions{Title: "Cold <1>"})
+	svg, err := p.SVG()
+	if err != nil {
+		t.Fatalf("SVG error: %v", err)
+	}
+	s := string(svg)
+	for _, want := range []string{"Cold &lt;1&gt;", "12. about", " 1. abandon", "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH"} {
+		if !strings.Contains(s, want) {
+			t.Fatalf("SVG missing %q", want)
+		}
+	}
+	if strings.Contains(s, PrivateKeyToHex(priv)[:32]) {
+		t.Fatalf("SVG contains private key without IncludePrivateKey")
+	}
+	dec := xml.NewDecoder(bytes.NewReader(svg))
+	for {
+		if _, err := dec.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("SVG is not well-formed XML: %v", err)
+		}
+	}
+
+	p, _ = NewPaperWallet(w, []uint32{0}, PaperWalletOptions{IncludePrivateKey: true})
+	svg, _ = p.SVG()
+	if !strings.Contains(string(svg), PrivateKeyToHex(priv)[32:]) {
+		t.Fatalf("SVG missing private key with IncludePrivateKey")
+	}
+}
+
+func TestPaperWallet_PDF(t *testing.T) {
+	w, _ := NewWallet(Mnemonic24Words)
+	indexes := make([]uint32, 8) // spills onto three pages
+	for i := range indexes {
+		indexes[i] = uint32(i)
+	}
+	p, err := NewPaperWallet(w, indexes, PaperWalletOptions{Title: "Vault (A)", IncludePrivateKey: true})
+	if err != nil {
+		t.Fatalf("NewPaperWallet error: %v", err)
+	}
+	pdf, err := p.PDF()
+	if err != nil {
+		t.Fatalf("PDF error: %v", err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatalf("missing PDF header or trailer")
+	}
+	if !bytes.Contains(pdf, []byte(`(Vault \(A\)) Tj`)) || !bytes.Contains(pdf, []byte("/Count 3")) {
+		t.Fatalf("unexpected PDF content")
+	}
+
+	// every xref entry must point at the start of its object
+	m := regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(pdf)
+	xref, _ := strconv.Atoi(string(m[1]))
+	lines := strings.Split(string(pdf[xref:]), "\n")
+	count, _ := strconv.Atoi(strings.Fields(lines[1])[1])
+	for i := 1; i < count; i++ {
+		off, _ := strconv.Atoi(lines[2+i][:10])
+		if !bytes.HasPrefix(pdf[off:], []byte(fmt.Sprintf("%d 0 obj", i))) {
+			t.Fatalf("xref entry %d points at wrong offset", i)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package tronwallet
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// PartialTransactionVersion is the container format version written by
+// PartialTransaction.Marshal.
+const PartialTransactionVersion = 1
+
+// Errors returned by PartialTransaction operations.
+var (
+	ErrPartialVersion       = errors.New("unsupported partial transaction version")
+	ErrPartialMismatch      = errors.New("partial transactions are for different transactions or permissions")
+	ErrNotPermissionKey     = errors.New("address is not a key of the permission")
+	ErrIncompleteSignatures = errors.New("signature weight is below the permission threshold")
+)
+
+// PermissionKey is a key of an account permission and its weight.
+type PermissionKey struct {
+	Address string `json:"address"`
+	Weight  int64  `json:"weight"`
+}
+
+// PartialSignature is a signature collected for a PartialTransaction, with
+// optional metadata about who produced it.
+type PartialSignature struct {
+	Address   string
+	Signature []byte
+	Signer    string
+	SignedAt  time.Time
+}
+
+// PartialTransaction carries a transaction between the signers of an m-of-n
+// account permission, similar in spirit to Bitcoin's PSBT. It records the
+// permission's threshold and key weights so any holder can tell how many
+// more signatures are needed. Signatures are verified whenever they are
+// added, merged or parsed.
+type PartialTransaction struct {
+	RawData      []byte
+	PermissionID int32
+	Threshold    int64
+	Keys         []PermissionKey
+	Signatures   []PartialSignature
+}
+
+// NewPartialTransaction starts collecting signatures for tx under the
+// permission with the given ID, threshold and keys. The permission ID must
+// match the one in tx's contract, since it is part of the signed data.
+// Signatures already on tx are kept if they belong to the permission's keys.
+func NewPartialTransaction(tx *Transaction, permissionID int32, threshold int64, keys []PermissionKey) (*PartialTransaction, error) {
+	p := &PartialTransaction{
+		RawData:      append([]byte(nil), tx.RawData...),
+		PermissionID: permissionID,
+		Threshold:    threshold,
+		Keys:         append([]PermissionKey(nil), keys...),
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	for _, sig := range tx.Signatures {
+		addr, err := RecoverAddress(tx.ID(), sig)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.add(PartialSignature{Address: addr, Signature: sig}); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// validate checks the permission description against the raw data.
+func (p *PartialTransaction) validate() error {
+	raw, err := ParseTransactionRaw(p.RawData)
+	if err != nil {
+		return err
+	}
+	if len(raw.Contracts) != 1 || raw.Contracts[0].PermissionID != p.PermissionID {
+		return fmt.Errorf("%w: transaction is not for permission %d", ErrInvalidTransaction, p.PermissionID)
+	}
+	if p.Threshold <= 0 || len(p.Keys) == 0 {
+		return errors.New("permission needs a positive threshold and at least one key")
+	}
+	var total int64
+	seen := map[string]bool{}
+	for i, k := range p.Keys {
+		raw, err := DecodeAddress(k.Address)
+		if err != nil {
+			return fmt.Errorf("key %d: %w", i, err)
+		}
+		p.Keys[i].Address = encodeAddress(raw)
+		if seen[p.Keys[i].Address] {
+			return fmt.Errorf("duplicate key %s", p.Keys[i].Address)
+		}
+		seen[p.Keys[i].Address] = true
+		if k.Weight <= 0 {
+			return fmt.Errorf("key %s: weight must be positive", k.Address)
+		}
+		total += k.Weight
+	}
+	if total < p.Threshold {
+		return fmt.Errorf("key weights sum to %d, below threshold %d", total, p.Threshold)
+	}
+	return nil
+}
+
+// ID returns the transaction ID being signed.
+func (p *PartialTransaction) ID() []byte {
+	return (&Transaction{RawData: p.RawData}).ID()
+}
+
+// keyWeight returns the weight of address, or 0 if it is not a key.
+func (p *PartialTransaction) keyWeight(address string) int64 {
+	for _, k := range p.Keys {
+		if k.Address == address {
+			return k.Weight
+		}
+	}
+	return 0
+}
+
+// add verifies sig and records it, ignoring repeats from the same key.
+func (p *PartialTransaction) add(sig PartialSignature) error {
+	if p.keyWeight(sig.Address) == 0 {
+		return fmt.Errorf("%w: %s", ErrNotPermissionKey, sig.Address)
+	}
+	if err := VerifySignature(p.ID(), sig.Signature, sig.Address); err != nil {
+		return err
+	}
+	for _, s := range p.Signatures {
+		if s.Address == sig.Address {
+			return nil
+		}
+	}
+	p.Signatures = append(p.Signatures, sig)
+	return nil
+}
+
+// Sign adds s's signature, recording signer as a human-readable name for
+// the other participants. Signing twice with the same key has no effect.
+func (p *PartialTransaction) Sign(s Signer, signer string) error {
+	if p.keyWeight(s.Address()) == 0 {
+		return fmt.Errorf("%w: %s", ErrNotPermissionKey, s.Address())
+	}
+	sig, err := s.SignHash(p.ID())
+	if err != nil {
+		return err
+	}
+	return p.add(PartialSignature{
+		Address:   s.Address(),
+		Signature: sig,
+		Signer:    signer,
+		SignedAt:  transactionNowImpl().UTC(),
+	})
+}
+
+// SignWithKey adds a signature by priv, for example a key returned by
+// TronWallet.Derive.
+func (p *PartialTransaction) SignWithKey(priv *ecdsa.PrivateKey, signer string) error {
+	if !validPrivateKey(priv) {
+		return errInvalidPrivateKey
+	}
+	return p.Sign(NewAccount(priv), signer)
+}
+
+// Merge adds the signatures collected in other, which must be for the same
+// transaction and permission.
+func (p *PartialTransaction) Merge(other *PartialTransaction) error {
+	if !bytes.Equal(p.RawData, other.RawData) || p.PermissionID != other.PermissionID || p.Threshold != other.Threshold || !sameKeys(p.Keys, other.Keys) {
+		return ErrPartialMismatch
+	}
+	for _, sig := range other.Signatures {
+		if err := p.add(sig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sameKeys(a, b []PermissionKey) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	weights := map[string]int64{}
+	for _, k := range a {
+		weights[k.Address] = k.Weight
+	}
+	for _, k := range b {
+		if w, ok := weights[k.Address]; !ok || w != k.Weight {
+			return false
+		}
+	}
+	return true
+}
+
+// Weight returns the total weight of the collected signatures.
+func (p *PartialTransaction) Weight() int64 {
+	var total int64
+	for _, s := range p.Signatures {
+		total += p.keyWeight(s.Address)
+	}
+	return total
+}
+
+// IsComplete reports whether the collected signatures reach the threshold.
+func (p *PartialTransaction) IsComplete() bool {
+	return p.Weight() >= p.Threshold
+}
+
+// Missing returns the keys that have not signed yet.
+func (p *PartialTransaction) Missing() []PermissionKey {
+	var missing []PermissionKey
+	for _, k := range p.Keys {
+		signed := false
+		for _, s := range p.Signatures {
+			signed = signed || s.Address == k.Address
+		}
+		if !signed {
+			missing = append(missing, k)
+		}
+	}
+	return missing
+}
+
+// Finalize returns the signed transaction once the threshold is reached.
+// Signatures are ordered as the permission's keys.
+func (p *PartialTransaction) Finalize() (*Transaction, error) {
+	if !p.IsComplete() {
+		return nil, fmt.Errorf("%w: have %d of %d", ErrIncompleteSignatures, p.Weight(), p.Threshold)
+	}
+	order := map[string]int{}
+	for i, k := range p.Keys {
+		order[k.Address] = i
+	}
+	sigs := append([]PartialSignature(nil), p.Signatures...)
+	sort.Slice(sigs, func(i, j int) bool { return order[sigs[i].Address] < order[sigs[j].Address] })
+	tx := &Transaction{RawData: append([]byte(nil), p.RawData...)}
+	for _, s := range sigs {
+		tx.Signatures = append(tx.Signatures, s.Signature)
+	}
+	return tx, nil
+}
+
+// partialTransactionJSON is the versioned file format of PartialTransaction.
+type partialTransactionJSON struct {
+	Version      int                    `json:"version"`
+	TxID         string                 `json:"tx_id"`
+	RawDataHex   string                 `json:"raw_data_hex"`
+	PermissionID int32                  `json:"permission_id"`
+	Threshold    int64                  `json:"threshold"`
+	Keys         []PermissionKey        `json:"keys"`
+	Signatures   []partialSignatureJSON `json:"signatures"`
+}
+
+type partialSignatureJSON struct {
+	Address   string    `json:"address"`
+	Signature string    `json:"signature"`
+	Signer    string    `json:"signer,omitempty"`
+	SignedAt  time.Time `json:"signed_at,omitzero"`
+}
+
+// Marshal encodes p in the versioned JSON container format.
+func (p *PartialTransaction) Marshal() ([]byte, error) {
+	j := partialTransactionJSON{
+		Version:      PartialTransactionVersion,
+		TxID:         hex.EncodeToString(p.ID()),
+		RawDataHex:   hex.EncodeToString(p.RawData),
+		PermissionID: p.PermissionID,
+		Threshold:    p.Threshold,
+		Keys:         p.Keys,
+		Signatures:   []partialSignatureJSON{},
+	}
+	for _, s := range p.Signatures {
+		j.Signatures = append(j.Signatures, partialSignatureJSON{
+			Address:   s.Address,
+			Signature: hex.EncodeToString(s.Signature),
+			Signer:    s.Signer,
+			SignedAt:  s.SignedAt,
+		})
+	}
+	return json.MarshalIndent(j, "", "  ")
+}
+
+// ParsePartialTransaction decodes a container written by Marshal, checking
+// the version, the transaction ID and every signature.
+func ParsePartialTransaction(data []byte) (*PartialTransaction, error) {
+	var j partialTransactionJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, err
+	}
+	if j.Version != PartialTransactionVersion {
+		return nil, fmt.Errorf("%w: %d", ErrPartialVersion, j.Version)
+	}
+	raw, err := hex.DecodeString(j.RawDataHex)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid raw_data_hex", ErrInvalidTransaction)
+	}
+	p := &PartialTransaction{RawData: raw, PermissionID: j.PermissionID, Threshold: j.Threshold, Keys: j.Keys}
+	if j.TxID != hex.EncodeToString(p.ID()) {
+		return nil, ErrTxIDMismatch
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	for _, s := range j.Signatures {
+		sig, err := hex.DecodeString(s.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid signature encoding", ErrInvalidSignature)
+		}
+		addr, err := DecodeAddress(s.Address)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.add(PartialSignature{Address: encodeAddress(addr), Signature: sig, Signer: s.Signer, SignedAt: s.SignedAt}); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
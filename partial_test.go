@@ -0,0 +1,217 @@
+package tronwallet
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testMultisig returns the first three keys of testMnemonic and a transfer
+// from account 0 signed under active permission 2, a 2-of-3 of those keys.
+func testMultisig(t *testing.T) ([]*ecdsa.PrivateKey, *Transaction, []PermissionKey) {
+	t.Helper()
+	w, err := RestoreWallet(testMnemonic)
+	if err != nil {
+		t.Fatalf("RestoreWallet error: %v", err)
+	}
+	var privs []*ecdsa.PrivateKey
+	var keys []PermissionKey
+	for i := uint32(0); i < 3; i++ {
+		priv, err := w.Derive(i)
+		if err != nil {
+			t.Fatalf("Derive error: %v", err)
+		}
+		privs = append(privs, priv)
+		keys = append(keys, PermissionKey{Address: NewAccount(priv).Address(), Weight: 1})
+	}
+	opts := testTxOptions()
+	opts.PermissionID = 2
+	tx, err := NewTransferTransaction(opts, keys[0].Address, "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", 1_000_000)
+	if err != nil {
+		t.Fatalf("NewTransferTransaction error: %v", err)
+	}
+	return privs, tx, keys
+}
+
+func TestPartialTransaction_SignMergeFinalize(t *testing.T) {
+	privs, tx, keys := testMultisig(t)
+	restore := transactionNowImpl
+	transactionNowImpl = func() time.Time { return time.Unix(1700000000, 0) }
+	defer func() { transactionNowImpl = restore }()
+
+	alice, err := NewPartialTransaction(tx, 2, 2, keys)
+	if err != nil {
+		t.Fatalf("NewPartialTransaction error: %v", err)
+	}
+	if err := alice.SignWithKey(privs[0], "alice"); err != nil {
+		t.Fatalf("SignWithKey error: %v", err)
+	}
+	if alice.IsComplete() || alice.Weight() != 1 || len(alice.Missing()) != 2 {
+		t.Fatalf("unexpected progress after one signature: weight %d, missing %v", alice.Weight(), alice.Missing())
+	}
+	if _, err := alice.Finalize(); !errors.Is(err, ErrIncompleteSignatures) {
+		t.Fatalf("expected ErrIncompleteSignatures, got %v", err)
+	}
+	file, err := alice.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	if !strings.Contains(string(file), `"signer": "alice"`) || !strings.Contains(string(file), `"signed_at": "2023-11-14T22:13:20Z"`) {
+		t.Fatalf("signer metadata missing from container:\n%s", file)
+	}
+
+	// Carol signs her own copy of the unsigned container.
+	carol, err := NewPartialTransaction(tx, 2, 2, keys)
+	if err != nil {
+		t.Fatalf("NewPartialTransaction error: %v", err)
+	}
+	if err := carol.SignWithKey(privs[2], "carol"); err != nil {
+		t.Fatalf("SignWithKey error: %v", err)
+	}
+
+	coordinator, err := ParsePartialTransaction(file)
+	if err != nil {
+		t.Fatalf("ParsePartialTransaction error: %v", err)
+	}
+	if len(coordinator.Signatures) != 1 || coordinator.Signatures[0].Signer != "alice" {
+		t.Fatalf("unexpected parsed signatures %+v", coordinator.Signatures)
+	}
+	if err := coordinator.Merge(carol); err != nil {
+		t.Fatalf("Merge error: %v", err)
+	}
+	if err := coordinator.Merge(carol); err != nil || len(coordinator.Signatures) != 2 {
+		t.Fatalf("merging twice should be a no-op, got %v with %d signatures", err, len(coordinator.Signatures))
+	}
+	if !coordinator.IsComplete() {
+		t.Fatalf("expected 2-of-3 to be complete")
+	}
+	signed, err := coordinator.Finalize()
+	if err != nil {
+		t.Fatalf("Finalize error: %v", err)
+	}
+	if !bytes.Equal(signed.RawData, tx.RawData) || len(signed.Signatures) != 2 {
+		t.Fatalf("unexpected finalized transaction")
+	}
+	for i, want := range []string{keys[0].Address, keys[2].Address} {
+		if got, _ := RecoverAddress(signed.ID(), signed.Signatures[i]); got != want {
+			t.Fatalf("signature %d from %s, want %s", i, got, want)
+		}
+	}
+}
+
+func TestPartialTransaction_KeepsExistingSignatures(t *testing.T) {
+	privs, tx, keys := testMultisig(t)
+	if err := tx.SignWithKey(privs[1]); err != nil {
+		t.Fatalf("SignWithKey error: %v", err)
+	}
+	p, err := NewPartialTransaction(tx, 2, 2, keys)
+	if err != nil {
+		t.Fatalf("NewPartialTransaction error: %v", err)
+	}
+	if p.Weight() != 1 || p.Signatures[0].Address != keys[1].Address {
+		t.Fatalf("expected the existing signature to be kept, got %+v", p.Signatures)
+	}
+
+	if _, err := NewPartialTransaction(tx, 2, 1, keys[:1]); !errors.Is(err, ErrNotPermissionKey) {
+		t.Fatalf("expected ErrNotPermissionKey for a foreign signature, got %v", err)
+	}
+}
+
+func TestPartialTransaction_Rejects(t *testing.T) {
+	privs, tx, keys := testMultisig(t)
+	cases := []struct {
+		name         string
+		permissionID int32
+		threshold    int64
+		keys         []PermissionKey
+	}{
+		{"wrong permission", 0, 2, keys},
+		{"zero threshold", 2, 0, keys},
+		{"no keys", 2, 1, nil},
+		{"bad address", 2, 1, []PermissionKey{{Address: "nope", Weight: 1}}},
+		{"duplicate key", 2, 1, []PermissionKey{keys[0], keys[0]}},
+		{"zero weight", 2, 1, []PermissionKey{{Address: keys[0].Address, Weight: 0}}},
+		{"unreachable threshold", 2, 4, keys},
+	}
+	for _, c := range cases {
+		if _, err := NewPartialTransaction(tx, c.permissionID, c.threshold, c.keys); err == nil {
+			t.Fatalf("%s: expected error", c.name)
+		}
+	}
+
+	p, err := NewPartialTransaction(tx, 2, 2, keys[:2])
+	if err != nil {
+		t.Fatalf("NewPartialTransaction error: %v", err)
+	}
+	if err := p.SignWithKey(privs[2], "mallory"); !errors.Is(err, ErrNotPermissionKey) {
+		t.Fatalf("expected ErrNotPermissionKey, got %v", err)
+	}
+	if err := p.SignWithKey(nil, "alice"); err == nil || len(p.Signatures) != 0 {
+		t.Fatalf("expected error for nil key, got %v", err)
+	}
+	if err := p.Sign(failingSigner{NewAccount(privs[0])}, "alice"); err == nil {
+		t.Fatalf("expected signer error")
+	}
+	forged := PartialSignature{Address: keys[0].Address, Signature: make([]byte, 65)}
+	if err := p.add(forged); err == nil {
+		t.Fatalf("expected invalid signature to be rejected")
+	}
+
+	other, _ := NewPartialTransaction(tx, 2, 1, keys[:2])
+	if err := p.Merge(other); !errors.Is(err, ErrPartialMismatch) {
+		t.Fatalf("expected ErrPartialMismatch for threshold, got %v", err)
+	}
+	other, _ = NewPartialTransaction(tx, 2, 2, keys)
+	if err := p.Merge(other); !errors.Is(err, ErrPartialMismatch) {
+		t.Fatalf("expected ErrPartialMismatch for keys, got %v", err)
+	}
+	other, _ = NewPartialTransaction(tx, 2, 2, []PermissionKey{keys[0], {Address: keys[1].Address, Weight: 2}})
+	if err := p.Merge(other); !errors.Is(err, ErrPartialMismatch) {
+		t.Fatalf("expected ErrPartialMismatch for weights, got %v", err)
+	}
+}
+
+func TestParsePartialTransaction_Rejects(t *testing.T) {
+	privs, tx, keys := testMultisig(t)
+	p, _ := NewPartialTransaction(tx, 2, 2, keys)
+	if err := p.SignWithKey(privs[0], "alice"); err != nil {
+		t.Fatalf("SignWithKey error: %v", err)
+	}
+	file, _ := p.Marshal()
+	sig := p.Signatures[0]
+
+	cases := []struct {
+		name    string
+		old     string
+		new     string
+		wantErr error
+	}{
+		{"version", `"version": 1`, `"version": 2`, ErrPartialVersion},
+		{"tx id", `"tx_id": "`, `"tx_id": "00`, ErrTxIDMismatch},
+		{"raw hex", `"raw_data_hex": "`, `"raw_data_hex": "zz`, ErrInvalidTransaction},
+		{"signature hex", `"signature": "`, `"signature": "zz`, ErrInvalidSignature},
+		{"signature address", `"address": "` + sig.Address + `",
+      "signature"`, `"address": "` + keys[1].Address + `",
+      "signature"`, ErrSignerMismatch},
+		{"signer not a key", `"address": "` + sig.Address + `",
+      "signature"`, `"address": "TYJPRrdB5APNeRs4R7fYZSwW3TcrTKw2gz",
+      "signature"`, nil},
+		{"threshold", `"threshold": 2`, `"threshold": 9`, nil},
+	}
+	for _, c := range cases {
+		bad := strings.Replace(string(file), c.old, c.new, 1)
+		if bad == string(file) {
+			t.Fatalf("%s: fixture replacement did not apply", c.name)
+		}
+		_, err := ParsePartialTransaction([]byte(bad))
+		if err == nil || (c.wantErr != nil && !errors.Is(err, c.wantErr)) {
+			t.Fatalf("%s: expected %v, got %v", c.name, c.wantErr, err)
+		}
+	}
+	if _, err := ParsePartialTransaction([]byte("{")); err == nil {
+		t.Fatalf("expected JSON error")
+	}
+}
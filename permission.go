@@ -0,0 +1,355 @@
+package tronwallet
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Limits java-tron places on account permissions. MaxPermissionKeys is the
+// TotalSignNum chain parameter.
+const (
+	MaxPermissionKeys     = 5
+	MaxActivePermissions  = 8
+	maxPermissionNameSize = 32
+	// operationsSize is the length of the operations bitmask: one bit for
+	// each of 256 contract types.
+	operationsSize = 32
+)
+
+// PermissionType is the kind of an account permission.
+type PermissionType int32
+
+// Permission types, as in java-tron's Permission.PermissionType enum.
+const (
+	OwnerPermission   PermissionType = 0
+	WitnessPermission PermissionType = 1
+	ActivePermission  PermissionType = 2
+)
+
+// String returns the java-tron name of the permission type.
+func (t PermissionType) String() string {
+	switch t {
+	case OwnerPermission:
+		return "Owner"
+	case WitnessPermission:
+		return "Witness"
+	case ActivePermission:
+		return "Active"
+	}
+	return fmt.Sprintf("PermissionType(%d)", int32(t))
+}
+
+// Permission is a set of keys that may sign for an account once their
+// weights reach Threshold. The owner permission (ID 0) may do anything,
+// including changing permissions; the witness permission (ID 1) only
+// produces blocks; active permissions (IDs 2 and up) may sign the contract
+// types listed in Operations.
+type Permission struct {
+	Type       PermissionType
+	ID         int32
+	Name       string
+	Threshold  int64
+	ParentID   int32
+	Operations []ContractType
+	Keys       []PermissionKey
+}
+
+// PermissionKeyFromPublic returns the permission key for pub, such as the
+// public half of a key from TronWallet.Derive, with the given weight.
+func PermissionKeyFromPublic(pub *ecdsa.PublicKey, weight int64) PermissionKey {
+	return PermissionKey{Address: TronAddressFromPublic(pub), Weight: weight}
+}
+
+// String renders the permission on one line, for example
+//
+//	Active #2 "payments": 2 of [TUEZ… (1), TSeJ… (1)]; allows TransferContract
+func (p Permission) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s #%d", p.Type, p.ID)
+	if p.Name != "" {
+		fmt.Fprintf(&sb, " %q", p.Name)
+	}
+	fmt.Fprintf(&sb, ": %d of [", p.Threshold)
+	for i, k := range p.Keys {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "%s (%d)", k.Address, k.Weight)
+	}
+	sb.WriteString("]")
+	if len(p.Operations) > 0 {
+		names := make([]string, len(p.Operations))
+		for i, op := range p.Operations {
+			names[i] = op.String()
+		}
+		sb.WriteString("; allows " + strings.Join(names, ", "))
+	}
+	return sb.String()
+}
+
+// AccountPermissionUpdateContract replaces all permissions of OwnerAddress.
+// Witness is only valid for Super Representative accounts. java-tron charges
+// the UpdateAccountPermissionFee chain parameter for it.
+type AccountPermissionUpdateContract struct {
+	OwnerAddress string
+	Owner        Permission
+	Witness      *Permission
+	Actives      []Permission
+}
+
+// NewAccountPermissionUpdateTransaction builds an unsigned update of owner's
+// permissions. The Type and ID of each permission are filled in from its
+// position: the owner is 0, the witness 1 and actives 2, 3 and so on.
+// Permission updates must be signed under the current owner permission.
+func NewAccountPermissionUpdateTransaction(opts TransactionOptions, owner string, ownerPermission Permission, witness *Permission, actives []Permission) (*Transaction, error) {
+	c := &AccountPermissionUpdateContract{OwnerAddress: owner, Owner: ownerPermission}
+	c.Owner.Type, c.Owner.ID = OwnerPermission, 0
+	if witness != nil {
+		w := *witness
+		w.Type, w.ID = WitnessPermission, 1
+		c.Witness = &w
+	}
+	for i, a := range actives {
+		a.Type, a.ID = ActivePermission, int32(i+2)
+		c.Actives = append(c.Actives, a)
+	}
+	return NewTransaction(opts, c)
+}
+
+// ContractType implements ContractParameter.
+func (c *AccountPermissionUpdateContract) ContractType() ContractType {
+	return AccountPermissionUpdateContractType
+}
+
+// String renders the permission set, one permission per line.
+func (c *AccountPermissionUpdateContract) String() string {
+	lines := []string{c.Owner.String()}
+	if c.Witness != nil {
+		lines = append(lines, c.Witness.String())
+	}
+	for _, a := range c.Actives {
+		lines = append(lines, a.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (c *AccountPermissionUpdateContract) marshalProto() ([]byte, error) {
+	owner, err := contractAddress("owner address", c.OwnerAddress)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Actives) == 0 || len(c.Actives) > MaxActivePermissions {
+		return nil, fmt.Errorf("need between 1 and %d active permissions, got %d", MaxActivePermissions, len(c.Actives))
+	}
+	var w protoWriter
+	w.bytes(1, owner)
+	b, err := c.Owner.marshal(OwnerPermission, 0)
+	if err != nil {
+		return nil, err
+	}
+	w.message(2, b)
+	if c.Witness != nil {
+		if len(c.Witness.Keys) != 1 {
+			return nil, errors.New("witness permission must have exactly one key")
+		}
+		if b, err = c.Witness.marshal(WitnessPermission, 1); err != nil {
+			return nil, err
+		}
+		w.message(3, b)
+	}
+	for i, a := range c.Actives {
+		if b, err = a.marshal(ActivePermission, int32(i+2)); err != nil {
+			return nil, err
+		}
+		w.message(4, b)
+	}
+	return w.buf, nil
+}
+
+// marshal validates the permission for its position and encodes it.
+func (p *Permission) marshal(typ PermissionType, id int32) ([]byte, error) {
+	if p.Type != typ || p.ID != id {
+		return nil, fmt.Errorf("%s permission must have type %s and ID %d", strings.ToLower(typ.String()), typ, id)
+	}
+	if len(p.Name) > maxPermissionNameSize {
+		return nil, fmt.Errorf("permission %d: name is longer than %d bytes", id, maxPermissionNameSize)
+	}
+	if p.ParentID != 0 {
+		return nil, fmt.Errorf("permission %d: parent ID must be 0", id)
+	}
+	if len(p.Keys) == 0 || len(p.Keys) > MaxPermissionKeys {
+		return nil, fmt.Errorf("permission %d: need between 1 and %d keys, got %d", id, MaxPermissionKeys, len(p.Keys))
+	}
+	if p.Threshold <= 0 {
+		return nil, fmt.Errorf("permission %d: threshold must be positive", id)
+	}
+	var ops []byte
+	if typ == ActivePermission {
+		if len(p.Operations) == 0 {
+			return nil, fmt.Errorf("permission %d: active permissions need at least one operation", id)
+		}
+		var err error
+		if ops, err = encodeOperations(p.Operations); err != nil {
+			return nil, fmt.Errorf("permission %d: %w", id, err)
+		}
+	} else if len(p.Operations) > 0 {
+		return nil, fmt.Errorf("%s permission cannot restrict operations", strings.ToLower(typ.String()))
+	}
+
+	var keys [][]byte
+	var total int64
+	seen := map[string]bool{}
+	for i, k := range p.Keys {
+		addr, err := contractAddress(fmt.Sprintf("permission %d key %d", id, i), k.Address)
+		if err != nil {
+			return nil, err
+		}
+		if seen[string(addr)] {
+			return nil, fmt.Errorf("permission %d: duplicate key %s", id, k.Address)
+		}
+		seen[string(addr)] = true
+		if k.Weight <= 0 {
+			return nil, fmt.Errorf("permission %d: key %s weight must be positive", id, k.Address)
+		}
+		total += k.Weight
+		var kw protoWriter
+		kw.bytes(1, addr)
+		kw.int64(2, k.Weight)
+		keys = append(keys, kw.buf)
+	}
+	if total < p.Threshold {
+		return nil, fmt.Errorf("permission %d: key weights sum to %d, below threshold %d", id, total, p.Threshold)
+	}
+
+	var w protoWriter
+	w.int64(1, int64(typ))
+	w.int64(2, int64(id))
+	w.string(3, p.Name)
+	w.int64(4, p.Threshold)
+	w.bytes(6, ops)
+	for _, k := range keys {
+		w.message(7, k)
+	}
+	return w.buf, nil
+}
+
+// encodeOperations sets bit t of the 32-byte bitmask for each contract type.
+func encodeOperations(types []ContractType) ([]byte, error) {
+	ops := make([]byte, operationsSize)
+	for _, t := range types {
+		if t < 0 || int(t) >= 8*operationsSize {
+			return nil, fmt.Errorf("invalid operation %s", t)
+		}
+		ops[t/8] |= 1 << (t % 8)
+	}
+	return ops, nil
+}
+
+// decodeOperations lists the contract types set in an operations bitmask.
+func decodeOperations(ops []byte) []ContractType {
+	var types []ContractType
+	for i := 0; i < 8*len(ops); i++ {
+		if ops[i/8]&(1<<(i%8)) != 0 {
+			types = append(types, ContractType(i))
+		}
+	}
+	return types
+}
+
+func parseAccountPermissionUpdateContract(b []byte) (ContractParameter, error) {
+	fields, err := parseProto(b)
+	if err != nil {
+		return nil, err
+	}
+	c := &AccountPermissionUpdateContract{}
+	for _, f := range fields {
+		var p *Permission
+		switch f.Num {
+		case 1:
+			c.OwnerAddress, err = f.address()
+		case 2:
+			if p, err = parsePermission(f); err == nil {
+				c.Owner = *p
+			}
+		case 3:
+			c.Witness, err = parsePermission(f)
+		case 4:
+			if p, err = parsePermission(f); err == nil {
+				c.Actives = append(c.Actives, *p)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func parsePermission(f protoField) (*Permission, error) {
+	b, err := f.bytes()
+	if err != nil {
+		return nil, err
+	}
+	fields, err := parseProto(b)
+	if err != nil {
+		return nil, err
+	}
+	p := &Permission{}
+	for _, f := range fields {
+		var v int64
+		switch f.Num {
+		case 1:
+			v, err = f.int64()
+			p.Type = PermissionType(v)
+		case 2:
+			v, err = f.int64()
+			p.ID = int32(v)
+		case 3:
+			p.Name, err = f.string()
+		case 4:
+			p.Threshold, err = f.int64()
+		case 5:
+			v, err = f.int64()
+			p.ParentID = int32(v)
+		case 6:
+			var ops []byte
+			ops, err = f.bytes()
+			p.Operations = decodeOperations(ops)
+		case 7:
+			var k *PermissionKey
+			if k, err = parsePermissionKey(f); err == nil {
+				p.Keys = append(p.Keys, *k)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func parsePermissionKey(f protoField) (*PermissionKey, error) {
+	b, err := f.bytes()
+	if err != nil {
+		return nil, err
+	}
+	fields, err := parseProto(b)
+	if err != nil {
+		return nil, err
+	}
+	k := &PermissionKey{}
+	for _, f := range fields {
+		switch f.Num {
+		case 1:
+			k.Address, err = f.address()
+		case 2:
+			k.Weight, err = f.int64()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
+}
@@ -0,0 +1,186 @@
+package tronwallet
+
+import (
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testPermissions returns a 2-of-2 owner permission over accounts 0 and 1 of
+// testMnemonic and a 1-of-1 active permission for account 1 limited to TRX
+// transfers and smart contract calls.
+func testPermissions(t *testing.T) (Permission, Permission) {
+	t.Helper()
+	w, err := RestoreWallet(testMnemonic)
+	if err != nil {
+		t.Fatalf("RestoreWallet error: %v", err)
+	}
+	priv0, _ := w.Derive(0)
+	priv1, _ := w.Derive(1)
+	owner := Permission{
+		Name:      "owner",
+		Threshold: 2,
+		Keys:      []PermissionKey{PermissionKeyFromPublic(&priv0.PublicKey, 1), PermissionKeyFromPublic(&priv1.PublicKey, 1)},
+	}
+	active := Permission{
+		Name:       "payments",
+		Threshold:  1,
+		Operations: []ContractType{TransferContractType, TriggerSmartContractType},
+		Keys:       []PermissionKey{PermissionKeyFromPublic(&priv1.PublicKey, 1)},
+	}
+	return owner, active
+}
+
+func TestNewAccountPermissionUpdateTransaction(t *testing.T) {
+	owner, active := testPermissions(t)
+	tx, err := NewAccountPermissionUpdateTransaction(testTxOptions(), "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", owner, nil, []Permission{active})
+	if err != nil {
+		t.Fatalf("NewAccountPermissionUpdateTransaction error: %v", err)
+	}
+	want := "0a02abcd2208112233445566778840e0a499ffbc315aed01082e12e8010a3c747970652e676f6f676c65617069732e636f6d2f70726f746f636f6c2e4163636f756e745065726d697373696f6e557064617465436f6e747261637412a7010a1541c8599111f29c1e1e061265b4af93ea1f274ad78a123f1a056f776e657220023a190a1541c8599111f29c1e1e061265b4af93ea1f274ad78a10013a190a1541b6e708a39781c96bd399c7657780ff9fe9f052a81001224d080210021a087061796d656e74732001322002000080000000000000000000000000000000000000000000000000000000003a190a1541b6e708a39781c96bd399c7657780ff9fe9f052a810017080d095ffbc31"
+	if got := hex.EncodeToString(tx.RawData); got != want {
+		t.Fatalf("raw data mismatch:\n got %s\nwant %s", got, want)
+	}
+
+	raw, err := tx.Raw()
+	if err != nil {
+		t.Fatalf("Raw error: %v", err)
+	}
+	c, ok := raw.Contracts[0].Parameter.(*AccountPermissionUpdateContract)
+	if !ok {
+		t.Fatalf("unexpected parameter %T", raw.Contracts[0].Parameter)
+	}
+	active.Type, active.ID = ActivePermission, 2
+	if !reflect.DeepEqual(c.Owner, owner) || c.Witness != nil || !reflect.DeepEqual(c.Actives, []Permission{active}) {
+		t.Fatalf("unexpected parsed contract %+v", c)
+	}
+	wantText := `Owner #0 "owner": 2 of [TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH (1), TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK (1)]` + "\n" +
+		`Active #2 "payments": 1 of [TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK (1)]; allows TransferContract, TriggerSmartContract`
+	if got := c.String(); got != wantText {
+		t.Fatalf("unexpected rendering:\n%s", got)
+	}
+
+	s, err := SummarizeTransaction(tx)
+	if err != nil || s.Opaque || !strings.Contains(s.String(), "Active:        Active #2 \"payments\"") {
+		t.Fatalf("unexpected summary %v:\n%s", err, s)
+	}
+	e, err := EstimateFee(tx, MainnetChainParameters, FeeOptions{Resources: AccountResources{FreeBandwidth: 600}})
+	if err != nil || e.ContractFee != 100_000_000 || e.Total != 100_000_000 {
+		t.Fatalf("unexpected fee estimate %+v, %v", e, err)
+	}
+
+	data, err := tx.JSON(true)
+	if err != nil {
+		t.Fatalf("JSON error: %v", err)
+	}
+	for _, frag := range []string{`"permission_name":"payments"`, `"type":"Active"`, `"operations":"0200008000`, `"keys":[{"address":"TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK","weight":1}]`} {
+		if !strings.Contains(string(data), frag) {
+			t.Fatalf("JSON is missing %s:\n%s", frag, data)
+		}
+	}
+	if back, err := ParseTransactionJSON(data); err != nil || string(back.RawData) != string(tx.RawData) {
+		t.Fatalf("JSON round trip failed: %v", err)
+	}
+}
+
+func TestAccountPermissionUpdate_Witness(t *testing.T) {
+	owner, active := testPermissions(t)
+	witness := Permission{Name: "witness", Threshold: 1, Keys: owner.Keys[:1]}
+	tx, err := NewAccountPermissionUpdateTransaction(testTxOptions(), "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", owner, &witness, []Permission{active, active})
+	if err != nil {
+		t.Fatalf("NewAccountPermissionUpdateTransaction error: %v", err)
+	}
+	raw, _ := tx.Raw()
+	c := raw.Contracts[0].Parameter.(*AccountPermissionUpdateContract)
+	if c.Witness == nil || c.Witness.Type != WitnessPermission || c.Witness.ID != 1 || c.Actives[1].ID != 3 {
+		t.Fatalf("unexpected permission IDs %+v", c)
+	}
+	if witness.Type != OwnerPermission || witness.ID != 0 {
+		t.Fatalf("builder must not modify its arguments")
+	}
+	if !strings.Contains(c.String(), `Witness #1 "witness": 1 of [`) {
+		t.Fatalf("unexpected rendering:\n%s", c)
+	}
+	if s, _ := SummarizeTransaction(tx); s.Opaque || !strings.Contains(s.String(), "Witness:") {
+		t.Fatalf("unexpected summary:\n%s", s)
+	}
+}
+
+func TestAccountPermissionUpdate_Validation(t *testing.T) {
+	owner, active := testPermissions(t)
+	key := owner.Keys[0]
+	keys := func(n int) []PermissionKey {
+		w, _ := RestoreWallet(testMnemonic)
+		var out []PermissionKey
+		for i := uint32(0); i < uint32(n); i++ {
+			priv, _ := w.Derive(i)
+			out = append(out, PermissionKeyFromPublic(&priv.PublicKey, 1))
+		}
+		return out
+	}
+	with := func(p Permission, edit func(*Permission)) Permission {
+		p.Keys = append([]PermissionKey(nil), p.Keys...)
+		edit(&p)
+		return p
+	}
+	cases := []struct {
+		name    string
+		owner   Permission
+		witness *Permission
+		actives []Permission
+	}{
+		{"no actives", owner, nil, nil},
+		{"too many actives", owner, nil, make([]Permission, MaxActivePermissions+1)},
+		{"no owner keys", with(owner, func(p *Permission) { p.Keys = nil }), nil, []Permission{active}},
+		{"too many keys", with(owner, func(p *Permission) { p.Keys = keys(MaxPermissionKeys + 1) }), nil, []Permission{active}},
+		{"zero threshold", with(owner, func(p *Permission) { p.Threshold = 0 }), nil, []Permission{active}},
+		{"threshold above weights", with(owner, func(p *Permission) { p.Threshold = 3 }), nil, []Permission{active}},
+		{"zero weight", with(owner, func(p *Permission) { p.Keys[0].Weight = 0 }), nil, []Permission{active}},
+		{"duplicate key", with(owner, func(p *Permission) { p.Keys[1] = key }), nil, []Permission{active}},
+		{"bad key", with(owner, func(p *Permission) { p.Keys[1].Address = "nope" }), nil, []Permission{active}},
+		{"long name", with(owner, func(p *Permission) { p.Name = strings.Repeat("n", 33) }), nil, []Permission{active}},
+		{"parent", with(owner, func(p *Permission) { p.ParentID = 2 }), nil, []Permission{active}},
+		{"owner operations", with(owner, func(p *Permission) { p.Operations = []ContractType{1} }), nil, []Permission{active}},
+		{"active without operations", owner, nil, []Permission{with(active, func(p *Permission) { p.Operations = nil })}},
+		{"invalid operation", owner, nil, []Permission{with(active, func(p *Permission) { p.Operations = []ContractType{256} })}},
+		{"witness keys", owner, &owner, []Permission{active}},
+	}
+	for _, c := range cases {
+		if _, err := NewAccountPermissionUpdateTransaction(testTxOptions(), "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", c.owner, c.witness, c.actives); err == nil {
+			t.Fatalf("%s: expected error", c.name)
+		}
+	}
+	if _, err := NewAccountPermissionUpdateTransaction(testTxOptions(), "bad", owner, nil, []Permission{active}); err == nil {
+		t.Fatalf("expected error for bad owner address")
+	}
+	if _, err := NewAccountPermissionUpdateTransaction(testTxOptions(), "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", with(owner, func(p *Permission) { p.Keys = keys(MaxPermissionKeys) }), nil, []Permission{active}); err != nil {
+		t.Fatalf("%d keys should be accepted: %v", MaxPermissionKeys, err)
+	}
+
+	// Hand-built contracts must carry the type and ID of their position.
+	bad := &AccountPermissionUpdateContract{OwnerAddress: "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", Owner: owner, Actives: []Permission{active}}
+	if _, err := NewTransaction(testTxOptions(), bad); err == nil {
+		t.Fatalf("expected error for an active permission without type and ID")
+	}
+}
+
+func TestOperationsBitmask(t *testing.T) {
+	types := []ContractType{0, TransferContractType, TriggerSmartContractType, AccountPermissionUpdateContractType, 255}
+	ops, err := encodeOperations(types)
+	if err != nil {
+		t.Fatalf("encodeOperations error: %v", err)
+	}
+	if len(ops) != 32 || ops[0] != 0x03 || ops[3] != 0x80 || ops[5] != 0x40 || ops[31] != 0x80 {
+		t.Fatalf("unexpected bitmask %x", ops)
+	}
+	if got := decodeOperations(ops); !reflect.DeepEqual(got, types) {
+		t.Fatalf("decodeOperations = %v", got)
+	}
+	if _, err := encodeOperations([]ContractType{-1}); err == nil {
+		t.Fatalf("expected error for negative type")
+	}
+	if PermissionType(7).String() != "PermissionType(7)" {
+		t.Fatalf("unexpected unknown permission type name")
+	}
+}
@@ -0,0 +1,161 @@
+package tronwallet
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// A minimal protobuf wire-format codec for the java-tron messages this
+// package builds and parses. It writes fields in ascending field number order
+// and omits proto3 default values, which is what protobuf-java does, so the
+// encodings are byte-identical to java-tron's.
+
+const (
+	protoWireVarint = 0
+	protoWireBytes  = 2
+)
+
+var errProtoTruncated = errors.New("protobuf: truncated message")
+
+// protoWriter appends protobuf fields to a buffer. Callers must add fields in
+// ascending field number order.
+type protoWriter struct {
+	buf []byte
+}
+
+func (w *protoWriter) tag(field, wire int) {
+	w.buf = binary.AppendUvarint(w.buf, uint64(field)<<3|uint64(wire))
+}
+
+// varint writes an int64/uint64/int32/enum/bool field, omitting zero.
+func (w *protoWriter) varint(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	w.tag(field, protoWireVarint)
+	w.buf = binary.AppendUvarint(w.buf, v)
+}
+
+// int64 writes a signed integer field; negative values use ten bytes, as
+// protobuf's int64 and int32 types do.
+func (w *protoWriter) int64(field int, v int64) {
+	w.varint(field, uint64(v))
+}
+
+func (w *protoWriter) bool(field int, v bool) {
+	if v {
+		w.varint(field, 1)
+	}
+}
+
+// bytes writes a bytes or string field, omitting empty values.
+func (w *protoWriter) bytes(field int, v []byte) {
+	if len(v) == 0 {
+		return
+	}
+	w.message(field, v)
+}
+
+func (w *protoWriter) string(field int, v string) {
+	w.bytes(field, []byte(v))
+}
+
+// message writes an embedded message; unlike scalars it is written even when
+// empty, because presence is significant for message fields.
+func (w *protoWriter) message(field int, v []byte) {
+	w.tag(field, protoWireBytes)
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+// protoField is a single decoded field. Varint holds the value of varint
+// fields and Bytes the payload of length-delimited fields.
+type protoField struct {
+	Num    int
+	Wire   int
+	Varint uint64
+	Bytes  []byte
+}
+
+// parseProto splits a message into its fields. Fixed-width fields are
+// accepted and skipped since none of the supported messages use them; groups
+// are rejected.
+func parseProto(b []byte) ([]protoField, error) {
+	var fields []protoField
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, errProtoTruncated
+		}
+		b = b[n:]
+		f := protoField{Num: int(key >> 3), Wire: int(key & 7)}
+		if f.Num == 0 {
+			return nil, errors.New("protobuf: invalid field number 0")
+		}
+		switch f.Wire {
+		case protoWireVarint:
+			f.Varint, n = binary.Uvarint(b)
+			if n <= 0 {
+				return nil, errProtoTruncated
+			}
+			b = b[n:]
+		case protoWireBytes:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || l > uint64(len(b)-n) {
+				return nil, errProtoTruncated
+			}
+			f.Bytes = b[n : n+int(l)]
+			b = b[n+int(l):]
+		case 1, 5:
+			size := 8
+			if f.Wire == 5 {
+				size = 4
+			}
+			if len(b) < size {
+				return nil, errProtoTruncated
+			}
+			b = b[size:]
+		default:
+			return nil, errors.New("protobuf: unsupported wire type")
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+var errProtoWireType = errors.New("protobuf: unexpected wire type")
+
+// uint64 returns the value of a varint field.
+func (f protoField) uint64() (uint64, error) {
+	if f.Wire != protoWireVarint {
+		return 0, errProtoWireType
+	}
+	return f.Varint, nil
+}
+
+func (f protoField) int64() (int64, error) {
+	v, err := f.uint64()
+	return int64(v), err
+}
+
+// bytes returns the payload of a length-delimited field.
+func (f protoField) bytes() ([]byte, error) {
+	if f.Wire != protoWireBytes {
+		return nil, errProtoWireType
+	}
+	return f.Bytes, nil
+}
+
+func (f protoField) string() (string, error) {
+	b, err := f.bytes()
+	return string(b), err
+}
+
+// address returns the Base58 form of a 21-byte address field.
+func (f protoField) address() (string, error) {
+	b, err := f.bytes()
+	if err != nil {
+		return "", err
+	}
+	return EncodeAddress(b)
+}
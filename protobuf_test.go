@@ -0,0 +1,60 @@
+package tronwallet
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestProtoWriter(t *testing.T) {
+	var w protoWriter
+	w.int64(1, 0)
+	w.int64(1, 150)
+	w.int64(2, -1)
+	w.bool(3, false)
+	w.bool(3, true)
+	w.bytes(4, nil)
+	w.string(5, "hi")
+	w.message(6, nil)
+	want := "089601" + "10ffffffffffffffffff01" + "1801" + "2a026869" + "3200"
+	if got := hex.EncodeToString(w.buf); got != want {
+		t.Fatalf("got %s want %s", got, want)
+	}
+
+	fields, err := parseProto(w.buf)
+	if err != nil || len(fields) != 5 {
+		t.Fatalf("parseProto: %v %+v", err, fields)
+	}
+	if v, _ := fields[1].int64(); v != -1 {
+		t.Fatalf("expected -1, got %d", v)
+	}
+	if _, err := fields[0].bytes(); err == nil {
+		t.Fatalf("expected wire type error")
+	}
+	if _, err := fields[3].address(); err == nil {
+		t.Fatalf("expected invalid address error")
+	}
+	if _, err := fields[0].address(); err == nil {
+		t.Fatalf("expected wire type error")
+	}
+}
+
+func TestParseProto(t *testing.T) {
+	// A fixed64 and a fixed32 field are skipped over.
+	fields, err := parseProto([]byte{0x09, 1, 2, 3, 4, 5, 6, 7, 8, 0x15, 1, 2, 3, 4, 0x18, 0x07})
+	if err != nil || len(fields) != 3 || fields[2].Varint != 7 {
+		t.Fatalf("parseProto: %v %+v", err, fields)
+	}
+	for name, b := range map[string][]byte{
+		"bad key":       {0x80},
+		"field zero":    {0x00, 0x01},
+		"bad varint":    {0x08, 0x80},
+		"short bytes":   {0x0a, 0x05, 0x01},
+		"bad length":    {0x0a, 0x80},
+		"short fixed64": {0x09, 0x01},
+		"group":         {0x0b},
+	} {
+		if _, err := parseProto(b); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
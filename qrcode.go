@@ -0,0 +1,527 @@
+package tronwallet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// QRErrorCorrection is the error correction level of a QR code.
+type QRErrorCorrection int
+
+const (
+	// QRLow recovers about 7% of damaged codewords.
+	QRLow QRErrorCorrection = iota
+	// QRMedium recovers about 15% of damaged codewords.
+	QRMedium
+	// QRQuartile recovers about 25% of damaged codewords.
+	QRQuartile
+	// QRHigh recovers about 30% of damaged codewords.
+	QRHigh
+)
+
+// qrFormatBits maps an error correction level to its 2-bit format value.
+var qrFormatBits = [4]int{1, 0, 3, 2}
+
+// qrECCPerBlock and qrNumBlocks are indexed by [level][version]; index 0 is
+// unused. Values come from ISO/IEC 18004 table 9.
+var qrECCPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var qrNumBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// QRCode is an encoded QR code symbol. Modules are addressed by (x, y) with
+// the origin in the top-left corner.
+type QRCode struct {
+	version    int
+	size       int
+	level      QRErrorCorrection
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+// EncodeQR encodes data in byte mode using the smallest QR version (1-40)
+// that fits at the requested error correction level.
+func EncodeQR(data []byte, level QRErrorCorrection) (*QRCode, error) {
+	if level < QRLow || level > QRHigh {
+		return nil, errors.New("invalid QR error correction level")
+	}
+	version := 0
+	for v := 1; v <= 40; v++ {
+		ccBits := 8
+		if v >= 10 {
+			ccBits = 16
+		}
+		if len(data) < 1<<ccBits && 4+ccBits+8*len(data) <= qrNumDataCodewords(v, level)*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, errors.New("data too long for a QR code")
+	}
+
+	var bb qrBitBuffer
+	bb.append(0x4, 4) // byte mode
+	if version >= 10 {
+		bb.append(len(data), 16)
+	} else {
+		bb.append(len(data), 8)
+	}
+	for _, b := range data {
+		bb.append(int(b), 8)
+	}
+	capacity := qrNumDataCodewords(version, level) * 8
+	bb.append(0, min(4, capacity-len(bb)))
+	bb.append(0, (8-len(bb)%8)%8)
+	for pad := 0xEC; len(bb) < capacity; pad ^= 0xEC ^ 0x11 {
+		bb.append(pad, 8)
+	}
+	codewords := make([]byte, len(bb)/8)
+	for i, bit := range bb {
+		if bit {
+			codewords[i>>3] |= 1 << (7 - uint(i&7))
+		}
+	}
+
+	q := &QRCode{version: version, size: version*4 + 17, level: level}
+	q.modules = make([][]bool, q.size)
+	q.isFunction = make([][]bool, q.size)
+	for i := range q.modules {
+		q.modules[i] = make([]bool, q.size)
+		q.isFunction[i] = make([]bool, q.size)
+	}
+	q.drawFunctionPatterns()
+	q.drawCodewords(q.addECCAndInterleave(codewords))
+
+	best, minPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormatBits(mask)
+		if p := q.penalty(); minPenalty < 0 || p < minPenalty {
+			best, minPenalty = mask, p
+		}
+		q.applyMask(mask) // XOR undoes the mask
+	}
+	q.applyMask(best)
+	q.drawFormatBits(best)
+	q.isFunction = nil
+	return q, nil
+}
+
+// Version returns the QR version (1-40).
+func (q *QRCode) Version() int { return q.version }
+
+// Size returns the width and height of the symbol in modules.
+func (q *QRCode) Size() int { return q.size }
+
+// Module reports whether the module at (x, y) is dark. Coordinates outside
+// the symbol are light.
+func (q *QRCode) Module(x, y int) bool {
+	return x >= 0 && y >= 0 && x < q.size && y < q.size && q.modules[y][x]
+}
+
+// SVG renders the symbol as a standalone SVG document with a quiet zone of
+// border modules on each side. One module is one user unit.
+func (q *QRCode) SVG(border int) []byte {
+	dim := q.size + 2*border
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, dim, dim)
+	fmt.Fprintf(&sb, `<rect width="%d" height="%d" fill="#fff"/>`, dim, dim)
+	fmt.Fprintf(&sb, `<path d="%s" fill="#000"/></svg>`, q.svgPath(border, border))
+	return []byte(sb.String())
+}
+
+// svgPath returns SVG path data drawing each dark module as a unit square,
+// offset by (x0, y0).
+func (q *QRCode) svgPath(x0, y0 int) string {
+	var sb strings.Builder
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if q.modules[y][x] {
+				fmt.Fprintf(&sb, "M%d,%dh1v1h-1z", x+x0, y+y0)
+			}
+		}
+	}
+	return sb.String()
+}
+
+type qrBitBuffer []bool
+
+func (bb *qrBitBuffer) append(val, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*bb = append(*bb, (val>>uint(i))&1 != 0)
+	}
+}
+
+func (q *QRCode) setFunction(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.isFunction[y][x] = true
+}
+
+func (q *QRCode) drawFunctionPatterns() {
+	for i := 0; i < q.size; i++ {
+		q.setFunction(6, i, i%2 == 0)
+		q.setFunction(i, 6, i%2 == 0)
+	}
+
+	q.drawFinderPattern(3, 3)
+	q.drawFinderPattern(q.size-4, 3)
+	q.drawFinderPattern(3, q.size-4)
+
+	pos := qrAlignmentPositions(q.version)
+	n := len(pos)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			// skip the three corners occupied by finder patterns
+			if (i == 0 && j == 0) || (i == 0 && j == n-1) || (i == n-1 && j == 0) {
+				continue
+			}
+			q.drawAlignmentPattern(pos[i], pos[j])
+		}
+	}
+
+	q.drawFormatBits(0) // reserve the area; overwritten after masking
+	q.drawVersion()
+}
+
+func (q *QRCode) drawFinderPattern(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			dist := max(abs(dx), abs(dy))
+			xx, yy := x+dx, y+dy
+			if xx >= 0 && xx < q.size && yy >= 0 && yy < q.size {
+				q.setFunction(xx, yy, dist != 2 && dist != 4)
+			}
+		}
+	}
+}
+
+func (q *QRCode) drawAlignmentPattern(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			q.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// qrFormatWord returns the 15-bit BCH-protected format information for a
+// level and mask.
+func qrFormatWord(level QRErrorCorrection, mask int) int {
+	data := qrFormatBits[level]<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+func (q *QRCode) drawFormatBits(mask int) {
+	bits := qrFormatWord(q.level, mask)
+
+	for i := 0; i <= 5; i++ {
+		q.setFunction(8, i, qrBit(bits, i))
+	}
+	q.setFunction(8, 7, qrBit(bits, 6))
+	q.setFunction(8, 8, qrBit(bits, 7))
+	q.setFunction(7, 8, qrBit(bits, 8))
+	for i := 9; i < 15; i++ {
+		q.setFunction(14-i, 8, qrBit(bits, i))
+	}
+
+	for i := 0; i < 8; i++ {
+		q.setFunction(q.size-1-i, 8, qrBit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		q.setFunction(8, q.size-15+i, qrBit(bits, i))
+	}
+	q.setFunction(8, q.size-8, true) // always dark
+}
+
+func (q *QRCode) drawVersion() {
+	if q.version < 7 {
+		return
+	}
+	rem := q.version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := q.version<<12 | rem
+	for i := 0; i < 18; i++ {
+		bit := qrBit(bits, i)
+		a, b := q.size-11+i%3, i/3
+		q.setFunction(a, b, bit)
+		q.setFunction(b, a, bit)
+	}
+}
+
+// addECCAndInterleave splits data into blocks, appends Reed-Solomon error
+// correction to each and interleaves the result.
+func (q *QRCode) addECCAndInterleave(data []byte) []byte {
+	numBlocks := qrNumBlocks[q.level][q.version]
+	eccLen := qrECCPerBlock[q.level][q.version]
+	rawCodewords := qrNumRawDataModules(q.version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := rsDivisor(eccLen)
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := range blocks {
+		n := shortBlockLen - eccLen
+		if i >= numShortBlocks {
+			n++
+		}
+		dat := append([]byte(nil), data[k:k+n]...)
+		k += n
+		ecc := rsRemainder(dat, divisor)
+		if i < numShortBlocks {
+			dat = append(dat, 0) // placeholder, skipped when interleaving
+		}
+		blocks[i] = append(dat, ecc...)
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortBlockLen-eccLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+func (q *QRCode) drawCodewords(data []byte) {
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = q.size - 1 - vert
+				}
+				if !q.isFunction[y][x] && i < len(data)*8 {
+					q.modules[y][x] = qrBit(int(data[i>>3]), 7-(i&7))
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (q *QRCode) applyMask(mask int) {
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !q.isFunction[y][x] {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the current symbol using the four ISO/IEC 18004 rules.
+func (q *QRCode) penalty() int {
+	const n1, n2, n3, n4 = 3, 3, 40, 10
+	result := 0
+
+	line := func(get func(i int) bool) {
+		runColor, run := false, 0
+		var history [7]int
+		for i := 0; i < q.size; i++ {
+			if get(i) == runColor {
+				run++
+				if run == 5 {
+					result += n1
+				} else if run > 5 {
+					result++
+				}
+			} else {
+				q.finderPenaltyAddHistory(run, &history)
+				if !runColor {
+					result += q.finderPenaltyCountPatterns(&history) * n3
+				}
+				runColor = get(i)
+				run = 1
+			}
+		}
+		result += q.finderPenaltyTerminateAndCount(runColor, run, &history) * n3
+	}
+	for y := 0; y < q.size; y++ {
+		line(func(x int) bool { return q.modules[y][x] })
+	}
+	for x := 0; x < q.size; x++ {
+		line(func(y int) bool { return q.modules[y][x] })
+	}
+
+	for y := 0; y < q.size-1; y++ {
+		for x := 0; x < q.size-1; x++ {
+			c := q.modules[y][x]
+			if c == q.modules[y][x+1] && c == q.modules[y+1][x] && c == q.modules[y+1][x+1] {
+				result += n2
+			}
+		}
+	}
+
+	dark := 0
+	for _, row := range q.modules {
+		for _, m := range row {
+			if m {
+				dark++
+			}
+		}
+	}
+	total := q.size * q.size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	return result + k*n4
+}
+
+func (q *QRCode) finderPenaltyCountPatterns(h *[7]int) int {
+	n := h[1]
+	core := n > 0 && h[2] == n && h[3] == n*3 && h[4] == n && h[5] == n
+	count := 0
+	if core && h[0] >= n*4 && h[6] >= n {
+		count++
+	}
+	if core && h[6] >= n*4 && h[0] >= n {
+		count++
+	}
+	return count
+}
+
+func (q *QRCode) finderPenaltyTerminateAndCount(runColor bool, run int, h *[7]int) int {
+	if runColor {
+		q.finderPenaltyAddHistory(run, h)
+		run = 0
+	}
+	run += q.size // light border after the final run
+	q.finderPenaltyAddHistory(run, h)
+	return q.finderPenaltyCountPatterns(h)
+}
+
+func (q *QRCode) finderPenaltyAddHistory(run int, h *[7]int) {
+	if h[0] == 0 {
+		run += q.size // light border before the first run
+	}
+	copy(h[1:], h[:6])
+	h[0] = run
+}
+
+// qrAlignmentPositions returns the centre coordinates of the alignment
+// patterns for a version, used on both axes.
+func qrAlignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	n := version/7 + 2
+	step := (version*8 + n*3 + 5) / (n*4 - 4) * 2
+	pos := make([]int, n)
+	pos[0] = 6
+	for i, p := n-1, version*4+17-7; i >= 1; i, p = i-1, p-step {
+		pos[i] = p
+	}
+	return pos
+}
+
+// qrNumRawDataModules returns the number of modules available for data and
+// error correction in a version.
+func qrNumRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		n := version/7 + 2
+		result -= (25*n-10)*n - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+func qrNumDataCodewords(version int, level QRErrorCorrection) int {
+	return qrNumRawDataModules(version)/8 - qrECCPerBlock[level][version]*qrNumBlocks[level][version]
+}
+
+// rsDivisor returns the Reed-Solomon generator polynomial of the given
+// degree, without its leading coefficient, over GF(2^8/0x11D).
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMul(result[j], root)
+			if j+1 < degree {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMul(root, 0x02)
+	}
+	return result
+}
+
+// rsRemainder returns the Reed-Solomon error correction codewords for data.
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coef := range divisor {
+			result[i] ^= gfMul(coef, factor)
+		}
+	}
+	return result
+}
+
+// gfMul multiplies two elements of GF(2^8) modulo x^8+x^4+x^3+x^2+1.
+func gfMul(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+	return byte(z)
+}
+
+func qrBit(x, i int) bool {
+	return (x>>uint(i))&1 != 0
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,214 @@
+package tronwallet
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRSRemainder_HelloWorldVector(t *testing.T) {
+	// ISO/IEC 18004 style example: "HELLO WORLD" as version 1-M
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, rsDivisor(10)); !bytes.Equal(got, want) {
+		t.Fatalf("unexpected ECC %v", got)
+	}
+}
+
+func TestQRFormatWord(t *testing.T) {
+	want := map[QRErrorCorrection]int{
+		QRLow:      0b111011111000100,
+		QRMedium:   0b101010000010010,
+		QRQuartile: 0b011010101011111,
+		QRHigh:     0b001011010001001,
+	}
+	for level, w := range want {
+		if got := qrFormatWord(level, 0); got != w {
+			t.Fatalf("level %d: got %015b want %015b", level, got, w)
+		}
+	}
+}
+
+func TestQRAlignmentPositionsAndCapacity(t *testing.T) {
+	cases := map[int][]int{
+		1:  nil,
+		2:  {6, 18},
+		7:  {6, 22, 38},
+		32: {6, 34, 60, 86, 112, 138},
+		40: {6, 30, 58, 86, 114, 142, 170},
+	}
+	for v, want := range cases {
+		if got := qrAlignmentPositions(v); !reflect.DeepEqual(got, want) {
+			t.Fatalf("version %d: got %v want %v", v, got, want)
+		}
+	}
+	// data codeword counts from the specification
+	if qrNumDataCodewords(1, QRLow) != 19 || qrNumDataCodewords(10, QRMedium) != 216 || qrNumDataCodewords(40, QRHigh) != 1276 {
+		t.Fatalf("unexpected data codeword capacity")
+	}
+}
+
+// decodeQRForTest reads a symbol back into its payload, checking every
+// Reed-Solomon block along the way.
+func decodeQRForTest(t *testing.T, q *QRCode) []byte {
+	t.Helper()
+	// format information, first copy
+	bits := 0
+	for i := 0; i <= 5; i++ {
+		if q.Module(8, i) {
+			bits |= 1 << i
+		}
+	}
+	for i, p := range [][2]int{{8, 7}, {8, 8}, {7, 8}} {
+		if q.Module(p[0], p[1]) {
+			bits |= 1 << (6 + i)
+		}
+	}
+	for i := 9; i < 15; i++ {
+		if q.Module(14-i, 8) {
+			bits |= 1 << i
+		}
+	}
+	mask := -1
+	for m := 0; m < 8; m++ {
+		if qrFormatWord(q.level, m) == bits {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("format information not found")
+	}
+
+	ref := &QRCode{version: q.version, size: q.size, level: q.level}
+	ref.modules = make([][]bool, q.size)
+	ref.isFunction = make([][]bool, q.size)
+	for i := range ref.modules {
+		ref.modules[i] = make([]bool, q.size)
+		ref.isFunction[i] = make([]bool, q.size)
+	}
+	ref.drawFunctionPatterns()
+	for y := range ref.modules {
+		copy(ref.modules[y], q.modules[y])
+	}
+	ref.applyMask(mask)
+
+	var raw []byte
+	var cur byte
+	n := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = q.size - 1 - vert
+				}
+				if ref.isFunction[y][x] {
+					continue
+				}
+				cur <<= 1
+				if ref.modules[y][x] {
+					cur |= 1
+				}
+				if n++; n%8 == 0 {
+					raw = append(raw, cur)
+				}
+			}
+		}
+	}
+
+	numBlocks := qrNumBlocks[q.level][q.version]
+	eccLen := qrECCPerBlock[q.level][q.version]
+	total := qrNumRawDataModules(q.version) / 8
+	numShort := numBlocks - total%numBlocks
+	shortLen := total / numBlocks
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i <= shortLen; i++ {
+		for j := range blocks {
+			if i != shortLen-eccLen || j >= numShort {
+				blocks[j] = append(blocks[j], raw[k])
+				k++
+			}
+		}
+	}
+	var data []byte
+	divisor := rsDivisor(eccLen)
+	for _, b := range blocks {
+		dat, ecc := b[:len(b)-eccLen], b[len(b)-eccLen:]
+		if !bytes.Equal(rsRemainder(dat, divisor), ecc) {
+			t.Fatalf("Reed-Solomon block mismatch")
+		}
+		data = append(data, dat...)
+	}
+
+	readBits := func(pos, n int) int {
+		v := 0
+		for i := 0; i < n; i++ {
+			v = v<<1 | int(data[(pos+i)/8]>>(7-uint((pos+i)%8))&1)
+		}
+		return v
+	}
+	if readBits(0, 4) != 0x4 {
+		t.Fatalf("expected byte mode")
+	}
+	ccBits := 8
+	if q.version >= 10 {
+		ccBits = 16
+	}
+	length := readBits(4, ccBits)
+	out := make([]byte, length)
+	for i := range out {
+		out[i] = byte(readBits(4+ccBits+8*i, 8))
+	}
+	return out
+}
+
+func TestEncodeQR_RoundTrip(t *testing.T) {
+	inputs := [][]byte{
+		[]byte("TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH"),
+		[]byte(strings.Repeat("b5a4cea271ff424d", 4)),
+		bytes.Repeat([]byte{0xA5}, 300),
+		bytes.Repeat([]byte("x"), 1200),
+	}
+	for _, in := range inputs {
+		for level := QRLow; level <= QRHigh; level++ {
+			q, err := EncodeQR(in, level)
+			if err != nil {
+				t.Fatalf("EncodeQR error: %v", err)
+			}
+			if q.Size() != q.Version()*4+17 {
+				t.Fatalf("unexpected size %d for version %d", q.Size(), q.Version())
+			}
+			if got := decodeQRForTest(t, q); !bytes.Equal(got, in) {
+				t.Fatalf("round trip mismatch at level %d version %d", level, q.Version())
+			}
+		}
+	}
+}
+
+func TestEncodeQR_Errors(t *testing.T) {
+	if _, err := EncodeQR([]byte("x"), QRErrorCorrection(9)); err == nil {
+		t.Fatalf("expected error for invalid level")
+	}
+	if _, err := EncodeQR(make([]byte, 3000), QRLow); err == nil {
+		t.Fatalf("expected error for oversized data")
+	}
+}
+
+func TestQRCode_SVG(t *testing.T) {
+	q, err := EncodeQR([]byte("hello"), QRMedium)
+	if err != nil {
+		t.Fatalf("EncodeQR error: %v", err)
+	}
+	if q.Version() != 1 || q.Module(-1, 0) || !q.Module(0, 0) {
+		t.Fatalf("unexpected symbol layout")
+	}
+	svg := string(q.SVG(4))
+	if !strings.HasPrefix(svg, "<svg") || !strings.Contains(svg, `viewBox="0 0 29 29"`) || !strings.Contains(svg, "M4,4h1v1h-1z") {
+		t.Fatalf("unexpected SVG %s", svg[:80])
+	}
+}
@@ -0,0 +1,202 @@
+package tronwallet
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// redacted replaces secret values in formatted and logged output.
+const redacted = "[REDACTED]"
+
+// ErrSecretExport is returned by MarshalJSON on types that hold secrets. Use
+// the type's MarshalSecretJSON method to export secrets deliberately.
+var ErrSecretExport = errors.New("refusing to marshal secret material; use MarshalSecretJSON")
+
+// formatRedacted implements fmt.Formatter for types holding secrets. Without
+// it, verbs such as %d and %x print the struct fields through reflection.
+func formatRedacted(f fmt.State, verb rune, v interface {
+	String() string
+	GoString() string
+}) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		fmt.Fprint(f, v.GoString())
+	case verb == 'q':
+		fmt.Fprint(f, strconv.Quote(v.String()))
+	default:
+		fmt.Fprint(f, v.String())
+	}
+}
+
+// String implements fmt.Stringer without revealing the mnemonic or seed.
+func (w TronWallet) String() string {
+	return "TronWallet{Mnemonic: " + redacted + ", Seed: " + redacted + "}"
+}
+
+// GoString implements fmt.GoStringer so %#v does not reveal secrets either.
+func (w TronWallet) GoString() string {
+	return `tronwallet.TronWallet{Mnemonic:"` + redacted + `", Seed:` + redacted + `}`
+}
+
+// Format implements fmt.Formatter so no verb reveals secrets.
+func (w TronWallet) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, w)
+}
+
+// LogValue implements slog.LogValuer with redacted secrets.
+func (w TronWallet) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("mnemonic", redacted),
+		slog.String("seed", redacted),
+	)
+}
+
+// MarshalJSON always fails with ErrSecretExport.
+func (w TronWallet) MarshalJSON() ([]byte, error) {
+	return nil, ErrSecretExport
+}
+
+// MarshalSecretJSON explicitly exports the mnemonic and hex-encoded seed.
+func (w TronWallet) MarshalSecretJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Mnemonic string `json:"mnemonic"`
+		Seed     string `json:"seed"`
+	}{w.Mnemonic, hex.EncodeToString(w.Seed)})
+}
+
+// String implements fmt.Stringer without revealing the key or chain code.
+func (k ExtKey) String() string {
+	return "ExtKey{Key: " + redacted + ", ChainCode: " + redacted + "}"
+}
+
+// GoString implements fmt.GoStringer so %#v does not reveal secrets either.
+func (k ExtKey) GoString() string {
+	return "tronwallet.ExtKey{Key:" + redacted + ", ChainCode:" + redacted + "}"
+}
+
+// Format implements fmt.Formatter so no verb reveals secrets.
+func (k ExtKey) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, k)
+}
+
+// LogValue implements slog.LogValuer with redacted secrets.
+func (k ExtKey) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("key", redacted),
+		slog.String("chain_code", redacted),
+	)
+}
+
+// MarshalJSON always fails with ErrSecretExport.
+func (k ExtKey) MarshalJSON() ([]byte, error) {
+	return nil, ErrSecretExport
+}
+
+// MarshalSecretJSON explicitly exports the hex-encoded key and chain code.
+func (k ExtKey) MarshalSecretJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Key       string `json:"key"`
+		ChainCode string `json:"chain_code"`
+	}{hex.EncodeToString(k.Key), hex.EncodeToString(k.ChainCode)})
+}
+
+// accountJSON is the public JSON form of an Account.
+type accountJSON struct {
+	Address    string `json:"address"`
+	Path       string `json:"path,omitempty"`
+	Index      uint32 `json:"index"`
+	PublicKey  string `json:"public_key"`
+	PrivateKey string `json:"private_key,omitempty"`
+}
+
+func (a Account) publicJSON() accountJSON {
+	return accountJSON{
+		Address:   a.address,
+		Path:      a.path,
+		Index:     a.index,
+		PublicKey: hex.EncodeToString(pubKeyUncompressed(a.publicKey)),
+	}
+}
+
+// String implements fmt.Stringer, showing only public information.
+func (a Account) String() string {
+	if a.path == "" {
+		return fmt.Sprintf("Account{Address: %s, PrivateKey: %s}", a.address, redacted)
+	}
+	return fmt.Sprintf("Account{Address: %s, Path: %s, PrivateKey: %s}", a.address, a.path, redacted)
+}
+
+// GoString implements fmt.GoStringer so %#v does not reveal the private key.
+func (a Account) GoString() string {
+	return fmt.Sprintf("tronwallet.Account{Path:%q, Index:%d, Address:%q, priv:%s}", a.path, a.index, a.address, redacted)
+}
+
+// Format implements fmt.Formatter so no verb reveals secrets.
+func (a Account) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, a)
+}
+
+// LogValue implements slog.LogValuer, logging only public information.
+func (a Account) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("address", a.address),
+		slog.String("path", a.path),
+		slog.String("private_key", redacted),
+	)
+}
+
+// MarshalJSON encodes the account's public information only; the private key
+// is never included.
+func (a Account) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.publicJSON())
+}
+
+// MarshalSecretJSON explicitly exports the account including its private key.
+func (a Account) MarshalSecretJSON() ([]byte, error) {
+	j := a.publicJSON()
+	j.PrivateKey = PrivateKeyToHex(a.priv)
+	return json.Marshal(j)
+}
+
+// String implements fmt.Stringer without revealing the mnemonic words.
+func (p PaperWallet) String() string {
+	return fmt.Sprintf("PaperWallet{Title: %s, Words: %s, Accounts: %d}", p.Title, redacted, len(p.Accounts))
+}
+
+// GoString implements fmt.GoStringer so %#v does not reveal the words either.
+func (p PaperWallet) GoString() string {
+	return fmt.Sprintf("tronwallet.PaperWallet{Title:%q, Words:%s, Accounts:%d}", p.Title, redacted, len(p.Accounts))
+}
+
+// Format implements fmt.Formatter so no verb reveals secrets.
+func (p PaperWallet) Format(f fmt.State, verb rune) {
+	formatRedacted(f, verb, p)
+}
+
+// LogValue implements slog.LogValuer with the mnemonic words redacted.
+func (p PaperWallet) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("title", p.Title),
+		slog.String("words", redacted),
+		slog.Int("accounts", len(p.Accounts)),
+	)
+}
+
+// MarshalJSON always fails with ErrSecretExport.
+func (p PaperWallet) MarshalJSON() ([]byte, error) {
+	return nil, ErrSecretExport
+}
+
+// MarshalSecretJSON explicitly exports the title, mnemonic words and account
+// addresses. Private keys are never included.
+func (p PaperWallet) MarshalSecretJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Title    string               `json:"title"`
+		Words    []string             `json:"words"`
+		Accounts []PaperWalletAccount `json:"accounts"`
+	}{p.Title, p.Words, p.Accounts})
+}
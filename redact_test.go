@@ -0,0 +1,113 @@
+package tronwallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedaction_FormattingDoesNotLeakSecrets(t *testing.T) {
+	w, err := RestoreWallet(testMnemonic)
+	if err != nil {
+		t.Fatalf("RestoreWallet error: %v", err)
+	}
+	a, _ := w.Account(0)
+	ext := masterKey(w.Seed)
+	pw, err := NewPaperWallet(w, []uint32{0}, PaperWalletOptions{IncludePrivateKey: true})
+	if err != nil {
+		t.Fatalf("NewPaperWallet error: %v", err)
+	}
+
+	secrets := []string{"abandon", hex.EncodeToString(w.Seed)[:16], a.PrivateKeyHex()[:16], hex.EncodeToString(ext.Key)[:16]}
+	values := []any{w, *w, ext, *ext, a, *a, pw, *pw}
+	for _, v := range values {
+		for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x", "%X", "%d", "%o", "%b", "%e", "%f", "%g", "%t", "%c", "%U", "%10.3v"} {
+			out := fmt.Sprintf(format, v)
+			for _, s := range secrets {
+				if strings.Contains(out, s) {
+					t.Fatalf("%s of %T leaked secret: %s", format, v, out)
+				}
+			}
+			if !strings.Contains(out, redacted) {
+				t.Fatalf("%s of %T missing redaction marker: %s", format, v, out)
+			}
+		}
+	}
+
+	// Verbs fmt rejects print the value by reflection, bypassing Format.
+	// Account keeps its key behind a pointer, which is printed as an address.
+	for _, format := range []string{"%p", "%w", "%!"} {
+		for _, v := range []any{a, *a} {
+			out := fmt.Sprintf(format, v)
+			if strings.Contains(out, a.PrivateKeyHex()[:16]) {
+				t.Fatalf("%s of %T leaked secret: %s", format, v, out)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Info("test", "wallet", w, "ext", ext, "account", a, "paper", pw)
+	for _, s := range secrets {
+		if strings.Contains(buf.String(), s) {
+			t.Fatalf("slog output leaked secret: %s", buf.String())
+		}
+	}
+	if !strings.Contains(buf.String(), a.Address()) {
+		t.Fatalf("slog output should include the public address: %s", buf.String())
+	}
+}
+
+func TestRedaction_JSON(t *testing.T) {
+	w, _ := RestoreWallet(testMnemonic)
+	ext := masterKey(w.Seed)
+	a, _ := w.Account(0)
+
+	pw, _ := NewPaperWallet(w, []uint32{0}, PaperWalletOptions{IncludePrivateKey: true})
+	for _, v := range []any{w, *w, ext, struct{ W *TronWallet }{w}, pw} {
+		if _, err := json.Marshal(v); !errors.Is(err, ErrSecretExport) {
+			t.Fatalf("expected ErrSecretExport marshalling %T, got %v", v, err)
+		}
+	}
+
+	b, err := w.MarshalSecretJSON()
+	if err != nil {
+		t.Fatalf("MarshalSecretJSON error: %v", err)
+	}
+	var exported struct{ Mnemonic, Seed string }
+	if err := json.Unmarshal(b, &exported); err != nil || exported.Mnemonic != testMnemonic || exported.Seed != hex.EncodeToString(w.Seed) {
+		t.Fatalf("unexpected wallet export %s (%v)", b, err)
+	}
+
+	b, err = ext.MarshalSecretJSON()
+	if err != nil || !strings.Contains(string(b), hex.EncodeToString(ext.ChainCode)) {
+		t.Fatalf("unexpected ExtKey export %s (%v)", b, err)
+	}
+
+	b, err = json.Marshal(a)
+	if err != nil {
+		t.Fatalf("Account MarshalJSON error: %v", err)
+	}
+	if strings.Contains(string(b), a.PrivateKeyHex()) || !strings.Contains(string(b), a.Address()) {
+		t.Fatalf("unexpected public account JSON %s", b)
+	}
+	b, err = a.MarshalSecretJSON()
+	if err != nil || !strings.Contains(string(b), a.PrivateKeyHex()) {
+		t.Fatalf("unexpected secret account JSON %s (%v)", b, err)
+	}
+
+	b, err = pw.MarshalSecretJSON()
+	if err != nil || !strings.Contains(string(b), `"abandon"`) || strings.Contains(string(b), a.PrivateKeyHex()) {
+		t.Fatalf("unexpected paper wallet export %s (%v)", b, err)
+	}
+
+	imported := NewAccount(a.PrivateKey())
+	if s := imported.String(); strings.Contains(s, "Path") || !strings.Contains(s, imported.Address()) {
+		t.Fatalf("unexpected imported account string %s", s)
+	}
+}
@@ -0,0 +1,47 @@
+package tronwallet
+
+import (
+	"crypto/ecdsa"
+	"errors"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	secpecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// SignatureLength is the size of a recoverable TRON signature, r || s || v.
+const SignatureLength = 65
+
+// ErrInvalidHash is returned when a hash to be signed or verified is not 32
+// bytes long.
+var ErrInvalidHash = errors.New("hash must be 32 bytes")
+
+// SignHash signs a 32-byte hash, such as a TRON transaction ID, and returns
+// the 65-byte r || s || v signature expected by TRON nodes, where v is 27 or
+// 28. Nonces are derived deterministically per RFC 6979 and s is normalized
+// to the lower half of the curve order, so the same key and hash always
+// produce the same canonical signature.
+func SignHash(priv *ecdsa.PrivateKey, hash []byte) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, ErrInvalidHash
+	}
+	if !validPrivateKey(priv) {
+		return nil, errInvalidPrivateKey
+	}
+	key := secp256k1.PrivKeyFromBytes(PrivateKeyToBytes(priv))
+	defer key.Zero()
+
+	// SignCompact returns <27 + recovery code> || r || s with a low-S value.
+	compact := secpecdsa.SignCompact(key, hash, false)
+	sig := make([]byte, SignatureLength)
+	copy(sig, compact[1:])
+	sig[64] = compact[0]
+	return sig, nil
+}
+
+// errInvalidPrivateKey is returned when signing with a nil or out-of-range key.
+var errInvalidPrivateKey = errors.New("invalid private key")
+
+// validPrivateKey reports whether priv is a usable secp256k1 signing key.
+func validPrivateKey(priv *ecdsa.PrivateKey) bool {
+	return priv != nil && priv.D != nil && priv.D.Sign() > 0 && priv.D.Cmp(secp256k1.S256().N) < 0
+}
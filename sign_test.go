@@ -0,0 +1,70 @@
+package tronwallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+func TestSignHash_RFC6979Vector(t *testing.T) {
+	// private key 1 signing SHA-256("Satoshi Nakamoto"), a widely published
+	// RFC 6979 secp256k1 vector (low-S form)
+	one := make([]byte, 32)
+	one[31] = 1
+	priv := secp256k1.PrivKeyFromBytes(one).ToECDSA()
+	hash := sha256.Sum256([]byte("Satoshi Nakamoto"))
+
+	sig, err := SignHash(priv, hash[:])
+	if err != nil {
+		t.Fatalf("SignHash error: %v", err)
+	}
+	want := "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d8" +
+		"2442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5"
+	if hex.EncodeToString(sig[:64]) != want {
+		t.Fatalf("unexpected signature %x", sig)
+	}
+	if sig[64] != 27 && sig[64] != 28 {
+		t.Fatalf("unexpected v %d", sig[64])
+	}
+}
+
+func TestSignHash_DeterministicLowS(t *testing.T) {
+	w, _ := RestoreWallet(testMnemonic)
+	priv, _ := w.Derive(0)
+	halfOrder := new(big.Int).Rsh(secp256k1.S256().N, 1)
+
+	for i := 0; i < 32; i++ {
+		hash := sha256.Sum256([]byte{byte(i)})
+		a, err := SignHash(priv, hash[:])
+		if err != nil {
+			t.Fatalf("SignHash error: %v", err)
+		}
+		b, _ := SignHash(priv, hash[:])
+		if hex.EncodeToString(a) != hex.EncodeToString(b) {
+			t.Fatalf("signature not deterministic")
+		}
+		if new(big.Int).SetBytes(a[32:64]).Cmp(halfOrder) > 0 {
+			t.Fatalf("signature has high S")
+		}
+	}
+}
+
+func TestSignHash_Errors(t *testing.T) {
+	w, _ := RestoreWallet(testMnemonic)
+	priv, _ := w.Derive(0)
+	if _, err := SignHash(priv, make([]byte, 31)); !errors.Is(err, ErrInvalidHash) {
+		t.Fatalf("expected ErrInvalidHash, got %v", err)
+	}
+	bad := &ecdsa.PrivateKey{D: big.NewInt(0)}
+	if _, err := SignHash(bad, make([]byte, 32)); err == nil {
+		t.Fatalf("expected error for zero key")
+	}
+	if _, err := SignHash(nil, make([]byte, 32)); err == nil {
+		t.Fatalf("expected error for nil key")
+	}
+}
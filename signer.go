@@ -0,0 +1,297 @@
+package tronwallet
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// Signer is a TRON key that can sign 32-byte hashes without necessarily
+// exposing the private key. Account is the in-process implementation and
+// RemoteSigner delegates to a SignerServer over a Unix socket; an HSM can be
+// plugged in by implementing the same three methods.
+type Signer interface {
+	// Address returns the Base58 TRON address of the key.
+	Address() string
+	// PublicKey returns the secp256k1 public key.
+	PublicKey() *ecdsa.PublicKey
+	// SignHash returns the 65-byte r || s || v signature of a 32-byte hash.
+	SignHash(hash []byte) ([]byte, error)
+}
+
+var (
+	_ Signer = (*Account)(nil)
+	_ Signer = (*RemoteSigner)(nil)
+)
+
+// signerRequest and signerResponse are the newline-delimited JSON messages
+// exchanged between RemoteSigner and SignerServer.
+type signerRequest struct {
+	Method string `json:"method"`
+	Hash   string `json:"hash,omitempty"`
+}
+
+type signerResponse struct {
+	Address   string `json:"address,omitempty"`
+	PublicKey string `json:"public_key,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+const (
+	signerMethodInfo = "info"
+	signerMethodSign = "sign_hash"
+)
+
+// SignerServer exposes a Signer to RemoteSigner clients. It is intended to
+// run as a small daemon that holds keys in a separate process.
+type SignerServer struct {
+	signer Signer
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	closed   bool
+}
+
+// NewSignerServer returns a server that signs with s.
+func NewSignerServer(s Signer) *SignerServer {
+	return &SignerServer{signer: s, conns: map[net.Conn]struct{}{}}
+}
+
+// ListenAndServe listens on the Unix socket at path, restricted to the
+// current user, and serves requests until Close is called. A stale socket
+// left at path by the current user is replaced; any other existing file is
+// an error.
+func (srv *SignerServer) ListenAndServe(path string) error {
+	l, err := listenPrivateUnix(path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+	return srv.Serve(l)
+}
+
+// listenPrivateUnix creates the socket inside a fresh 0700 directory, where
+// no other user can reach it, restricts it to 0600 and only then moves it
+// to path.
+func listenPrivateUnix(path string) (net.Listener, error) {
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode().Type() != os.ModeSocket || !ownedByCurrentUser(fi) {
+			return nil, fmt.Errorf("signer socket %s: path exists and is not a socket owned by the current user", path)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".signer-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	tmp := filepath.Join(dir, "sock")
+	l, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err = os.Chmod(tmp, 0o600); err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Serve accepts connections on l until Close is called. It returns nil after
+// Close and the accept error otherwise.
+func (srv *SignerServer) Serve(l net.Listener) error {
+	srv.mu.Lock()
+	if srv.closed {
+		srv.mu.Unlock()
+		return l.Close()
+	}
+	srv.listener = l
+	srv.mu.Unlock()
+	for {
+		conn, err := l.Accept()
+		srv.mu.Lock()
+		if srv.closed {
+			srv.mu.Unlock()
+			if conn != nil {
+				conn.Close()
+			}
+			return nil
+		}
+		if err != nil {
+			srv.mu.Unlock()
+			return err
+		}
+		srv.conns[conn] = struct{}{}
+		srv.mu.Unlock()
+		go srv.serveConn(conn)
+	}
+}
+
+// Close stops the server and closes all open connections.
+func (srv *SignerServer) Close() error {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.closed = true
+	var err error
+	if srv.listener != nil {
+		err = srv.listener.Close()
+		srv.listener = nil
+	}
+	for c := range srv.conns {
+		c.Close()
+		delete(srv.conns, c)
+	}
+	return err
+}
+
+func (srv *SignerServer) serveConn(conn net.Conn) {
+	defer func() {
+		srv.mu.Lock()
+		delete(srv.conns, conn)
+		srv.mu.Unlock()
+		conn.Close()
+	}()
+	dec := json.NewDecoder(bufio.NewReader(conn))
+	enc := json.NewEncoder(conn)
+	for {
+		var req signerRequest
+		if err := dec.Decode(&req); err != nil {
+			return
+		}
+		if err := enc.Encode(srv.handle(req)); err != nil {
+			return
+		}
+	}
+}
+
+func (srv *SignerServer) handle(req signerRequest) signerResponse {
+	switch req.Method {
+	case signerMethodInfo:
+		return signerResponse{
+			Address:   srv.signer.Address(),
+			PublicKey: hex.EncodeToString(pubKeyUncompressed(srv.signer.PublicKey())),
+		}
+	case signerMethodSign:
+		hash, err := hex.DecodeString(req.Hash)
+		if err != nil {
+			return signerResponse{Error: "invalid hash encoding"}
+		}
+		sig, err := srv.signer.SignHash(hash)
+		if err != nil {
+			return signerResponse{Error: err.Error()}
+		}
+		return signerResponse{Signature: hex.EncodeToString(sig)}
+	}
+	return signerResponse{Error: fmt.Sprintf("unknown method %q", req.Method)}
+}
+
+// RemoteSigner is a Signer backed by a SignerServer reachable over a Unix
+// socket. Every signature it returns is checked to recover to the server's
+// address, so a misbehaving server cannot substitute another key.
+type RemoteSigner struct {
+	mu      sync.Mutex
+	conn    net.Conn
+	dec     *json.Decoder
+	enc     *json.Encoder
+	address string
+	pub     *ecdsa.PublicKey
+}
+
+// DialRemoteSigner connects to the SignerServer listening on the Unix socket
+// at path and fetches its address and public key.
+func DialRemoteSigner(path string) (*RemoteSigner, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	r := &RemoteSigner{conn: conn, dec: json.NewDecoder(bufio.NewReader(conn)), enc: json.NewEncoder(conn)}
+	resp, err := r.call(signerRequest{Method: signerMethodInfo})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	pubBytes, err := hex.DecodeString(resp.PublicKey)
+	if err != nil {
+		conn.Close()
+		return nil, errors.New("remote signer: invalid public key")
+	}
+	pub, err := secp256k1.ParsePubKey(pubBytes)
+	if err != nil {
+		conn.Close()
+		return nil, errors.New("remote signer: invalid public key")
+	}
+	r.pub = pub.ToECDSA()
+	r.address = TronAddressFromPublic(r.pub)
+	if r.address != resp.Address {
+		conn.Close()
+		return nil, errors.New("remote signer: address does not match public key")
+	}
+	return r, nil
+}
+
+// Address returns the remote key's Base58 TRON address.
+func (r *RemoteSigner) Address() string {
+	return r.address
+}
+
+// PublicKey returns the remote key's public key.
+func (r *RemoteSigner) PublicKey() *ecdsa.PublicKey {
+	return r.pub
+}
+
+// SignHash asks the server to sign hash and verifies the result.
+func (r *RemoteSigner) SignHash(hash []byte) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, ErrInvalidHash
+	}
+	resp, err := r.call(signerRequest{Method: signerMethodSign, Hash: hex.EncodeToString(hash)})
+	if err != nil {
+		return nil, err
+	}
+	sig, err := hex.DecodeString(resp.Signature)
+	if err != nil {
+		return nil, errors.New("remote signer: invalid signature encoding")
+	}
+	if err := VerifySignature(hash, sig, r.address); err != nil {
+		return nil, fmt.Errorf("remote signer: %w", err)
+	}
+	return sig, nil
+}
+
+// Close closes the connection to the server.
+func (r *RemoteSigner) Close() error {
+	return r.conn.Close()
+}
+
+func (r *RemoteSigner) call(req signerRequest) (*signerResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.enc.Encode(req); err != nil {
+		return nil, err
+	}
+	var resp signerResponse
+	if err := r.dec.Decode(&resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("remote signer: %s", resp.Error)
+	}
+	return &resp, nil
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package tronwallet
+
+import "os"
+
+// ownedByCurrentUser cannot check ownership here, so existing sockets are
+// never replaced.
+func ownedByCurrentUser(os.FileInfo) bool {
+	return false
+}
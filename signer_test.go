@@ -0,0 +1,237 @@
+package tronwallet
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func startTestSigner(t *testing.T, s Signer) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "signer.sock")
+	srv := NewSignerServer(s)
+	done := make(chan error, 1)
+	go func() { done <- srv.ListenAndServe(path) }()
+	t.Cleanup(func() {
+		srv.Close()
+		if err := <-done; err != nil {
+			t.Errorf("ListenAndServe error: %v", err)
+		}
+	})
+	for i := 0; i < 100; i++ {
+		if c, err := net.Dial("unix", path); err == nil {
+			c.Close()
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return path
+}
+
+func TestRemoteSigner_RoundTrip(t *testing.T) {
+	w, _ := RestoreWallet(testMnemonic)
+	a, _ := w.Account(0)
+	path := startTestSigner(t, a)
+
+	r, err := DialRemoteSigner(path)
+	if err != nil {
+		t.Fatalf("DialRemoteSigner error: %v", err)
+	}
+	defer r.Close()
+	if r.Address() != a.Address() || !r.PublicKey().Equal(a.PublicKey()) {
+		t.Fatalf("remote key mismatch: %s", r.Address())
+	}
+
+	hash := sha256.Sum256([]byte("remote"))
+	sig, err := r.SignHash(hash[:])
+	if err != nil {
+		t.Fatalf("SignHash error: %v", err)
+	}
+	local, _ := a.SignHash(hash[:])
+	if string(sig) != string(local) {
+		t.Fatalf("remote signature differs from local")
+	}
+
+	msgSig, err := SignMessageV2With(r, []byte("hello"))
+	if err != nil || VerifyMessageV2([]byte("hello"), msgSig, a.Address()) != nil {
+		t.Fatalf("SignMessageV2With over remote signer failed: %v", err)
+	}
+
+	if _, err := r.SignHash([]byte{1, 2, 3}); !errors.Is(err, ErrInvalidHash) {
+		t.Fatalf("expected ErrInvalidHash, got %v", err)
+	}
+}
+
+// lyingSigner claims one key but signs with another.
+type lyingSigner struct {
+	*Account
+	other *Account
+}
+
+func (l lyingSigner) SignHash(hash []byte) ([]byte, error) {
+	return l.other.SignHash(hash)
+}
+
+func TestRemoteSigner_RejectsWrongKey(t *testing.T) {
+	w, _ := RestoreWallet(testMnemonic)
+	a0, _ := w.Account(0)
+	a1, _ := w.Account(1)
+	path := startTestSigner(t, lyingSigner{a0, a1})
+
+	r, err := DialRemoteSigner(path)
+	if err != nil {
+		t.Fatalf("DialRemoteSigner error: %v", err)
+	}
+	defer r.Close()
+	hash := sha256.Sum256([]byte("x"))
+	if _, err := r.SignHash(hash[:]); !errors.Is(err, ErrSignerMismatch) {
+		t.Fatalf("expected ErrSignerMismatch, got %v", err)
+	}
+}
+
+func TestSignerServer_SocketPermissions(t *testing.T) {
+	w, _ := RestoreWallet(testMnemonic)
+	a, _ := w.Account(0)
+	path := startTestSigner(t, a)
+	fi, err := os.Lstat(path)
+	if err != nil || fi.Mode().Type() != os.ModeSocket || fi.Mode().Perm() != 0o600 {
+		t.Fatalf("unexpected socket %v, %v", fi, err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Fatalf("temporary directory left behind: %v", entries)
+	}
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	os.WriteFile(file, nil, 0o600)
+	if err := NewSignerServer(a).ListenAndServe(file); err == nil {
+		t.Fatalf("expected error for a path that is not a socket")
+	}
+
+	// A stale socket of the current user is replaced.
+	stale := filepath.Join(dir, "stale.sock")
+	l, err := net.Listen("unix", stale)
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	l.Close()
+	srv := NewSignerServer(a)
+	done := make(chan error, 1)
+	go func() { done <- srv.ListenAndServe(stale) }()
+	var r *RemoteSigner
+	for i := 0; i < 100 && r == nil; i++ {
+		time.Sleep(10 * time.Millisecond)
+		r, _ = DialRemoteSigner(stale)
+	}
+	if r == nil {
+		t.Fatalf("server did not replace the stale socket")
+	}
+	r.Close()
+	srv.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("ListenAndServe error: %v", err)
+	}
+
+	// A socket owned by another user is refused.
+	if os.Geteuid() != 0 {
+		return
+	}
+	foreign := filepath.Join(dir, "foreign.sock")
+	if l, err = net.Listen("unix", foreign); err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	defer l.Close()
+	if err := os.Lchown(foreign, 65534, 65534); err != nil {
+		t.Fatalf("chown error: %v", err)
+	}
+	if err := NewSignerServer(a).ListenAndServe(foreign); err == nil {
+		t.Fatalf("expected error for another user's socket")
+	}
+}
+
+func TestSignerServer_Protocol(t *testing.T) {
+	w, _ := RestoreWallet(testMnemonic)
+	a, _ := w.Account(0)
+	path := startTestSigner(t, a)
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("dial error: %v", err)
+	}
+	defer conn.Close()
+	dec := json.NewDecoder(bufio.NewReader(conn))
+	for _, tc := range []struct {
+		req  signerRequest
+		want string
+	}{
+		{signerRequest{Method: "export_key"}, "unknown method"},
+		{signerRequest{Method: signerMethodSign, Hash: "zz"}, "invalid hash encoding"},
+		{signerRequest{Method: signerMethodSign, Hash: "00"}, ErrInvalidHash.Error()},
+	} {
+		if err := json.NewEncoder(conn).Encode(tc.req); err != nil {
+			t.Fatalf("encode error: %v", err)
+		}
+		var resp signerResponse
+		if err := dec.Decode(&resp); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		if !strings.Contains(resp.Error, tc.want) {
+			t.Fatalf("%s: expected error %q, got %+v", tc.req.Method, tc.want, resp)
+		}
+	}
+}
+
+func TestDialRemoteSigner_Errors(t *testing.T) {
+	if _, err := DialRemoteSigner(filepath.Join(t.TempDir(), "missing.sock")); err == nil {
+		t.Fatalf("expected error dialing missing socket")
+	}
+
+	// A server whose advertised address does not match its public key.
+	path := filepath.Join(t.TempDir(), "bad.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	defer l.Close()
+	w, _ := RestoreWallet(testMnemonic)
+	a0, _ := w.Account(0)
+	a1, _ := w.Account(1)
+	responses := []signerResponse{
+		{Address: a1.Address(), PublicKey: hexPub(a0)},
+		{Address: a0.Address(), PublicKey: "zz"},
+		{Address: a0.Address(), PublicKey: "0400"},
+		{Error: "unavailable"},
+	}
+	go func() {
+		for _, resp := range responses {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			var req signerRequest
+			json.NewDecoder(c).Decode(&req)
+			json.NewEncoder(c).Encode(resp)
+			c.Close()
+		}
+	}()
+	for range responses {
+		if _, err := DialRemoteSigner(path); err == nil {
+			t.Fatalf("expected DialRemoteSigner to fail")
+		}
+	}
+}
+
+func hexPub(a *Account) string {
+	b, _ := json.Marshal(a)
+	var j accountJSON
+	json.Unmarshal(b, &j)
+	return j.PublicKey
+}
@@ -0,0 +1,14 @@
+//go:build unix
+
+package tronwallet
+
+import (
+	"os"
+	"syscall"
+)
+
+// ownedByCurrentUser reports whether fi belongs to the effective user.
+func ownedByCurrentUser(fi os.FileInfo) bool {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	return ok && int(st.Uid) == os.Geteuid()
+}
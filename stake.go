@@ -0,0 +1,413 @@
+package tronwallet
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ResourceCode selects the resource staked or delegated under Stake 2.0.
+type ResourceCode int32
+
+// Resource codes, as in java-tron's ResourceCode enum.
+const (
+	ResourceBandwidth ResourceCode = 0
+	ResourceEnergy    ResourceCode = 1
+	ResourceTronPower ResourceCode = 2
+)
+
+// String returns the java-tron name of the resource.
+func (r ResourceCode) String() string {
+	switch r {
+	case ResourceBandwidth:
+		return "BANDWIDTH"
+	case ResourceEnergy:
+		return "ENERGY"
+	case ResourceTronPower:
+		return "TRON_POWER"
+	}
+	return fmt.Sprintf("ResourceCode(%d)", int32(r))
+}
+
+// minStakeAmount is the smallest amount java-tron accepts for freezing or
+// delegating: 1 TRX in sun.
+const minStakeAmount = 1_000_000
+
+// checkResource accepts bandwidth and energy.
+func checkResource(r ResourceCode) error {
+	if r != ResourceBandwidth && r != ResourceEnergy {
+		return fmt.Errorf("unsupported resource %s", r)
+	}
+	return nil
+}
+
+// FreezeBalanceV2Contract stakes FrozenBalance sun for Resource.
+type FreezeBalanceV2Contract struct {
+	OwnerAddress  string
+	FrozenBalance int64
+	Resource      ResourceCode
+}
+
+// NewFreezeBalanceV2Transaction builds an unsigned Stake 2.0 freeze of amount
+// sun for resource.
+func NewFreezeBalanceV2Transaction(opts TransactionOptions, owner string, amount int64, resource ResourceCode) (*Transaction, error) {
+	return NewTransaction(opts, &FreezeBalanceV2Contract{OwnerAddress: owner, FrozenBalance: amount, Resource: resource})
+}
+
+// ContractType implements ContractParameter.
+func (c *FreezeBalanceV2Contract) ContractType() ContractType { return FreezeBalanceV2ContractType }
+
+func (c *FreezeBalanceV2Contract) marshalProto() ([]byte, error) {
+	owner, err := contractAddress("owner address", c.OwnerAddress)
+	if err != nil {
+		return nil, err
+	}
+	if c.FrozenBalance < minStakeAmount {
+		return nil, fmt.Errorf("%w: frozen balance must be at least 1 TRX", ErrInvalidAmount)
+	}
+	if c.Resource != ResourceTronPower {
+		if err := checkResource(c.Resource); err != nil {
+			return nil, err
+		}
+	}
+	var w protoWriter
+	w.bytes(1, owner)
+	w.int64(2, c.FrozenBalance)
+	w.int64(3, int64(c.Resource))
+	return w.buf, nil
+}
+
+func parseFreezeBalanceV2Contract(b []byte) (ContractParameter, error) {
+	c := &FreezeBalanceV2Contract{}
+	if err := parseStakeFields(b, &c.OwnerAddress, &c.FrozenBalance, &c.Resource); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// UnfreezeBalanceV2Contract starts unstaking UnfreezeBalance sun of
+// Resource. The TRX becomes withdrawable after the unfreezing period.
+type UnfreezeBalanceV2Contract struct {
+	OwnerAddress    string
+	UnfreezeBalance int64
+	Resource        ResourceCode
+}
+
+// NewUnfreezeBalanceV2Transaction builds an unsigned Stake 2.0 unfreeze of
+// amount sun staked for resource.
+func NewUnfreezeBalanceV2Transaction(opts TransactionOptions, owner string, amount int64, resource ResourceCode) (*Transaction, error) {
+	return NewTransaction(opts, &UnfreezeBalanceV2Contract{OwnerAddress: owner, UnfreezeBalance: amount, Resource: resource})
+}
+
+// ContractType implements ContractParameter.
+func (c *UnfreezeBalanceV2Contract) ContractType() ContractType {
+	return UnfreezeBalanceV2ContractType
+}
+
+func (c *UnfreezeBalanceV2Contract) marshalProto() ([]byte, error) {
+	owner, err := contractAddress("owner address", c.OwnerAddress)
+	if err != nil {
+		return nil, err
+	}
+	if c.UnfreezeBalance <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if c.Resource != ResourceTronPower {
+		if err := checkResource(c.Resource); err != nil {
+			return nil, err
+		}
+	}
+	var w protoWriter
+	w.bytes(1, owner)
+	w.int64(2, c.UnfreezeBalance)
+	w.int64(3, int64(c.Resource))
+	return w.buf, nil
+}
+
+func parseUnfreezeBalanceV2Contract(b []byte) (ContractParameter, error) {
+	c := &UnfreezeBalanceV2Contract{}
+	if err := parseStakeFields(b, &c.OwnerAddress, &c.UnfreezeBalance, &c.Resource); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// parseStakeFields decodes the owner (1), amount (2) and resource (3) fields
+// shared by the freeze and unfreeze contracts.
+func parseStakeFields(b []byte, owner *string, amount *int64, resource *ResourceCode) error {
+	fields, err := parseProto(b)
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		switch f.Num {
+		case 1:
+			*owner, err = f.address()
+		case 2:
+			*amount, err = f.int64()
+		case 3:
+			var r int64
+			r, err = f.int64()
+			*resource = ResourceCode(r)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithdrawExpireUnfreezeContract withdraws all unstaked TRX whose unfreezing
+// period has passed.
+type WithdrawExpireUnfreezeContract struct {
+	OwnerAddress string
+}
+
+// NewWithdrawExpireUnfreezeTransaction builds an unsigned withdrawal of
+// expired unfreezes.
+func NewWithdrawExpireUnfreezeTransaction(opts TransactionOptions, owner string) (*Transaction, error) {
+	return NewTransaction(opts, &WithdrawExpireUnfreezeContract{OwnerAddress: owner})
+}
+
+// ContractType implements ContractParameter.
+func (c *WithdrawExpireUnfreezeContract) ContractType() ContractType {
+	return WithdrawExpireUnfreezeContractType
+}
+
+func (c *WithdrawExpireUnfreezeContract) marshalProto() ([]byte, error) {
+	return marshalOwnerOnly(c.OwnerAddress)
+}
+
+func parseWithdrawExpireUnfreezeContract(b []byte) (ContractParameter, error) {
+	c := &WithdrawExpireUnfreezeContract{}
+	if err := parseOwnerOnly(b, &c.OwnerAddress); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// CancelAllUnfreezeV2Contract cancels all pending unfreezes, restaking the
+// TRX that is still unfreezing and withdrawing what has expired.
+type CancelAllUnfreezeV2Contract struct {
+	OwnerAddress string
+}
+
+// NewCancelAllUnfreezeV2Transaction builds an unsigned cancellation of all
+// pending unfreezes.
+func NewCancelAllUnfreezeV2Transaction(opts TransactionOptions, owner string) (*Transaction, error) {
+	return NewTransaction(opts, &CancelAllUnfreezeV2Contract{OwnerAddress: owner})
+}
+
+// ContractType implements ContractParameter.
+func (c *CancelAllUnfreezeV2Contract) ContractType() ContractType {
+	return CancelAllUnfreezeV2ContractType
+}
+
+func (c *CancelAllUnfreezeV2Contract) marshalProto() ([]byte, error) {
+	return marshalOwnerOnly(c.OwnerAddress)
+}
+
+func parseCancelAllUnfreezeV2Contract(b []byte) (ContractParameter, error) {
+	c := &CancelAllUnfreezeV2Contract{}
+	if err := parseOwnerOnly(b, &c.OwnerAddress); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// marshalOwnerOnly encodes a contract whose only field is owner_address.
+func marshalOwnerOnly(address string) ([]byte, error) {
+	owner, err := contractAddress("owner address", address)
+	if err != nil {
+		return nil, err
+	}
+	var w protoWriter
+	w.bytes(1, owner)
+	return w.buf, nil
+}
+
+func parseOwnerOnly(b []byte, owner *string) error {
+	fields, err := parseProto(b)
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		if f.Num == 1 {
+			if *owner, err = f.address(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DelegateResourceContract delegates the Resource obtained from Balance sun
+// of staked TRX to ReceiverAddress. With Lock set the delegation cannot be
+// undone for LockPeriod blocks (three days when LockPeriod is zero).
+type DelegateResourceContract struct {
+	OwnerAddress    string
+	Resource        ResourceCode
+	Balance         int64
+	ReceiverAddress string
+	Lock            bool
+	LockPeriod      int64
+}
+
+// NewDelegateResourceTransaction builds an unsigned delegation of the
+// resource backed by balance sun to receiver. lockPeriod is in blocks and
+// requires lock.
+func NewDelegateResourceTransaction(opts TransactionOptions, owner, receiver string, balance int64, resource ResourceCode, lock bool, lockPeriod int64) (*Transaction, error) {
+	return NewTransaction(opts, &DelegateResourceContract{
+		OwnerAddress:    owner,
+		Resource:        resource,
+		Balance:         balance,
+		ReceiverAddress: receiver,
+		Lock:            lock,
+		LockPeriod:      lockPeriod,
+	})
+}
+
+// ContractType implements ContractParameter.
+func (c *DelegateResourceContract) ContractType() ContractType { return DelegateResourceContractType }
+
+func (c *DelegateResourceContract) marshalProto() ([]byte, error) {
+	owner, receiver, err := delegationAddresses(c.OwnerAddress, c.ReceiverAddress)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResource(c.Resource); err != nil {
+		return nil, err
+	}
+	if c.Balance < minStakeAmount {
+		return nil, fmt.Errorf("%w: delegated balance must be at least 1 TRX", ErrInvalidAmount)
+	}
+	if c.LockPeriod < 0 || (c.LockPeriod > 0 && !c.Lock) {
+		return nil, errors.New("lock period requires lock and must not be negative")
+	}
+	var w protoWriter
+	w.bytes(1, owner)
+	w.int64(2, int64(c.Resource))
+	w.int64(3, c.Balance)
+	w.bytes(4, receiver)
+	w.bool(5, c.Lock)
+	w.int64(6, c.LockPeriod)
+	return w.buf, nil
+}
+
+func parseDelegateResourceContract(b []byte) (ContractParameter, error) {
+	fields, err := parseProto(b)
+	if err != nil {
+		return nil, err
+	}
+	c := &DelegateResourceContract{}
+	for _, f := range fields {
+		switch f.Num {
+		case 1:
+			c.OwnerAddress, err = f.address()
+		case 2:
+			var r int64
+			r, err = f.int64()
+			c.Resource = ResourceCode(r)
+		case 3:
+			c.Balance, err = f.int64()
+		case 4:
+			c.ReceiverAddress, err = f.address()
+		case 5:
+			var lock uint64
+			lock, err = f.uint64()
+			c.Lock = lock != 0
+		case 6:
+			c.LockPeriod, err = f.int64()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// UnDelegateResourceContract reclaims the Resource backed by Balance sun
+// previously delegated to ReceiverAddress.
+type UnDelegateResourceContract struct {
+	OwnerAddress    string
+	Resource        ResourceCode
+	Balance         int64
+	ReceiverAddress string
+}
+
+// NewUnDelegateResourceTransaction builds an unsigned reclaim of balance sun
+// of resource delegated to receiver.
+func NewUnDelegateResourceTransaction(opts TransactionOptions, owner, receiver string, balance int64, resource ResourceCode) (*Transaction, error) {
+	return NewTransaction(opts, &UnDelegateResourceContract{
+		OwnerAddress:    owner,
+		Resource:        resource,
+		Balance:         balance,
+		ReceiverAddress: receiver,
+	})
+}
+
+// ContractType implements ContractParameter.
+func (c *UnDelegateResourceContract) ContractType() ContractType {
+	return UnDelegateResourceContractType
+}
+
+func (c *UnDelegateResourceContract) marshalProto() ([]byte, error) {
+	owner, receiver, err := delegationAddresses(c.OwnerAddress, c.ReceiverAddress)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResource(c.Resource); err != nil {
+		return nil, err
+	}
+	if c.Balance <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	var w protoWriter
+	w.bytes(1, owner)
+	w.int64(2, int64(c.Resource))
+	w.int64(3, c.Balance)
+	w.bytes(4, receiver)
+	return w.buf, nil
+}
+
+func parseUnDelegateResourceContract(b []byte) (ContractParameter, error) {
+	fields, err := parseProto(b)
+	if err != nil {
+		return nil, err
+	}
+	c := &UnDelegateResourceContract{}
+	for _, f := range fields {
+		switch f.Num {
+		case 1:
+			c.OwnerAddress, err = f.address()
+		case 2:
+			var r int64
+			r, err = f.int64()
+			c.Resource = ResourceCode(r)
+		case 3:
+			c.Balance, err = f.int64()
+		case 4:
+			c.ReceiverAddress, err = f.address()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// delegationAddresses decodes the owner and receiver of a delegation, which
+// must differ.
+func delegationAddresses(ownerAddress, receiverAddress string) ([]byte, []byte, error) {
+	owner, err := contractAddress("owner address", ownerAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	receiver, err := contractAddress("receiver address", receiverAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	if string(owner) == string(receiver) {
+		return nil, nil, errors.New("cannot delegate to the owner address")
+	}
+	return owner, receiver, nil
+}
@@ -0,0 +1,140 @@
+package tronwallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStakeTransactions_MatchJavaTron(t *testing.T) {
+	from, to := "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK"
+	tx, err := NewFreezeBalanceV2Transaction(testTxOptions(), from, 10_000_000, ResourceEnergy)
+	if err != nil {
+		t.Fatalf("NewFreezeBalanceV2Transaction error: %v", err)
+	}
+	want := "0a02abcd2208112233445566778840e0a499ffbc315a5a083612560a34747970652e676f6f676c65617069732e636f6d2f70726f746f636f6c2e467265657a6542616c616e63655632436f6e7472616374121e0a1541c8599111f29c1e1e061265b4af93ea1f274ad78a1080ade20418017080d095ffbc31"
+	if got := hex.EncodeToString(tx.RawData); got != want {
+		t.Fatalf("freeze raw data mismatch:\n got %s\nwant %s", got, want)
+	}
+
+	tx, err = NewDelegateResourceTransaction(testTxOptions(), from, to, 5_000_000, ResourceEnergy, true, 28800)
+	if err != nil {
+		t.Fatalf("NewDelegateResourceTransaction error: %v", err)
+	}
+	want = "0a02abcd2208112233445566778840e0a499ffbc315a78083912740a35747970652e676f6f676c65617069732e636f6d2f70726f746f636f6c2e44656c65676174655265736f75726365436f6e7472616374123b0a1541c8599111f29c1e1e061265b4af93ea1f274ad78a100118c096b102221541b6e708a39781c96bd399c7657780ff9fe9f052a828013080e1017080d095ffbc31"
+	if got := hex.EncodeToString(tx.RawData); got != want {
+		t.Fatalf("delegate raw data mismatch:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestStakeTransactions_RoundTrip(t *testing.T) {
+	from, to := "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK"
+	build := map[ContractParameter]func() (*Transaction, error){
+		&FreezeBalanceV2Contract{from, 2_000_000, ResourceBandwidth}: func() (*Transaction, error) {
+			return NewFreezeBalanceV2Transaction(testTxOptions(), from, 2_000_000, ResourceBandwidth)
+		},
+		&UnfreezeBalanceV2Contract{from, 1, ResourceTronPower}: func() (*Transaction, error) {
+			return NewUnfreezeBalanceV2Transaction(testTxOptions(), from, 1, ResourceTronPower)
+		},
+		&WithdrawExpireUnfreezeContract{from}: func() (*Transaction, error) {
+			return NewWithdrawExpireUnfreezeTransaction(testTxOptions(), from)
+		},
+		&CancelAllUnfreezeV2Contract{from}: func() (*Transaction, error) {
+			return NewCancelAllUnfreezeV2Transaction(testTxOptions(), from)
+		},
+		&DelegateResourceContract{from, ResourceBandwidth, 1_000_000, to, false, 0}: func() (*Transaction, error) {
+			return NewDelegateResourceTransaction(testTxOptions(), from, to, 1_000_000, ResourceBandwidth, false, 0)
+		},
+		&UnDelegateResourceContract{from, ResourceEnergy, 7, to}: func() (*Transaction, error) {
+			return NewUnDelegateResourceTransaction(testTxOptions(), from, to, 7, ResourceEnergy)
+		},
+	}
+	w, _ := RestoreWallet(testMnemonic)
+	priv, _ := w.Derive(0)
+	for want, fn := range build {
+		tx, err := fn()
+		if err != nil {
+			t.Fatalf("%s: build error: %v", want.ContractType(), err)
+		}
+		raw, err := tx.Raw()
+		if err != nil {
+			t.Fatalf("%s: Raw error: %v", want.ContractType(), err)
+		}
+		if got := raw.Contracts[0].Parameter; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: got %+v want %+v", want.ContractType(), got, want)
+		}
+		if err := tx.SignWithKey(priv); err != nil || VerifySignature(tx.ID(), tx.Signatures[0], from) != nil {
+			t.Fatalf("%s: signing failed: %v", want.ContractType(), err)
+		}
+	}
+}
+
+func TestStakeTransactions_Validation(t *testing.T) {
+	from, to := "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK"
+	for name, c := range map[string]ContractParameter{
+		"freeze too small":      &FreezeBalanceV2Contract{from, 999_999, ResourceEnergy},
+		"freeze bad resource":   &FreezeBalanceV2Contract{from, 1_000_000, 7},
+		"freeze bad owner":      &FreezeBalanceV2Contract{"nope", 1_000_000, ResourceEnergy},
+		"unfreeze zero":         &UnfreezeBalanceV2Contract{from, 0, ResourceEnergy},
+		"unfreeze bad resource": &UnfreezeBalanceV2Contract{from, 1, -1},
+		"unfreeze bad owner":    &UnfreezeBalanceV2Contract{"nope", 1, ResourceEnergy},
+		"withdraw bad owner":    &WithdrawExpireUnfreezeContract{"nope"},
+		"cancel bad owner":      &CancelAllUnfreezeV2Contract{""},
+		"delegate to self":      &DelegateResourceContract{from, ResourceEnergy, 1_000_000, from, false, 0},
+		"delegate bad receiver": &DelegateResourceContract{from, ResourceEnergy, 1_000_000, "nope", false, 0},
+		"delegate bad owner":    &DelegateResourceContract{"nope", ResourceEnergy, 1_000_000, to, false, 0},
+		"delegate tron power":   &DelegateResourceContract{from, ResourceTronPower, 1_000_000, to, false, 0},
+		"delegate too small":    &DelegateResourceContract{from, ResourceEnergy, 1, to, false, 0},
+		"period without lock":   &DelegateResourceContract{from, ResourceEnergy, 1_000_000, to, false, 10},
+		"negative period":       &DelegateResourceContract{from, ResourceEnergy, 1_000_000, to, true, -1},
+		"undelegate zero":       &UnDelegateResourceContract{from, ResourceEnergy, 0, to},
+		"undelegate bad res":    &UnDelegateResourceContract{from, 5, 1, to},
+		"undelegate self":       &UnDelegateResourceContract{from, ResourceEnergy, 1, from},
+	} {
+		if _, err := NewTransaction(testTxOptions(), c); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+	if _, err := NewFreezeBalanceV2Transaction(testTxOptions(), from, 1, ResourceEnergy); !errors.Is(err, ErrInvalidAmount) {
+		t.Fatalf("expected ErrInvalidAmount, got %v", err)
+	}
+	if ResourceEnergy.String() != "ENERGY" || ResourceBandwidth.String() != "BANDWIDTH" || ResourceTronPower.String() != "TRON_POWER" || ResourceCode(9).String() != "ResourceCode(9)" {
+		t.Fatalf("unexpected resource names")
+	}
+}
+
+func TestStakeParsers_Malformed(t *testing.T) {
+	parsers := []func([]byte) (ContractParameter, error){
+		parseFreezeBalanceV2Contract,
+		parseUnfreezeBalanceV2Contract,
+		parseWithdrawExpireUnfreezeContract,
+		parseCancelAllUnfreezeV2Contract,
+		parseDelegateResourceContract,
+		parseUnDelegateResourceContract,
+	}
+	for i, parse := range parsers {
+		for _, b := range [][]byte{{0x0a}, {0x08, 0x01}} {
+			if _, err := parse(b); err == nil {
+				t.Fatalf("parser %d: expected error for %x", i, b)
+			}
+		}
+	}
+	for _, parse := range parsers[:2] {
+		for _, b := range [][]byte{{0x12, 0x00}, {0x1a, 0x00}} {
+			if _, err := parse(b); err == nil {
+				t.Fatalf("expected error for %x", b)
+			}
+		}
+	}
+	for _, b := range [][]byte{{0x10, 0x00, 0x12, 0x00}, {0x18, 0x01, 0x1a, 0x00}, {0x20, 0x01}, {0x28, 0x01, 0x2a, 0x00}, {0x32, 0x00}} {
+		if _, err := parseDelegateResourceContract(b); err == nil {
+			t.Fatalf("delegate: expected error for %x", b)
+		}
+	}
+	for _, b := range [][]byte{{0x12, 0x00}, {0x1a, 0x00}, {0x20, 0x01}} {
+		if _, err := parseUnDelegateResourceContract(b); err == nil {
+			t.Fatalf("undelegate: expected error for %x", b)
+		}
+	}
+}
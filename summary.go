@@ -0,0 +1,345 @@
+package tronwallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// sunPerTRX is the number of sun in one TRX.
+const sunPerTRX = 1_000_000
+
+// SummaryField is a labelled value shown to approvers.
+type SummaryField struct {
+	Label string
+	Value string
+}
+
+// TransactionSummary is a human-readable description of a transaction for
+// review before signing. Fields that do not apply to the contract type are
+// empty. When Opaque is set the summary does not fully describe what the
+// transaction does and Notes says why; approvers should not sign it blindly.
+type TransactionSummary struct {
+	TxID         string
+	Type         ContractType
+	PermissionID int32
+	From         string
+	To           string
+	// Contract is the smart contract called, for TriggerSmartContract, or
+	// the address of the new contract, for CreateSmartContract.
+	Contract string
+	// Amount is formatted with its unit, such as "1.5 TRX (1500000 sun)".
+	Amount string
+	// Method and Args describe decoded smart contract calldata.
+	Method string
+	Args   []SummaryField
+	// Details lists other contract-specific fields in display order.
+	Details    []SummaryField
+	FeeLimit   int64
+	Timestamp  time.Time
+	Expiration time.Time
+	Memo       []byte
+	Signatures int
+	Opaque     bool
+	Notes      []string
+}
+
+// TokenInfo describes a token for display.
+type TokenInfo struct {
+	Symbol   string
+	Decimals int
+}
+
+// SummaryOption configures SummarizeTransaction.
+type SummaryOption func(*summaryOptions)
+
+type summaryOptions struct {
+	tokens map[string]TokenInfo
+}
+
+// WithToken formats amounts of the TRC20 contract address or TRC10 token ID
+// with the given symbol and decimals. Without it token amounts are shown in
+// base units.
+func WithToken(token, symbol string, decimals int) SummaryOption {
+	return func(o *summaryOptions) {
+		if raw, err := DecodeAddress(token); err == nil {
+			token = encodeAddress(raw)
+		}
+		o.tokens[token] = TokenInfo{Symbol: symbol, Decimals: decimals}
+	}
+}
+
+// SummarizeTransactionJSON verifies a transaction in the HTTP API JSON format
+// with ParseTransactionJSON and summarizes it.
+func SummarizeTransactionJSON(data []byte, opts ...SummaryOption) (*TransactionSummary, error) {
+	tx, err := ParseTransactionJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return SummarizeTransaction(tx, opts...)
+}
+
+// SummarizeTransaction decodes tx for review. Raw protobuf bytes can be
+// loaded with ParseTransaction, or wrapped as Transaction{RawData: raw}.
+func SummarizeTransaction(tx *Transaction, opts ...SummaryOption) (*TransactionSummary, error) {
+	o := summaryOptions{tokens: map[string]TokenInfo{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	raw, err := tx.Raw()
+	if err != nil {
+		return nil, err
+	}
+	s := &TransactionSummary{
+		TxID:       hex.EncodeToString(tx.ID()),
+		FeeLimit:   raw.FeeLimit,
+		Timestamp:  time.UnixMilli(raw.Timestamp).UTC(),
+		Expiration: time.UnixMilli(raw.Expiration).UTC(),
+		Memo:       raw.Data,
+		Signatures: len(tx.Signatures),
+	}
+	if again, err := raw.Marshal(); err != nil || !bytes.Equal(again, tx.RawData) {
+		s.opaque("raw_data contains fields or encodings not shown in this summary")
+	}
+	if len(raw.Contracts) != 1 {
+		s.opaque(fmt.Sprintf("transaction has %d contracts; java-tron requires exactly one", len(raw.Contracts)))
+		if len(raw.Contracts) == 0 {
+			return s, nil
+		}
+	}
+	c := raw.Contracts[0]
+	s.Type = c.Parameter.ContractType()
+	s.PermissionID = c.PermissionID
+	s.describe(c.Parameter, &o)
+	return s, nil
+}
+
+func (s *TransactionSummary) opaque(note string) {
+	s.Opaque = true
+	s.Notes = append(s.Notes, note)
+}
+
+func (s *TransactionSummary) detail(label, value string) {
+	s.Details = append(s.Details, SummaryField{label, value})
+}
+
+// describe fills in the contract-specific fields.
+func (s *TransactionSummary) describe(p ContractParameter, o *summaryOptions) {
+	switch c := p.(type) {
+	case *TransferContract:
+		s.From, s.To, s.Amount = c.OwnerAddress, c.ToAddress, formatTRX(c.Amount)
+	case *TransferAssetContract:
+		s.From, s.To = c.OwnerAddress, c.ToAddress
+		s.Amount = formatToken(big.NewInt(c.Amount), c.AssetName, "TRC10 token "+c.AssetName, o)
+	case *TriggerSmartContract:
+		s.From, s.Contract = c.OwnerAddress, c.ContractAddress
+		if c.CallValue != 0 {
+			s.detail("Call value", formatTRX(c.CallValue))
+		}
+		if c.CallTokenValue != 0 {
+			s.detail("Call token value", fmt.Sprintf("%d of TRC10 token %d", c.CallTokenValue, c.TokenID))
+		}
+		s.describeCall(c, o)
+	case *CreateSmartContract:
+		s.describeDeploy(c)
+	case *VoteWitnessContract:
+		s.From = c.OwnerAddress
+		for _, v := range c.Votes {
+			s.detail("Vote", fmt.Sprintf("%s x %d", v.Address, v.Count))
+		}
+	case *WithdrawBalanceContract:
+		s.From = c.OwnerAddress
+		s.detail("Action", "claim voting and block rewards")
+	case *AccountPermissionUpdateContract:
+		s.From = c.OwnerAddress
+		s.detail("Owner", c.Owner.String())
+		if c.Witness != nil {
+			s.detail("Witness", c.Witness.String())
+		}
+		for _, a := range c.Actives {
+			s.detail("Active", a.String())
+		}
+	case *FreezeBalanceV2Contract:
+		s.From, s.Amount = c.OwnerAddress, formatTRX(c.FrozenBalance)
+		s.detail("Resource", c.Resource.String())
+	case *UnfreezeBalanceV2Contract:
+		s.From, s.Amount = c.OwnerAddress, formatTRX(c.UnfreezeBalance)
+		s.detail("Resource", c.Resource.String())
+	case *WithdrawExpireUnfreezeContract:
+		s.From = c.OwnerAddress
+		s.detail("Action", "withdraw expired unfrozen TRX")
+	case *CancelAllUnfreezeV2Contract:
+		s.From = c.OwnerAddress
+		s.detail("Action", "cancel all pending unfreezes")
+	case *DelegateResourceContract:
+		s.From, s.To, s.Amount = c.OwnerAddress, c.ReceiverAddress, formatTRX(c.Balance)
+		s.detail("Resource", c.Resource.String())
+		if c.Lock {
+			s.detail("Lock period", fmt.Sprintf("%d blocks", c.LockPeriod))
+		}
+	case *UnDelegateResourceContract:
+		s.From, s.To, s.Amount = c.OwnerAddress, c.ReceiverAddress, formatTRX(c.Balance)
+		s.detail("Resource", c.Resource.String())
+	default:
+		s.opaque(fmt.Sprintf("contract type %s is not decoded", p.ContractType()))
+	}
+}
+
+// describeDeploy describes a contract deployment. The bytecode cannot be
+// reviewed here, so its size and hash are shown for comparison with the
+// compiler output.
+func (s *TransactionSummary) describeDeploy(c *CreateSmartContract) {
+	sc := &c.NewContract
+	s.From = c.OwnerAddress
+	if id, err := hex.DecodeString(s.TxID); err == nil {
+		s.Contract, _ = contractAddressFor(id, c.OwnerAddress)
+	}
+	if sc.Name != "" {
+		s.detail("Name", sc.Name)
+	}
+	s.detail("Bytecode", fmt.Sprintf("%d bytes, SHA-256 %x", len(sc.Bytecode), sc.codeHash()))
+	if sc.CallValue != 0 {
+		s.detail("Call value", formatTRX(sc.CallValue))
+	}
+	if c.CallTokenValue != 0 {
+		s.detail("Call token value", fmt.Sprintf("%d of TRC10 token %d", c.CallTokenValue, c.TokenID))
+	}
+	s.detail("Caller energy share", fmt.Sprintf("%d%%", sc.ConsumeUserResourcePercent))
+	s.detail("Origin energy limit", fmt.Sprintf("%d", sc.OriginEnergyLimit))
+	if sc.OriginAddress != c.OwnerAddress {
+		s.opaque("contract origin " + sc.OriginAddress + " is not the owner")
+	}
+}
+
+// trc20Calls are the calls SummarizeTransaction decodes.
+var trc20Calls = []ABIEntry{trc20Transfer, trc20Approve, trc20TransferFrom}
+
+// describeCall decodes TRC20 transfer, approve and transferFrom calldata.
+func (s *TransactionSummary) describeCall(c *TriggerSmartContract, o *summaryOptions) {
+	if len(c.Data) < 4 {
+		s.opaque("calldata has no method selector")
+		return
+	}
+	var m ABIEntry
+	for _, fn := range trc20Calls {
+		if bytes.Equal(c.Data[:4], fn.Selector()) {
+			m = fn
+		}
+	}
+	if m.Name == "" {
+		s.opaque("unknown method selector 0x" + hex.EncodeToString(c.Data[:4]))
+		return
+	}
+	// The arguments are all static, so anything past them is hidden data.
+	if n := len(c.Data) - 4; n != 32*len(m.Inputs) {
+		s.opaque(fmt.Sprintf("calldata for %s has %d bytes of arguments, want %d", m.Signature(), n, 32*len(m.Inputs)))
+		return
+	}
+	args, err := m.DecodeCall(c.Data)
+	if err != nil {
+		s.opaque(fmt.Sprintf("calldata for %s: %v", m.Signature(), err))
+		return
+	}
+	values := make([]string, len(args))
+	for i, p := range m.Inputs {
+		if p.Type == "address" {
+			values[i] = args[i].(string)
+		} else {
+			values[i] = formatToken(args[i].(*big.Int), c.ContractAddress, "token units", o)
+		}
+		s.Args = append(s.Args, SummaryField{p.Name, values[i]})
+	}
+	s.Method = m.Signature()
+	s.Amount = values[len(values)-1]
+	switch len(values) {
+	case 2:
+		s.To = values[0]
+	case 3:
+		s.detail("Token owner", values[0])
+		s.To = values[1]
+	}
+}
+
+// formatTRX formats an amount of sun as TRX.
+func formatTRX(sun int64) string {
+	return fmt.Sprintf("%s TRX (%d sun)", formatUnits(big.NewInt(sun), 6), sun)
+}
+
+// formatToken formats a token amount using the registered TokenInfo for
+// token, or as base units with the fallback unit.
+func formatToken(amount *big.Int, token, fallback string, o *summaryOptions) string {
+	if info, ok := o.tokens[token]; ok {
+		return fmt.Sprintf("%s %s (%s base units)", formatUnits(amount, info.Decimals), info.Symbol, amount)
+	}
+	return fmt.Sprintf("%s %s", amount, fallback)
+}
+
+// formatUnits renders amount / 10^decimals without trailing zeros.
+func formatUnits(amount *big.Int, decimals int) string {
+	neg := amount.Sign() < 0
+	digits := new(big.Int).Abs(amount).String()
+	if decimals > 0 {
+		if len(digits) <= decimals {
+			digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+		}
+		whole, frac := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+		digits = whole
+		if frac != "" {
+			digits += "." + frac
+		}
+	}
+	if neg {
+		return "-" + digits
+	}
+	return digits
+}
+
+// String renders the summary as aligned "Label: value" lines.
+func (s *TransactionSummary) String() string {
+	var sb strings.Builder
+	line := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(&sb, "%-14s %s\n", label+":", value)
+		}
+	}
+	line("Transaction", s.TxID)
+	line("Type", s.Type.String())
+	if s.PermissionID != 0 {
+		line("Permission", fmt.Sprint(s.PermissionID))
+	}
+	line("From", s.From)
+	line("To", s.To)
+	line("Contract", s.Contract)
+	line("Method", s.Method)
+	for _, a := range s.Args {
+		line("  "+a.Label, a.Value)
+	}
+	line("Amount", s.Amount)
+	for _, d := range s.Details {
+		line(d.Label, d.Value)
+	}
+	if s.FeeLimit != 0 {
+		line("Fee limit", formatTRX(s.FeeLimit))
+	}
+	switch {
+	case len(s.Memo) == 0:
+	case IsEncryptedMemo(s.Memo):
+		line("Memo", fmt.Sprintf("encrypted, %d bytes", len(s.Memo)))
+	case isPrintableMemo(s.Memo):
+		line("Memo", fmt.Sprintf("%q", s.Memo))
+	default:
+		line("Memo", FormatMemo(s.Memo))
+	}
+	line("Timestamp", s.Timestamp.Format(time.RFC3339))
+	line("Expires", s.Expiration.Format(time.RFC3339))
+	line("Signatures", fmt.Sprint(s.Signatures))
+	if s.Opaque {
+		for _, n := range s.Notes {
+			line("OPAQUE", n)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,188 @@
+package tronwallet
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestSummarizeTransaction_TRX(t *testing.T) {
+	s, err := SummarizeTransactionJSON([]byte(transferJSON))
+	if err != nil {
+		t.Fatalf("SummarizeTransactionJSON error: %v", err)
+	}
+	if s.Type != TransferContractType || s.From != "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH" || s.To != "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK" || s.Amount != "1 TRX (1000000 sun)" || s.Opaque {
+		t.Fatalf("unexpected summary %+v", s)
+	}
+	out := s.String()
+	for _, want := range []string{
+		"Transaction:   57160c72887d410f866470f4515b6bf9d67169321441f1fd372868ba43fb9068",
+		"Type:          TransferContract",
+		"Amount:        1 TRX (1000000 sun)",
+		"Expires:       2023-11-14T22:14:20Z",
+		"Signatures:    0",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("summary missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "OPAQUE") || strings.Contains(out, "Fee limit") {
+		t.Fatalf("unexpected lines:\n%s", out)
+	}
+	if _, err := SummarizeTransactionJSON([]byte(`{}`)); err == nil {
+		t.Fatalf("expected error for empty JSON")
+	}
+}
+
+func TestSummarizeTransaction_TRC20(t *testing.T) {
+	owner, to := "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK"
+	opts := testTxOptions()
+	opts.FeeLimit = 30_000_000
+	tx, _ := NewTRC20TransferTransaction(opts, owner, testUSDT, 0, to, big.NewInt(1_500_000))
+
+	s, err := SummarizeTransaction(tx, WithToken("41a614f803b6fd780986a42c78ec9c7f77e6ded13c", "USDT", 6))
+	if err != nil {
+		t.Fatalf("SummarizeTransaction error: %v", err)
+	}
+	if s.Method != "transfer(address,uint256)" || s.To != to || s.Contract != testUSDT || s.Amount != "1.5 USDT (1500000 base units)" || s.Opaque {
+		t.Fatalf("unexpected summary %+v", s)
+	}
+	if out := s.String(); !strings.Contains(out, "Fee limit:     30 TRX (30000000 sun)") || !strings.Contains(out, "  to:") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+
+	s, _ = SummarizeTransaction(tx)
+	if s.Amount != "1500000 token units" {
+		t.Fatalf("unexpected amount without token info: %s", s.Amount)
+	}
+
+	tx, _ = NewTRC20TransferFromTransaction(opts, to, testUSDT, 0, owner, "TYJPRrdB5APNeRs4R7fYZSwW3TcrTKw2gx", big.NewInt(3))
+	s, _ = SummarizeTransaction(tx)
+	if s.To != "TYJPRrdB5APNeRs4R7fYZSwW3TcrTKw2gx" || len(s.Details) != 1 || s.Details[0].Value != owner || len(s.Args) != 3 {
+		t.Fatalf("unexpected transferFrom summary %+v", s)
+	}
+
+	tx, _ = NewTRC20ApproveTransaction(opts, owner, testUSDT, 0, to, big.NewInt(0))
+	s, _ = SummarizeTransaction(tx)
+	if s.Method != "approve(address,uint256)" || s.To != to || s.Amount != "0 token units" {
+		t.Fatalf("unexpected approve summary %+v", s)
+	}
+}
+
+func TestSummarizeTransaction_OpaqueCalls(t *testing.T) {
+	owner := "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH"
+	opts := testTxOptions()
+	opts.FeeLimit = 1
+	dirtyAddress, _ := hex.DecodeString("a9059cbb" + strings.Repeat("ff", 32) + strings.Repeat("00", 32))
+	for name, data := range map[string][]byte{
+		"empty":          nil,
+		"unknown method": {0xde, 0xad, 0xbe, 0xef},
+		"short args":     append(append([]byte(nil), trc20Transfer.Selector()...), 1, 2, 3),
+		"dirty address":  dirtyAddress,
+	} {
+		tx, err := NewTriggerSmartContractTransaction(opts, owner, testUSDT, 5, data)
+		if err != nil {
+			t.Fatalf("%s: build error: %v", name, err)
+		}
+		s, err := SummarizeTransaction(tx)
+		if err != nil {
+			t.Fatalf("%s: SummarizeTransaction error: %v", name, err)
+		}
+		if !s.Opaque || len(s.Notes) != 1 || !strings.Contains(s.String(), "OPAQUE:") || s.Details[0].Value != "0.000005 TRX (5 sun)" {
+			t.Fatalf("%s: expected opaque summary, got %+v", name, s)
+		}
+	}
+
+	tx, _ := NewTransaction(opts, &TriggerSmartContract{OwnerAddress: owner, ContractAddress: testUSDT, Data: trc20Transfer.Selector(), CallTokenValue: 2, TokenID: 1002000})
+	s, _ := SummarizeTransaction(tx)
+	if s.Details[0].Value != "2 of TRC10 token 1002000" {
+		t.Fatalf("unexpected details %+v", s.Details)
+	}
+}
+
+func TestSummarizeTransaction_ContractTypes(t *testing.T) {
+	owner, to := "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK"
+	for _, tc := range []struct {
+		param  ContractParameter
+		amount string
+		want   string
+	}{
+		{&TransferAssetContract{"1002000", owner, to, 250}, "250 TRC10 token 1002000", ""},
+		{&VoteWitnessContract{OwnerAddress: owner, Votes: []Vote{{to, 7}}}, "", to + " x 7"},
+		{&WithdrawBalanceContract{owner}, "", "claim voting and block rewards"},
+		{&FreezeBalanceV2Contract{owner, 2_000_000, ResourceEnergy}, "2 TRX (2000000 sun)", "ENERGY"},
+		{&UnfreezeBalanceV2Contract{owner, 1, ResourceBandwidth}, "0.000001 TRX (1 sun)", "BANDWIDTH"},
+		{&WithdrawExpireUnfreezeContract{owner}, "", "withdraw expired unfrozen TRX"},
+		{&CancelAllUnfreezeV2Contract{owner}, "", "cancel all pending unfreezes"},
+		{&DelegateResourceContract{owner, ResourceEnergy, 1_000_000, to, true, 100}, "1 TRX (1000000 sun)", "100 blocks"},
+		{&UnDelegateResourceContract{owner, ResourceEnergy, 3_000_000, to}, "3 TRX (3000000 sun)", "ENERGY"},
+	} {
+		tx, err := NewTransaction(testTxOptions(), tc.param)
+		if err != nil {
+			t.Fatalf("%s: build error: %v", tc.param.ContractType(), err)
+		}
+		s, err := SummarizeTransaction(tx, WithToken("not-an-address", "X", 0))
+		if err != nil {
+			t.Fatalf("%s: SummarizeTransaction error: %v", tc.param.ContractType(), err)
+		}
+		if s.Opaque || s.From != owner || s.Amount != tc.amount || (tc.want != "" && !strings.Contains(s.String(), tc.want)) {
+			t.Fatalf("%s: unexpected summary:\n%s", tc.param.ContractType(), s)
+		}
+	}
+
+	tx, _ := NewTRC10TransferTransaction(testTxOptions(), owner, to, "1002000", 1234)
+	s, _ := SummarizeTransaction(tx, WithToken("1002000", "BTT", 2))
+	if s.Amount != "12.34 BTT (1234 base units)" {
+		t.Fatalf("unexpected TRC10 amount %s", s.Amount)
+	}
+}
+
+func TestSummarizeTransaction_OpaqueTransactions(t *testing.T) {
+	unknown := &TransactionRaw{Contracts: []Contract{{Parameter: &UnknownContract{Type: 99, TypeURL: "x"}, PermissionID: 2}}, Data: []byte("memo")}
+	b, _ := unknown.Marshal()
+	s, err := SummarizeTransaction(&Transaction{RawData: b, Signatures: [][]byte{{1}}})
+	if err != nil {
+		t.Fatalf("SummarizeTransaction error: %v", err)
+	}
+	out := s.String()
+	if !s.Opaque || !strings.Contains(out, "ContractType(99) is not decoded") || !strings.Contains(out, "Permission:    2") || !strings.Contains(out, `Memo:          "memo"`) || !strings.Contains(out, "Signatures:    1") {
+		t.Fatalf("unexpected summary:\n%s", out)
+	}
+
+	empty := &TransactionRaw{Expiration: 1}
+	b, _ = empty.Marshal()
+	if s, _ := SummarizeTransaction(&Transaction{RawData: b}); !s.Opaque || !strings.Contains(s.Notes[0], "0 contracts") {
+		t.Fatalf("expected opaque summary for no contracts, got %+v", s)
+	}
+
+	// An unknown field (number 99) is hidden from the decoded view.
+	raw, _ := hex.DecodeString(transferRawHex)
+	raw = append(raw, 0x98, 0x06, 0x01)
+	s, err = SummarizeTransaction(&Transaction{RawData: raw})
+	if err != nil || !s.Opaque || s.Amount == "" {
+		t.Fatalf("expected opaque summary for unknown field, got %+v (%v)", s, err)
+	}
+
+	if _, err := SummarizeTransaction(&Transaction{RawData: []byte{0x0a}}); err == nil {
+		t.Fatalf("expected error for malformed transaction")
+	}
+}
+
+func TestFormatUnits(t *testing.T) {
+	for _, tc := range []struct {
+		n        int64
+		decimals int
+		want     string
+	}{
+		{0, 6, "0"},
+		{1, 6, "0.000001"},
+		{1_500_000, 6, "1.5"},
+		{-2_000_000, 6, "-2"},
+		{42, 0, "42"},
+	} {
+		if got := formatUnits(big.NewInt(tc.n), tc.decimals); got != tc.want {
+			t.Fatalf("formatUnits(%d, %d) = %s, want %s", tc.n, tc.decimals, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Prints the TronWeb signing vectors checked by the Go tests as JSON.
+//
+//   npm install && npm run vectors
+//
+// Signing is done locally; no node is contacted.
+const { TronWeb } = require('tronweb');
+const { version } = require('tronweb/package.json');
+
+// keccak256("cow")
+const key = 'c85ef7d79691fe79573b1a7064c19c1a9819ebdbd1faaab1a8ec92344438aaf4';
+const tronWeb = new TronWeb({ fullHost: 'http://127.0.0.1:8090', privateKey: key });
+
+// An EIP-712 document as ParseTypedData reads it. TronWeb takes the types
+// without EIP712Domain, which it derives from the domain itself.
+const tokenTransfer = {
+  types: {
+    EIP712Domain: [
+      { name: 'name', type: 'string' },
+      { name: 'version', type: 'string' },
+      { name: 'chainId', type: 'uint256' },
+      { name: 'verifyingContract', type: 'address' },
+    ],
+    TokenTransfer: [
+      { name: 'from', type: 'address' },
+      { name: 'to', type: 'address' },
+      { name: 'tokenId', type: 'trcToken' },
+      { name: 'amount', type: 'uint256' },
+    ],
+  },
+  primaryType: 'TokenTransfer',
+  domain: {
+    name: 'TRC Gate',
+    version: '1',
+    chainId: 728126428,
+    verifyingContract: 'TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t',
+  },
+  message: {
+    from: 'TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH',
+    to: '41b6e708a39781c96bd399c7657780ff9fe9f052a8',
+    tokenId: '1002000',
+    amount: 1500000,
+  },
+};
+
+async function main() {
+  const out = { tronweb: version, key, signMessageV2: [], sign: [], typedData: [] };
+  for (const message of ['hello world', 'TRON signMessageV2 ✓']) {
+    out.signMessageV2.push({ message, signature: await tronWeb.trx.signMessageV2(message, key) });
+  }
+  for (const message of ['0123456789abcdef', 'deadbeef']) {
+    out.sign.push({ message, signature: await tronWeb.trx.sign(message, key) });
+  }
+  for (const doc of [tokenTransfer]) {
+    const { EIP712Domain, ...types } = doc.types;
+    out.typedData.push({
+      document: doc,
+      digest: tronWeb.utils._TypedDataEncoder.hash(doc.domain, types, doc.message),
+      signature: await tronWeb.trx._signTypedData(doc.domain, types, doc.message, key),
+    });
+  }
+  console.log(JSON.stringify(out, null, 2));
+}
+
+main().catch((err) => {
+  console.error(err);
+  process.exit(1);
+});
@@ -0,0 +1,372 @@
+package tronwallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultTransactionExpiration is how long after its timestamp a new
+// transaction stays valid when TransactionOptions.Expiration is not set. It
+// matches the window java-tron's /wallet/createtransaction uses.
+const DefaultTransactionExpiration = 60 * time.Second
+
+// contractTypeURLPrefix prefixes the protobuf Any type URL of a contract.
+const contractTypeURLPrefix = "type.googleapis.com/protocol."
+
+// ErrInvalidTransaction is returned when transaction bytes cannot be decoded
+// or a transaction cannot be built from the given options.
+var ErrInvalidTransaction = errors.New("invalid transaction")
+
+// transactionNowImpl is used for default timestamps; tests may replace it.
+var transactionNowImpl = time.Now
+
+// Transaction is a TRON transaction: the serialized raw_data, which is what
+// gets hashed and signed, and its signatures. RawData is authoritative; use
+// Raw to decode it.
+type Transaction struct {
+	RawData    []byte
+	Signatures [][]byte
+}
+
+// TransactionRaw is the decoded protocol.Transaction.raw message. Times are
+// Unix milliseconds.
+type TransactionRaw struct {
+	RefBlockBytes []byte
+	RefBlockNum   int64
+	RefBlockHash  []byte
+	Expiration    int64
+	Data          []byte
+	Contracts     []Contract
+	Timestamp     int64
+	FeeLimit      int64
+}
+
+// Contract is one entry of TransactionRaw.Contracts. java-tron accepts
+// exactly one contract per transaction.
+type Contract struct {
+	Parameter    ContractParameter
+	PermissionID int32
+}
+
+// ContractType identifies a contract, as in java-tron's
+// Transaction.Contract.ContractType enum.
+type ContractType int32
+
+// ContractParameter is the typed payload of a contract, such as
+// TransferContract.
+type ContractParameter interface {
+	// ContractType returns the enum value stored in Contract.type.
+	ContractType() ContractType
+	marshalProto() ([]byte, error)
+}
+
+// String returns the protobuf message name of the contract type.
+func (t ContractType) String() string {
+	if name, ok := contractTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("ContractType(%d)", int32(t))
+}
+
+// UnknownContract holds the parameter of a contract type this package does
+// not decode. It re-encodes byte for byte.
+type UnknownContract struct {
+	Type    ContractType
+	TypeURL string
+	Value   []byte
+}
+
+// ContractType implements ContractParameter.
+func (c *UnknownContract) ContractType() ContractType { return c.Type }
+
+func (c *UnknownContract) marshalProto() ([]byte, error) { return c.Value, nil }
+
+// TransactionOptions sets the header fields of a new transaction. The
+// reference block must be recent (within the last 65535 blocks) and is
+// usually the node's latest solidified block.
+type TransactionOptions struct {
+	// RefBlockNum and RefBlockID identify the reference block; RefBlockID is
+	// the 32-byte block ID ("blockID" in node APIs).
+	RefBlockNum int64
+	RefBlockID  []byte
+	// Timestamp defaults to the current time.
+	Timestamp time.Time
+	// Expiration defaults to Timestamp + DefaultTransactionExpiration.
+	Expiration time.Time
+	// FeeLimit is the maximum energy fee in sun, used by smart contract calls.
+	FeeLimit int64
+	// PermissionID selects the account permission that signs the
+	// transaction: 0 is the owner permission, 2 and up are active
+	// permissions used for multisig.
+	PermissionID int32
+	// Memo is stored in raw_data.data, at most MaxMemoSize bytes. Build it
+	// with ParseMemo or EncryptMemo.
+	Memo []byte
+}
+
+// NewTransaction builds an unsigned transaction carrying a single contract.
+func NewTransaction(opts TransactionOptions, param ContractParameter) (*Transaction, error) {
+	if len(opts.RefBlockID) != 32 {
+		return nil, fmt.Errorf("%w: reference block ID must be 32 bytes", ErrInvalidTransaction)
+	}
+	if opts.FeeLimit < 0 {
+		return nil, fmt.Errorf("%w: negative fee limit", ErrInvalidTransaction)
+	}
+	if opts.PermissionID < 0 {
+		return nil, fmt.Errorf("%w: negative permission ID", ErrInvalidTransaction)
+	}
+	if len(opts.Memo) > MaxMemoSize {
+		return nil, ErrMemoTooLarge
+	}
+	ts := opts.Timestamp
+	if ts.IsZero() {
+		ts = transactionNowImpl()
+	}
+	exp := opts.Expiration
+	if exp.IsZero() {
+		exp = ts.Add(DefaultTransactionExpiration)
+	}
+	if !exp.After(ts) {
+		return nil, fmt.Errorf("%w: expiration must be after timestamp", ErrInvalidTransaction)
+	}
+
+	num := binary.BigEndian.AppendUint64(nil, uint64(opts.RefBlockNum))
+	raw := &TransactionRaw{
+		RefBlockBytes: num[6:8],
+		RefBlockHash:  append([]byte(nil), opts.RefBlockID[8:16]...),
+		Expiration:    exp.UnixMilli(),
+		Data:          append([]byte(nil), opts.Memo...),
+		Contracts:     []Contract{{Parameter: param, PermissionID: opts.PermissionID}},
+		Timestamp:     ts.UnixMilli(),
+		FeeLimit:      opts.FeeLimit,
+	}
+	data, err := raw.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{RawData: data}, nil
+}
+
+// ID returns the transaction ID, the SHA-256 of RawData. This is the hash
+// that gets signed.
+func (tx *Transaction) ID() []byte {
+	id := sha256.Sum256(tx.RawData)
+	return id[:]
+}
+
+// Raw decodes RawData.
+func (tx *Transaction) Raw() (*TransactionRaw, error) {
+	return ParseTransactionRaw(tx.RawData)
+}
+
+// Memo returns the memo stored in raw_data.data, which may be empty. Show
+// it with FormatMemo, or open it with DecryptMemo if IsEncryptedMemo.
+func (tx *Transaction) Memo() ([]byte, error) {
+	raw, err := tx.Raw()
+	if err != nil {
+		return nil, err
+	}
+	return raw.Data, nil
+}
+
+// Sign appends s's signature of the transaction ID.
+func (tx *Transaction) Sign(s Signer) error {
+	sig, err := s.SignHash(tx.ID())
+	if err != nil {
+		return err
+	}
+	tx.Signatures = append(tx.Signatures, sig)
+	return nil
+}
+
+// SignWithKey appends a signature by priv, for example a key returned by
+// TronWallet.Derive.
+func (tx *Transaction) SignWithKey(priv *ecdsa.PrivateKey) error {
+	if !validPrivateKey(priv) {
+		return errInvalidPrivateKey
+	}
+	return tx.Sign(NewAccount(priv))
+}
+
+// Marshal returns the protocol.Transaction encoding, as broadcast with
+// /wallet/broadcasthex.
+func (tx *Transaction) Marshal() []byte {
+	var w protoWriter
+	w.message(1, tx.RawData)
+	for _, sig := range tx.Signatures {
+		w.message(2, sig)
+	}
+	return w.buf
+}
+
+// ParseTransaction decodes a protocol.Transaction. Execution results (ret)
+// are ignored.
+func ParseTransaction(b []byte) (*Transaction, error) {
+	fields, err := parseProto(b)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+	tx := &Transaction{}
+	for _, f := range fields {
+		switch f.Num {
+		case 1:
+			tx.RawData, err = f.bytes()
+		case 2:
+			var sig []byte
+			sig, err = f.bytes()
+			tx.Signatures = append(tx.Signatures, sig)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+		}
+	}
+	if _, err := tx.Raw(); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// Marshal encodes the raw data as java-tron does.
+func (r *TransactionRaw) Marshal() ([]byte, error) {
+	var w protoWriter
+	w.bytes(1, r.RefBlockBytes)
+	w.int64(3, r.RefBlockNum)
+	w.bytes(4, r.RefBlockHash)
+	w.int64(8, r.Expiration)
+	w.bytes(10, r.Data)
+	for _, c := range r.Contracts {
+		b, err := c.marshalProto()
+		if err != nil {
+			return nil, err
+		}
+		w.message(11, b)
+	}
+	w.int64(14, r.Timestamp)
+	w.int64(18, r.FeeLimit)
+	return w.buf, nil
+}
+
+// ParseTransactionRaw decodes a protocol.Transaction.raw message.
+func ParseTransactionRaw(b []byte) (*TransactionRaw, error) {
+	fields, err := parseProto(b)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+	r := &TransactionRaw{}
+	for _, f := range fields {
+		switch f.Num {
+		case 1:
+			r.RefBlockBytes, err = f.bytes()
+		case 3:
+			r.RefBlockNum, err = f.int64()
+		case 4:
+			r.RefBlockHash, err = f.bytes()
+		case 8:
+			r.Expiration, err = f.int64()
+		case 10:
+			r.Data, err = f.bytes()
+		case 11:
+			var c *Contract
+			if c, err = parseContract(f); err == nil {
+				r.Contracts = append(r.Contracts, *c)
+			}
+		case 14:
+			r.Timestamp, err = f.int64()
+		case 18:
+			r.FeeLimit, err = f.int64()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+		}
+	}
+	return r, nil
+}
+
+func (c Contract) marshalProto() ([]byte, error) {
+	if c.Parameter == nil {
+		return nil, fmt.Errorf("%w: missing contract parameter", ErrInvalidTransaction)
+	}
+	value, err := c.Parameter.marshalProto()
+	if err != nil {
+		return nil, err
+	}
+	typeURL := contractTypeURLPrefix + c.Parameter.ContractType().String()
+	if u, ok := c.Parameter.(*UnknownContract); ok {
+		typeURL = u.TypeURL
+	}
+	var param protoWriter
+	param.string(1, typeURL)
+	param.bytes(2, value)
+
+	var w protoWriter
+	w.int64(1, int64(c.Parameter.ContractType()))
+	w.message(2, param.buf)
+	w.int64(5, int64(c.PermissionID))
+	return w.buf, nil
+}
+
+func parseContract(f protoField) (*Contract, error) {
+	b, err := f.bytes()
+	if err != nil {
+		return nil, err
+	}
+	fields, err := parseProto(b)
+	if err != nil {
+		return nil, err
+	}
+	var typ, permissionID int64
+	var param []byte
+	for _, f := range fields {
+		switch f.Num {
+		case 1:
+			typ, err = f.int64()
+		case 2:
+			param, err = f.bytes()
+		case 5:
+			permissionID, err = f.int64()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if param == nil {
+		return nil, errors.New("contract without parameter")
+	}
+
+	// param is a google.protobuf.Any: type_url = 1, value = 2.
+	fields, err = parseProto(param)
+	if err != nil {
+		return nil, err
+	}
+	var typeURL, value []byte
+	for _, f := range fields {
+		switch f.Num {
+		case 1:
+			typeURL, err = f.bytes()
+		case 2:
+			value, err = f.bytes()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c := &Contract{PermissionID: int32(permissionID)}
+	t := ContractType(typ)
+	parse, ok := contractParsers[t]
+	if !ok {
+		c.Parameter = &UnknownContract{Type: t, TypeURL: string(typeURL), Value: value}
+		return c, nil
+	}
+	if string(typeURL) != contractTypeURLPrefix+t.String() {
+		return nil, fmt.Errorf("contract type %s does not match parameter type %q", t, typeURL)
+	}
+	if c.Parameter, err = parse(value); err != nil {
+		return nil, fmt.Errorf("%s: %w", t, err)
+	}
+	return c, nil
+}
@@ -0,0 +1,184 @@
+package tronwallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+)
+
+// testRefBlockID is the ID of block 0x0123abcd used by transaction fixtures.
+var testRefBlockID, _ = hex.DecodeString("000000000123abcd1122334455667788" + "99999999999999999999999999999999")
+
+func testTxOptions() TransactionOptions {
+	return TransactionOptions{
+		RefBlockNum: 0x0123abcd,
+		RefBlockID:  testRefBlockID,
+		Timestamp:   time.UnixMilli(1700000000000),
+	}
+}
+
+// transferRawHex is the java-tron serialization of a 1 TRX transfer from
+// account 0 to account 1 of testMnemonic with testTxOptions.
+const transferRawHex = "0a02abcd2208112233445566778840e0a499ffbc315a67080112630a2d747970652e676f6f676c65617069732e636f6d2f70726f746f636f6c2e5472616e73666572436f6e747261637412320a1541c8599111f29c1e1e061265b4af93ea1f274ad78a121541b6e708a39781c96bd399c7657780ff9fe9f052a818c0843d7080d095ffbc31"
+
+func TestNewTransferTransaction_MatchesJavaTron(t *testing.T) {
+	tx, err := NewTransferTransaction(testTxOptions(), "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", 1_000_000)
+	if err != nil {
+		t.Fatalf("NewTransferTransaction error: %v", err)
+	}
+	if got := hex.EncodeToString(tx.RawData); got != transferRawHex {
+		t.Fatalf("raw data mismatch:\n got %s\nwant %s", got, transferRawHex)
+	}
+	if got := hex.EncodeToString(tx.ID()); got != "57160c72887d410f866470f4515b6bf9d67169321441f1fd372868ba43fb9068" {
+		t.Fatalf("unexpected txID %s", got)
+	}
+
+	raw, err := tx.Raw()
+	if err != nil {
+		t.Fatalf("Raw error: %v", err)
+	}
+	c, ok := raw.Contracts[0].Parameter.(*TransferContract)
+	if !ok || c.OwnerAddress != "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH" || c.ToAddress != "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK" || c.Amount != 1_000_000 {
+		t.Fatalf("unexpected decoded contract %+v", raw.Contracts[0].Parameter)
+	}
+	if raw.Expiration != 1700000060000 || raw.Timestamp != 1700000000000 || !bytes.Equal(raw.RefBlockBytes, []byte{0xab, 0xcd}) {
+		t.Fatalf("unexpected header %+v", raw)
+	}
+}
+
+func TestTransaction_SignAndParse(t *testing.T) {
+	w, _ := RestoreWallet(testMnemonic)
+	priv, _ := w.Derive(0)
+	tx, err := NewTransferTransaction(testTxOptions(), TronAddressFromPrivate(priv), "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", 5)
+	if err != nil {
+		t.Fatalf("NewTransferTransaction error: %v", err)
+	}
+	if err := tx.SignWithKey(priv); err != nil {
+		t.Fatalf("SignWithKey error: %v", err)
+	}
+	if err := VerifySignature(tx.ID(), tx.Signatures[0], TronAddressFromPrivate(priv)); err != nil {
+		t.Fatalf("signature does not verify: %v", err)
+	}
+
+	parsed, err := ParseTransaction(tx.Marshal())
+	if err != nil {
+		t.Fatalf("ParseTransaction error: %v", err)
+	}
+	if !bytes.Equal(parsed.RawData, tx.RawData) || len(parsed.Signatures) != 1 || !bytes.Equal(parsed.Signatures[0], tx.Signatures[0]) {
+		t.Fatalf("round trip mismatch")
+	}
+
+	if err := tx.Sign(failingSigner{}); err == nil {
+		t.Fatalf("expected signer error")
+	}
+	if err := tx.SignWithKey(nil); err == nil || len(tx.Signatures) != 1 {
+		t.Fatalf("expected error for nil key, got %v", err)
+	}
+}
+
+type failingSigner struct{ Signer }
+
+func (failingSigner) SignHash([]byte) ([]byte, error) { return nil, errors.New("offline") }
+
+func TestNewTransaction_Defaults(t *testing.T) {
+	now := time.UnixMilli(1650000000000)
+	transactionNowImpl = func() time.Time { return now }
+	defer func() { transactionNowImpl = time.Now }()
+
+	opts := testTxOptions()
+	opts.Timestamp = time.Time{}
+	opts.FeeLimit = 10_000_000
+	tx, err := NewTransferTransaction(opts, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", 1)
+	if err != nil {
+		t.Fatalf("NewTransferTransaction error: %v", err)
+	}
+	raw, _ := tx.Raw()
+	if raw.Timestamp != now.UnixMilli() || raw.Expiration != now.Add(DefaultTransactionExpiration).UnixMilli() || raw.FeeLimit != 10_000_000 {
+		t.Fatalf("unexpected header %+v", raw)
+	}
+}
+
+func TestNewTransaction_Errors(t *testing.T) {
+	from, to := "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK"
+	cases := map[string]func(*TransactionOptions){
+		"short block ID":      func(o *TransactionOptions) { o.RefBlockID = o.RefBlockID[:8] },
+		"negative fee":        func(o *TransactionOptions) { o.FeeLimit = -1 },
+		"negative permission": func(o *TransactionOptions) { o.PermissionID = -1 },
+		"expired":             func(o *TransactionOptions) { o.Expiration = o.Timestamp },
+	}
+	for name, mutate := range cases {
+		opts := testTxOptions()
+		mutate(&opts)
+		if _, err := NewTransferTransaction(opts, from, to, 1); !errors.Is(err, ErrInvalidTransaction) {
+			t.Fatalf("%s: expected ErrInvalidTransaction, got %v", name, err)
+		}
+	}
+	if _, err := NewTransaction(testTxOptions(), nil); !errors.Is(err, ErrInvalidTransaction) {
+		t.Fatalf("expected ErrInvalidTransaction for nil parameter, got %v", err)
+	}
+}
+
+func TestParseTransactionRaw_UnknownContract(t *testing.T) {
+	raw := &TransactionRaw{
+		RefBlockBytes: []byte{1, 2},
+		RefBlockHash:  []byte{1, 2, 3, 4, 5, 6, 7, 8},
+		Expiration:    2,
+		Contracts: []Contract{{
+			Parameter:    &UnknownContract{Type: 99, TypeURL: "type.googleapis.com/protocol.Future", Value: []byte{0x08, 0x01}},
+			PermissionID: 2,
+		}},
+		Timestamp: 1,
+	}
+	b, err := raw.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	parsed, err := ParseTransactionRaw(b)
+	if err != nil {
+		t.Fatalf("ParseTransactionRaw error: %v", err)
+	}
+	u, ok := parsed.Contracts[0].Parameter.(*UnknownContract)
+	if !ok || u.Type != 99 || u.TypeURL != "type.googleapis.com/protocol.Future" || parsed.Contracts[0].PermissionID != 2 {
+		t.Fatalf("unexpected contract %+v", parsed.Contracts[0])
+	}
+	if u.ContractType().String() != "ContractType(99)" {
+		t.Fatalf("unexpected type name %s", u.ContractType())
+	}
+	again, _ := parsed.Marshal()
+	if !bytes.Equal(again, b) {
+		t.Fatalf("unknown contract did not round trip")
+	}
+}
+
+func TestParseTransaction_Malformed(t *testing.T) {
+	good, _ := hex.DecodeString(transferRawHex)
+	// Contract type says TransferContract but the Any claims another message.
+	mismatched := bytes.Replace(good, []byte("TransferContract"), []byte("TransferContracX"), 1)
+	// A TransferContract whose owner address is truncated to 20 bytes.
+	badOwner, _ := hex.DecodeString("5a66080112620a2d747970652e676f6f676c65617069732e636f6d2f70726f746f636f6c2e5472616e73666572436f6e747261637412310a14c8599111f29c1e1e061265b4af93ea1f274ad78a121541b6e708a39781c96bd399c7657780ff9fe9f052a818c0843d")
+
+	for name, raw := range map[string][]byte{
+		"truncated":       good[:len(good)-3],
+		"type mismatch":   mismatched,
+		"bad owner":       badOwner,
+		"wire type":       {0x08, 0x01, 0x5a, 0x02, 0x08, 0x01, 0x0a, 0x00},
+		"no parameter":    {0x5a, 0x02, 0x08, 0x01},
+		"expiration type": {0x42, 0x00},
+	} {
+		if _, err := ParseTransactionRaw(raw); !errors.Is(err, ErrInvalidTransaction) {
+			t.Fatalf("%s: expected ErrInvalidTransaction, got %v", name, err)
+		}
+		tx := &Transaction{RawData: raw}
+		if _, err := ParseTransaction(tx.Marshal()); err == nil {
+			t.Fatalf("%s: expected ParseTransaction error", name)
+		}
+	}
+	if _, err := ParseTransaction([]byte{0x0a}); !errors.Is(err, ErrInvalidTransaction) {
+		t.Fatalf("expected ErrInvalidTransaction, got %v", err)
+	}
+	if _, err := ParseTransaction([]byte{0x10, 0x01}); !errors.Is(err, ErrInvalidTransaction) {
+		t.Fatalf("expected ErrInvalidTransaction for varint signature, got %v", err)
+	}
+}
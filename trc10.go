@@ -0,0 +1,26 @@
+package tronwallet
+
+import "fmt"
+
+// NewTRC10TransferTransaction builds an unsigned transfer of amount units of
+// the TRC10 token with the given decimal ID.
+func NewTRC10TransferTransaction(opts TransactionOptions, from, to, tokenID string, amount int64) (*Transaction, error) {
+	return NewTransaction(opts, &TransferAssetContract{AssetName: tokenID, OwnerAddress: from, ToAddress: to, Amount: amount})
+}
+
+// ParseTRC10Transfer returns the TRC10 transfer carried by tx, or an error if
+// tx is not a TRC10 transfer.
+func ParseTRC10Transfer(tx *Transaction) (*TransferAssetContract, error) {
+	raw, err := tx.Raw()
+	if err != nil {
+		return nil, err
+	}
+	if len(raw.Contracts) != 1 {
+		return nil, fmt.Errorf("%w: expected one contract, got %d", ErrInvalidTransaction, len(raw.Contracts))
+	}
+	c, ok := raw.Contracts[0].Parameter.(*TransferAssetContract)
+	if !ok {
+		return nil, fmt.Errorf("not a TRC10 transfer: %s", raw.Contracts[0].Parameter.ContractType())
+	}
+	return c, nil
+}
@@ -0,0 +1,72 @@
+package tronwallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// trc10RawHex is the java-tron serialization of a transfer of 250 units of
+// token 1002000 from account 0 to account 1 of testMnemonic.
+const trc10RawHex = "0a02abcd2208112233445566778840e0a499ffbc315a74080212700a32747970652e676f6f676c65617069732e636f6d2f70726f746f636f6c2e5472616e736665724173736574436f6e7472616374123a0a0731303032303030121541c8599111f29c1e1e061265b4af93ea1f274ad78a1a1541b6e708a39781c96bd399c7657780ff9fe9f052a820fa017080d095ffbc31"
+
+func TestNewTRC10TransferTransaction(t *testing.T) {
+	tx, err := NewTRC10TransferTransaction(testTxOptions(), "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", "1002000", 250)
+	if err != nil {
+		t.Fatalf("NewTRC10TransferTransaction error: %v", err)
+	}
+	if got := hex.EncodeToString(tx.RawData); got != trc10RawHex {
+		t.Fatalf("raw data mismatch:\n got %s\nwant %s", got, trc10RawHex)
+	}
+}
+
+func TestParseTRC10Transfer(t *testing.T) {
+	raw, _ := hex.DecodeString(trc10RawHex)
+	c, err := ParseTRC10Transfer(&Transaction{RawData: raw})
+	if err != nil {
+		t.Fatalf("ParseTRC10Transfer error: %v", err)
+	}
+	want := TransferAssetContract{AssetName: "1002000", OwnerAddress: "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", ToAddress: "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", Amount: 250}
+	if *c != want {
+		t.Fatalf("got %+v want %+v", *c, want)
+	}
+
+	trx, _ := hex.DecodeString(transferRawHex)
+	if _, err := ParseTRC10Transfer(&Transaction{RawData: trx}); err == nil {
+		t.Fatalf("expected error for a TRX transfer")
+	}
+	if _, err := ParseTRC10Transfer(&Transaction{RawData: []byte{0x0a}}); !errors.Is(err, ErrInvalidTransaction) {
+		t.Fatalf("expected ErrInvalidTransaction, got %v", err)
+	}
+	if _, err := ParseTRC10Transfer(&Transaction{RawData: raw[:4]}); !errors.Is(err, ErrInvalidTransaction) {
+		t.Fatalf("expected ErrInvalidTransaction for missing contract, got %v", err)
+	}
+	// Wrong wire type for asset_name.
+	if _, err := parseTransferAssetContract([]byte{0x08, 0x01}); err == nil {
+		t.Fatalf("expected wire type error")
+	}
+	if _, err := parseTransferAssetContract([]byte{0x0a}); err == nil {
+		t.Fatalf("expected truncation error")
+	}
+}
+
+func TestTRC10Transfer_Validation(t *testing.T) {
+	from, to := "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK"
+	for _, id := range []string{"", "0", "01002000", "BitTorrent", "12345678901234567890"} {
+		if _, err := NewTRC10TransferTransaction(testTxOptions(), from, to, id, 1); err == nil {
+			t.Fatalf("expected error for token ID %q", id)
+		}
+	}
+	if _, err := NewTRC10TransferTransaction(testTxOptions(), "nope", to, "1002000", 1); !errors.Is(err, ErrInvalidAddress) {
+		t.Fatalf("expected ErrInvalidAddress, got %v", err)
+	}
+	if _, err := NewTRC10TransferTransaction(testTxOptions(), from, "nope", "1002000", 1); !errors.Is(err, ErrInvalidAddress) {
+		t.Fatalf("expected ErrInvalidAddress, got %v", err)
+	}
+	if _, err := NewTRC10TransferTransaction(testTxOptions(), from, from, "1002000", 1); err == nil {
+		t.Fatalf("expected error for self transfer")
+	}
+	if _, err := NewTRC10TransferTransaction(testTxOptions(), from, to, "1002000", 0); !errors.Is(err, ErrInvalidAmount) {
+		t.Fatalf("expected ErrInvalidAmount, got %v", err)
+	}
+}
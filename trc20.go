@@ -0,0 +1,69 @@
+package tronwallet
+
+import "math/big"
+
+// The TRC20 functions built by this package and decoded by
+// SummarizeTransaction.
+var (
+	trc20Transfer = ABIEntry{Type: "function", Name: "transfer", Inputs: []ABIParam{
+		{Name: "to", Type: "address"}, {Name: "amount", Type: "uint256"},
+	}}
+	trc20Approve = ABIEntry{Type: "function", Name: "approve", Inputs: []ABIParam{
+		{Name: "spender", Type: "address"}, {Name: "amount", Type: "uint256"},
+	}}
+	trc20TransferFrom = ABIEntry{Type: "function", Name: "transferFrom", Inputs: []ABIParam{
+		{Name: "from", Type: "address"}, {Name: "to", Type: "address"}, {Name: "amount", Type: "uint256"},
+	}}
+)
+
+// abiSelector returns the 4-byte selector of a canonical function signature.
+func abiSelector(signature string) []byte {
+	return keccak256([]byte(signature))[:4]
+}
+
+// abiAddressWord encodes a TRON address as a 32-byte ABI word. Contracts see
+// the 20-byte account hash, so the 0x41 prefix is dropped.
+func abiAddressWord(address string) ([]byte, error) {
+	raw, err := DecodeAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	word := make([]byte, 32)
+	copy(word[12:], raw[1:])
+	return word, nil
+}
+
+// trc20Call builds an unsigned call of a TRC20 function of token.
+func trc20Call(opts TransactionOptions, owner, token string, callValue int64, fn ABIEntry, args ...any) (*Transaction, error) {
+	data, err := fn.EncodeCall(args...)
+	if err != nil {
+		return nil, err
+	}
+	return NewTriggerSmartContractTransaction(opts, owner, token, callValue, data)
+}
+
+// NewTRC20TransferTransaction builds an unsigned call of token's
+// transfer(to, amount). Amounts are in the token's smallest unit; opts.FeeLimit
+// must be set. callValue is the TRX, in sun, sent along with the call and is
+// zero for ordinary tokens.
+func NewTRC20TransferTransaction(opts TransactionOptions, owner, token string, callValue int64, to string, amount *big.Int) (*Transaction, error) {
+	if amount != nil && amount.Sign() <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	return trc20Call(opts, owner, token, callValue, trc20Transfer, to, amount)
+}
+
+// NewTRC20ApproveTransaction builds an unsigned call of token's
+// approve(spender, amount). A zero amount revokes the allowance.
+func NewTRC20ApproveTransaction(opts TransactionOptions, owner, token string, callValue int64, spender string, amount *big.Int) (*Transaction, error) {
+	return trc20Call(opts, owner, token, callValue, trc20Approve, spender, amount)
+}
+
+// NewTRC20TransferFromTransaction builds an unsigned call of token's
+// transferFrom(from, to, amount), spending an allowance granted to owner.
+func NewTRC20TransferFromTransaction(opts TransactionOptions, owner, token string, callValue int64, from, to string, amount *big.Int) (*Transaction, error) {
+	if amount != nil && amount.Sign() <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	return trc20Call(opts, owner, token, callValue, trc20TransferFrom, from, to, amount)
+}
@@ -0,0 +1,120 @@
+package tronwallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+const testUSDT = "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t"
+
+func TestTRC20Selectors(t *testing.T) {
+	for sel, want := range map[string]string{
+		hex.EncodeToString(trc20Transfer.Selector()):     "a9059cbb",
+		hex.EncodeToString(trc20Approve.Selector()):      "095ea7b3",
+		hex.EncodeToString(trc20TransferFrom.Selector()): "23b872dd",
+	} {
+		if sel != want {
+			t.Fatalf("selector %s, want %s", sel, want)
+		}
+	}
+}
+
+func TestNewTRC20TransferTransaction(t *testing.T) {
+	opts := testTxOptions()
+	opts.FeeLimit = 100_000_000
+	tx, err := NewTRC20TransferTransaction(opts, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", testUSDT, 0, "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", big.NewInt(1_000_000))
+	if err != nil {
+		t.Fatalf("NewTRC20TransferTransaction error: %v", err)
+	}
+	want := "0a02abcd2208112233445566778840e0a499ffbc315aae01081f12a9010a31747970652e676f6f676c65617069732e636f6d2f70726f746f636f6c2e54726967676572536d617274436f6e747261637412740a1541c8599111f29c1e1e061265b4af93ea1f274ad78a121541a614f803b6fd780986a42c78ec9c7f77e6ded13c2244a9059cbb000000000000000000000000b6e708a39781c96bd399c7657780ff9fe9f052a800000000000000000000000000000000000000000000000000000000000f42407080d095ffbc31900180c2d72f"
+	if got := hex.EncodeToString(tx.RawData); got != want {
+		t.Fatalf("raw data mismatch:\n got %s\nwant %s", got, want)
+	}
+	raw, err := tx.Raw()
+	if err != nil {
+		t.Fatalf("Raw error: %v", err)
+	}
+	c, ok := raw.Contracts[0].Parameter.(*TriggerSmartContract)
+	if !ok || c.ContractAddress != testUSDT || c.CallValue != 0 || raw.FeeLimit != 100_000_000 {
+		t.Fatalf("unexpected decoded contract %+v", raw.Contracts[0].Parameter)
+	}
+
+	tx, err = NewTRC20TransferTransaction(opts, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", testUSDT, 2_000_000, "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", big.NewInt(1_000_000))
+	if err != nil {
+		t.Fatalf("NewTRC20TransferTransaction error: %v", err)
+	}
+	raw, _ = tx.Raw()
+	if c := raw.Contracts[0].Parameter.(*TriggerSmartContract); c.CallValue != 2_000_000 {
+		t.Fatalf("call value = %d", c.CallValue)
+	}
+}
+
+func TestNewTRC20ApproveAndTransferFrom(t *testing.T) {
+	opts := testTxOptions()
+	opts.FeeLimit = 1
+	owner, spender, to := "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", "TYJPRrdB5APNeRs4R7fYZSwW3TcrTKw2gx"
+	max := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+	tx, err := NewTRC20ApproveTransaction(opts, owner, testUSDT, 0, spender, max)
+	if err != nil {
+		t.Fatalf("NewTRC20ApproveTransaction error: %v", err)
+	}
+	raw, _ := tx.Raw()
+	data := hex.EncodeToString(raw.Contracts[0].Parameter.(*TriggerSmartContract).Data)
+	if data != "095ea7b3000000000000000000000000b6e708a39781c96bd399c7657780ff9fe9f052a8"+strings.Repeat("f", 64) {
+		t.Fatalf("unexpected approve data %s", data)
+	}
+	if _, err := NewTRC20ApproveTransaction(opts, owner, testUSDT, 0, spender, new(big.Int)); err != nil {
+		t.Fatalf("zero approval should be allowed: %v", err)
+	}
+
+	tx, err = NewTRC20TransferFromTransaction(opts, spender, testUSDT, 0, owner, to, big.NewInt(255))
+	if err != nil {
+		t.Fatalf("NewTRC20TransferFromTransaction error: %v", err)
+	}
+	raw, _ = tx.Raw()
+	c := raw.Contracts[0].Parameter.(*TriggerSmartContract)
+	data = hex.EncodeToString(c.Data)
+	if c.OwnerAddress != spender || !strings.HasPrefix(data, "23b872dd000000000000000000000000c8599111f29c1e1e061265b4af93ea1f274ad78a") || !strings.HasSuffix(data, "ff") || len(c.Data) != 4+3*32 {
+		t.Fatalf("unexpected transferFrom data %s", data)
+	}
+}
+
+func TestTRC20_Errors(t *testing.T) {
+	opts := testTxOptions()
+	opts.FeeLimit = 1
+	owner, to := "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK"
+	tooBig := new(big.Int).Lsh(big.NewInt(1), 256)
+
+	if _, err := NewTRC20TransferTransaction(opts, owner, testUSDT, 0, to, big.NewInt(0)); !errors.Is(err, ErrInvalidAmount) {
+		t.Fatalf("expected ErrInvalidAmount, got %v", err)
+	}
+	if _, err := NewTRC20TransferFromTransaction(opts, owner, testUSDT, 0, owner, to, big.NewInt(-1)); !errors.Is(err, ErrInvalidAmount) {
+		t.Fatalf("expected ErrInvalidAmount, got %v", err)
+	}
+	if _, err := NewTRC20TransferTransaction(opts, owner, testUSDT, 0, "nope", big.NewInt(1)); !errors.Is(err, ErrInvalidAddress) {
+		t.Fatalf("expected ErrInvalidAddress, got %v", err)
+	}
+	if _, err := NewTRC20TransferFromTransaction(opts, owner, testUSDT, 0, "nope", to, big.NewInt(1)); !errors.Is(err, ErrInvalidAddress) {
+		t.Fatalf("expected ErrInvalidAddress, got %v", err)
+	}
+	if _, err := NewTRC20ApproveTransaction(opts, owner, testUSDT, 0, to, nil); err == nil {
+		t.Fatalf("expected error for missing amount")
+	}
+	if _, err := NewTRC20ApproveTransaction(opts, owner, testUSDT, 0, to, tooBig); err == nil {
+		t.Fatalf("expected error for amount overflowing uint256")
+	}
+	if _, err := NewTRC20ApproveTransaction(opts, owner, testUSDT, -1, to, big.NewInt(1)); err == nil {
+		t.Fatalf("expected error for a negative call value")
+	}
+	if _, err := NewTRC20TransferTransaction(opts, owner, "nope", 0, to, big.NewInt(1)); !errors.Is(err, ErrInvalidAddress) {
+		t.Fatalf("expected ErrInvalidAddress for token, got %v", err)
+	}
+	opts.FeeLimit = 0
+	if _, err := NewTRC20TransferTransaction(opts, owner, testUSDT, 0, to, big.NewInt(1)); !errors.Is(err, ErrInvalidTransaction) {
+		t.Fatalf("expected ErrInvalidTransaction without fee limit, got %v", err)
+	}
+}
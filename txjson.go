@@ -0,0 +1,430 @@
+package tronwallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Errors returned by ParseTransactionJSON when the parts of a transaction
+// disagree with raw_data_hex, which is the only part that is signed.
+var (
+	ErrTxIDMismatch    = errors.New("txID does not match raw_data_hex")
+	ErrRawDataMismatch = errors.New("raw_data does not match raw_data_hex")
+)
+
+// transactionJSON is the transaction format of TRON's HTTP API and TronGrid.
+type transactionJSON struct {
+	Visible    bool            `json:"visible"`
+	TxID       string          `json:"txID"`
+	RawData    json.RawMessage `json:"raw_data"`
+	RawDataHex string          `json:"raw_data_hex"`
+	Signature  []string        `json:"signature,omitempty"`
+}
+
+// MarshalJSON encodes tx in the HTTP API format with Base58 addresses
+// (visible=true).
+func (tx *Transaction) MarshalJSON() ([]byte, error) {
+	return tx.JSON(true)
+}
+
+// UnmarshalJSON decodes and verifies tx like ParseTransactionJSON.
+func (tx *Transaction) UnmarshalJSON(data []byte) error {
+	parsed, err := ParseTransactionJSON(data)
+	if err != nil {
+		return err
+	}
+	*tx = *parsed
+	return nil
+}
+
+// JSON encodes tx in the format of TRON's HTTP API: visible, txID, raw_data,
+// raw_data_hex and signature. Addresses in raw_data are Base58 when visible
+// is true and 41-prefixed hex otherwise. Contracts of unknown types cannot
+// be rendered and return an error.
+func (tx *Transaction) JSON(visible bool) ([]byte, error) {
+	rawData, err := txJSONRenderer{visible: visible}.render(tx.RawData, "Transaction.raw")
+	if err != nil {
+		return nil, err
+	}
+	rawJSON, err := json.Marshal(rawData)
+	if err != nil {
+		return nil, err
+	}
+	j := transactionJSON{
+		Visible:    visible,
+		TxID:       hex.EncodeToString(tx.ID()),
+		RawData:    rawJSON,
+		RawDataHex: hex.EncodeToString(tx.RawData),
+	}
+	for _, sig := range tx.Signatures {
+		j.Signature = append(j.Signature, hex.EncodeToString(sig))
+	}
+	return json.Marshal(j)
+}
+
+// ParseTransactionJSON decodes a transaction in the HTTP API format, in
+// either visible mode. raw_data_hex is authoritative: txID must be its
+// SHA-256, and raw_data, when present, must describe the same transaction,
+// so a tampered JSON view cannot hide what is being signed. The parameter
+// values of contract types this package does not know are not compared.
+func ParseTransactionJSON(data []byte) (*Transaction, error) {
+	var j transactionJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, err
+	}
+	raw, err := hex.DecodeString(j.RawDataHex)
+	if err != nil || len(raw) == 0 {
+		return nil, fmt.Errorf("%w: missing or invalid raw_data_hex", ErrInvalidTransaction)
+	}
+	tx := &Transaction{RawData: raw}
+	if !strings.EqualFold(j.TxID, hex.EncodeToString(tx.ID())) {
+		return nil, ErrTxIDMismatch
+	}
+	if _, err := tx.Raw(); err != nil {
+		return nil, err
+	}
+	if len(j.RawData) > 0 && string(j.RawData) != "null" {
+		if err := checkRawDataJSON(raw, j.RawData, j.Visible); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range j.Signature {
+		sig, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid signature encoding", ErrInvalidTransaction)
+		}
+		tx.Signatures = append(tx.Signatures, sig)
+	}
+	return tx, nil
+}
+
+// checkRawDataJSON compares the raw_data JSON with the rendering of raw.
+func checkRawDataJSON(raw []byte, given json.RawMessage, visible bool) error {
+	want, err := txJSONRenderer{visible: visible, lenient: true}.render(raw, "Transaction.raw")
+	if err != nil {
+		return err
+	}
+	wantJSON, err := json.Marshal(want)
+	if err != nil {
+		return err
+	}
+	var a, b any
+	if err := decodeJSONNumbers(wantJSON, &a); err != nil {
+		return err
+	}
+	if err := decodeJSONNumbers(given, &b); err != nil {
+		return fmt.Errorf("%w: %v", ErrRawDataMismatch, err)
+	}
+	dropOpaqueValues(a, b)
+	if !reflect.DeepEqual(a, b) {
+		return ErrRawDataMismatch
+	}
+	return nil
+}
+
+func decodeJSONNumbers(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// dropOpaqueValues removes from given the parameter values of contracts that
+// the lenient rendering want left out because their type is unknown.
+func dropOpaqueValues(want, given any) {
+	w, _ := want.(map[string]any)
+	g, _ := given.(map[string]any)
+	wc, _ := w["contract"].([]any)
+	gc, _ := g["contract"].([]any)
+	for i := range wc {
+		if i >= len(gc) {
+			return
+		}
+		wp, _ := wc[i].(map[string]any)["parameter"].(map[string]any)
+		gcm, _ := gc[i].(map[string]any)
+		gp, _ := gcm["parameter"].(map[string]any)
+		if _, ok := wp["value"]; !ok && gp != nil {
+			delete(gp, "value")
+		}
+	}
+}
+
+// protoJSONKind says how a protobuf field is rendered in the HTTP API.
+type protoJSONKind int
+
+const (
+	jsonInt     protoJSONKind = iota // number
+	jsonBool                         // true/false
+	jsonBytes                        // hex
+	jsonAddress                      // hex, or Base58 when visible
+	jsonName                         // hex, or UTF-8 text when visible
+	jsonString                       // UTF-8 text
+	jsonEnum                         // enum name
+	jsonMessage                      // nested object
+	jsonAny                          // {"value": ..., "type_url": ...}
+)
+
+// protoJSONField describes one field of a message schema.
+type protoJSONField struct {
+	num      int
+	name     string
+	kind     protoJSONKind
+	repeated bool
+	message  string             // schema of a jsonMessage field
+	enum     func(int64) string // names of a jsonEnum field
+}
+
+func contractTypeName(v int64) string   { return ContractType(v).String() }
+func resourceName(v int64) string       { return ResourceCode(v).String() }
+func permissionTypeName(v int64) string { return PermissionType(v).String() }
+
+// protoJSONSchemas describes the messages that can be rendered as JSON, keyed
+// by message name. Contract parameters are keyed by their type name.
+var protoJSONSchemas = map[string][]protoJSONField{
+	"Transaction.raw": {
+		{num: 1, name: "ref_block_bytes", kind: jsonBytes},
+		{num: 3, name: "ref_block_num", kind: jsonInt},
+		{num: 4, name: "ref_block_hash", kind: jsonBytes},
+		{num: 8, name: "expiration", kind: jsonInt},
+		{num: 10, name: "data", kind: jsonBytes},
+		{num: 11, name: "contract", kind: jsonMessage, repeated: true, message: "Transaction.Contract"},
+		{num: 14, name: "timestamp", kind: jsonInt},
+		{num: 18, name: "fee_limit", kind: jsonInt},
+	},
+	"Transaction.Contract": {
+		{num: 1, name: "type", kind: jsonEnum, enum: contractTypeName},
+		{num: 2, name: "parameter", kind: jsonAny},
+		{num: 5, name: "Permission_id", kind: jsonInt},
+	},
+	"TransferContract": {
+		{num: 1, name: "owner_address", kind: jsonAddress},
+		{num: 2, name: "to_address", kind: jsonAddress},
+		{num: 3, name: "amount", kind: jsonInt},
+	},
+	"TransferAssetContract": {
+		{num: 1, name: "asset_name", kind: jsonName},
+		{num: 2, name: "owner_address", kind: jsonAddress},
+		{num: 3, name: "to_address", kind: jsonAddress},
+		{num: 4, name: "amount", kind: jsonInt},
+	},
+	"VoteWitnessContract": {
+		{num: 1, name: "owner_address", kind: jsonAddress},
+		{num: 2, name: "votes", kind: jsonMessage, repeated: true, message: "VoteWitnessContract.Vote"},
+		{num: 3, name: "support", kind: jsonBool},
+	},
+	"VoteWitnessContract.Vote": {
+		{num: 1, name: "vote_address", kind: jsonAddress},
+		{num: 2, name: "vote_count", kind: jsonInt},
+	},
+	"WithdrawBalanceContract": {
+		{num: 1, name: "owner_address", kind: jsonAddress},
+	},
+	"CreateSmartContract": {
+		{num: 1, name: "owner_address", kind: jsonAddress},
+		{num: 2, name: "new_contract", kind: jsonMessage, message: "SmartContract"},
+		{num: 3, name: "call_token_value", kind: jsonInt},
+		{num: 4, name: "token_id", kind: jsonInt},
+	},
+	"SmartContract": {
+		{num: 1, name: "origin_address", kind: jsonAddress},
+		{num: 2, name: "contract_address", kind: jsonAddress},
+		{num: 3, name: "abi", kind: jsonMessage, message: "SmartContract.ABI"},
+		{num: 4, name: "bytecode", kind: jsonBytes},
+		{num: 5, name: "call_value", kind: jsonInt},
+		{num: 6, name: "consume_user_resource_percent", kind: jsonInt},
+		{num: 7, name: "name", kind: jsonString},
+		{num: 8, name: "origin_energy_limit", kind: jsonInt},
+	},
+	"SmartContract.ABI": {
+		{num: 1, name: "entrys", kind: jsonMessage, repeated: true, message: "SmartContract.ABI.Entry"},
+	},
+	"SmartContract.ABI.Entry": {
+		{num: 1, name: "anonymous", kind: jsonBool},
+		{num: 2, name: "constant", kind: jsonBool},
+		{num: 3, name: "name", kind: jsonString},
+		{num: 4, name: "inputs", kind: jsonMessage, repeated: true, message: "SmartContract.ABI.Entry.Param"},
+		{num: 5, name: "outputs", kind: jsonMessage, repeated: true, message: "SmartContract.ABI.Entry.Param"},
+		{num: 6, name: "type", kind: jsonEnum, enum: abiEntryTypeName},
+		{num: 7, name: "payable", kind: jsonBool},
+		{num: 8, name: "stateMutability", kind: jsonEnum, enum: abiStateMutabilityName},
+	},
+	"SmartContract.ABI.Entry.Param": {
+		{num: 1, name: "indexed", kind: jsonBool},
+		{num: 2, name: "name", kind: jsonString},
+		{num: 3, name: "type", kind: jsonString},
+	},
+	"TriggerSmartContract": {
+		{num: 1, name: "owner_address", kind: jsonAddress},
+		{num: 2, name: "contract_address", kind: jsonAddress},
+		{num: 3, name: "call_value", kind: jsonInt},
+		{num: 4, name: "data", kind: jsonBytes},
+		{num: 5, name: "call_token_value", kind: jsonInt},
+		{num: 6, name: "token_id", kind: jsonInt},
+	},
+	"AccountPermissionUpdateContract": {
+		{num: 1, name: "owner_address", kind: jsonAddress},
+		{num: 2, name: "owner", kind: jsonMessage, message: "Permission"},
+		{num: 3, name: "witness", kind: jsonMessage, message: "Permission"},
+		{num: 4, name: "actives", kind: jsonMessage, repeated: true, message: "Permission"},
+	},
+	"Permission": {
+		{num: 1, name: "type", kind: jsonEnum, enum: permissionTypeName},
+		{num: 2, name: "id", kind: jsonInt},
+		{num: 3, name: "permission_name", kind: jsonString},
+		{num: 4, name: "threshold", kind: jsonInt},
+		{num: 5, name: "parent_id", kind: jsonInt},
+		{num: 6, name: "operations", kind: jsonBytes},
+		{num: 7, name: "keys", kind: jsonMessage, repeated: true, message: "Key"},
+	},
+	"Key": {
+		{num: 1, name: "address", kind: jsonAddress},
+		{num: 2, name: "weight", kind: jsonInt},
+	},
+	"FreezeBalanceV2Contract": {
+		{num: 1, name: "owner_address", kind: jsonAddress},
+		{num: 2, name: "frozen_balance", kind: jsonInt},
+		{num: 3, name: "resource", kind: jsonEnum, enum: resourceName},
+	},
+	"UnfreezeBalanceV2Contract": {
+		{num: 1, name: "owner_address", kind: jsonAddress},
+		{num: 2, name: "unfreeze_balance", kind: jsonInt},
+		{num: 3, name: "resource", kind: jsonEnum, enum: resourceName},
+	},
+	"WithdrawExpireUnfreezeContract": {
+		{num: 1, name: "owner_address", kind: jsonAddress},
+	},
+	"DelegateResourceContract": {
+		{num: 1, name: "owner_address", kind: jsonAddress},
+		{num: 2, name: "resource", kind: jsonEnum, enum: resourceName},
+		{num: 3, name: "balance", kind: jsonInt},
+		{num: 4, name: "receiver_address", kind: jsonAddress},
+		{num: 5, name: "lock", kind: jsonBool},
+		{num: 6, name: "lock_period", kind: jsonInt},
+	},
+	"UnDelegateResourceContract": {
+		{num: 1, name: "owner_address", kind: jsonAddress},
+		{num: 2, name: "resource", kind: jsonEnum, enum: resourceName},
+		{num: 3, name: "balance", kind: jsonInt},
+		{num: 4, name: "receiver_address", kind: jsonAddress},
+	},
+	"CancelAllUnfreezeV2Contract": {
+		{num: 1, name: "owner_address", kind: jsonAddress},
+	},
+}
+
+// txJSONRenderer renders protobuf messages the way java-tron's JsonFormat
+// does. Only fields present in the encoding are rendered, so defaults are
+// omitted. A lenient renderer leaves out the values of unknown contract
+// types instead of failing.
+type txJSONRenderer struct {
+	visible bool
+	lenient bool
+}
+
+func (r txJSONRenderer) render(b []byte, schema string) (map[string]any, error) {
+	specs, ok := protoJSONSchemas[schema]
+	if !ok {
+		return nil, fmt.Errorf("no JSON schema for %s", schema)
+	}
+	fields, err := parseProto(b)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+	out := map[string]any{}
+	for _, f := range fields {
+		for _, spec := range specs {
+			if spec.num != f.Num {
+				continue
+			}
+			v, err := r.value(f, spec)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", schema, spec.name, err)
+			}
+			if spec.repeated {
+				list, _ := out[spec.name].([]any)
+				out[spec.name] = append(list, v)
+			} else {
+				out[spec.name] = v
+			}
+		}
+	}
+	return out, nil
+}
+
+func (r txJSONRenderer) value(f protoField, spec protoJSONField) (any, error) {
+	switch spec.kind {
+	case jsonInt:
+		v, err := f.int64()
+		return json.Number(strconv.FormatInt(v, 10)), err
+	case jsonBool:
+		v, err := f.uint64()
+		return v != 0, err
+	case jsonEnum:
+		v, err := f.int64()
+		return spec.enum(v), err
+	}
+
+	b, err := f.bytes()
+	if err != nil {
+		return nil, err
+	}
+	switch spec.kind {
+	case jsonAddress:
+		if r.visible {
+			return EncodeAddress(b)
+		}
+	case jsonName:
+		if r.visible {
+			return string(b), nil
+		}
+	case jsonString:
+		return string(b), nil
+	case jsonMessage:
+		return r.render(b, spec.message)
+	case jsonAny:
+		return r.any(b)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// any renders a google.protobuf.Any holding a contract parameter.
+func (r txJSONRenderer) any(b []byte) (any, error) {
+	fields, err := parseProto(b)
+	if err != nil {
+		return nil, err
+	}
+	var typeURL string
+	var value []byte
+	for _, f := range fields {
+		switch f.Num {
+		case 1:
+			var u []byte
+			u, err = f.bytes()
+			typeURL = string(u)
+		case 2:
+			value, err = f.bytes()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	out := map[string]any{"type_url": typeURL}
+	name := strings.TrimPrefix(typeURL, contractTypeURLPrefix)
+	if _, ok := protoJSONSchemas[name]; !ok {
+		if r.lenient {
+			return out, nil
+		}
+		return nil, fmt.Errorf("cannot render %s as JSON", typeURL)
+	}
+	v, err := r.render(value, name)
+	if err != nil {
+		return nil, err
+	}
+	out["value"] = v
+	return out, nil
+}
@@ -0,0 +1,287 @@
+package tronwallet
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// transferJSON is the HTTP API rendering of transferRawHex, in the layout
+// returned by /wallet/createtransaction.
+const transferJSON = `{
+  "visible": false,
+  "txID": "57160c72887d410f866470f4515b6bf9d67169321441f1fd372868ba43fb9068",
+  "raw_data": {
+    "contract": [{
+      "parameter": {
+        "value": {
+          "amount": 1000000,
+          "owner_address": "41c8599111f29c1e1e061265b4af93ea1f274ad78a",
+          "to_address": "41b6e708a39781c96bd399c7657780ff9fe9f052a8"
+        },
+        "type_url": "type.googleapis.com/protocol.TransferContract"
+      },
+      "type": "TransferContract"
+    }],
+    "ref_block_bytes": "abcd",
+    "ref_block_hash": "1122334455667788",
+    "expiration": 1700000060000,
+    "timestamp": 1700000000000
+  },
+  "raw_data_hex": "` + transferRawHex + `"
+}`
+
+func TestParseTransactionJSON(t *testing.T) {
+	tx, err := ParseTransactionJSON([]byte(transferJSON))
+	if err != nil {
+		t.Fatalf("ParseTransactionJSON error: %v", err)
+	}
+	if hex.EncodeToString(tx.RawData) != transferRawHex || len(tx.Signatures) != 0 {
+		t.Fatalf("unexpected transaction %x", tx.RawData)
+	}
+
+	visible := strings.NewReplacer(
+		`"visible": false`, `"visible": true`,
+		`"41c8599111f29c1e1e061265b4af93ea1f274ad78a"`, `"TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH"`,
+		`"41b6e708a39781c96bd399c7657780ff9fe9f052a8"`, `"TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK"`,
+	).Replace(transferJSON)
+	if _, err := ParseTransactionJSON([]byte(visible)); err != nil {
+		t.Fatalf("visible ParseTransactionJSON error: %v", err)
+	}
+
+	// Hex addresses while claiming visible=true do not match.
+	mixed := strings.Replace(transferJSON, `"visible": false`, `"visible": true`, 1)
+	if _, err := ParseTransactionJSON([]byte(mixed)); !errors.Is(err, ErrRawDataMismatch) {
+		t.Fatalf("expected ErrRawDataMismatch, got %v", err)
+	}
+}
+
+func TestParseTransactionJSON_Tampering(t *testing.T) {
+	for name, tc := range map[string]struct {
+		old, new string
+		want     error
+	}{
+		"amount":        {`"amount": 1000000`, `"amount": 9000000`, ErrRawDataMismatch},
+		"recipient":     {`"to_address": "41b6e7`, `"to_address": "41b6e8`, ErrRawDataMismatch},
+		"extra field":   {`"timestamp": 1700000000000`, `"timestamp": 1700000000000, "fee_limit": 1`, ErrRawDataMismatch},
+		"txID":          {`"txID": "5716`, `"txID": "5816`, ErrTxIDMismatch},
+		"raw hex":       {`"raw_data_hex": "0a02abcd`, `"raw_data_hex": "0a02abce`, ErrTxIDMismatch},
+		"bad raw hex":   {`"raw_data_hex": "0a`, `"raw_data_hex": "zz`, ErrInvalidTransaction},
+		"raw_data type": {`"raw_data": {`, `"raw_data": "x", "ignored": {`, ErrRawDataMismatch},
+		"bad signature": {`"raw_data_hex"`, `"signature": ["zz"], "raw_data_hex"`, ErrInvalidTransaction},
+	} {
+		data := strings.Replace(transferJSON, tc.old, tc.new, 1)
+		if data == transferJSON {
+			t.Fatalf("%s: replacement did not apply", name)
+		}
+		if _, err := ParseTransactionJSON([]byte(data)); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", name, tc.want, err)
+		}
+	}
+	if _, err := ParseTransactionJSON([]byte(`{`)); err == nil {
+		t.Fatalf("expected syntax error")
+	}
+	// A txID over bytes that are not a transaction.
+	junk := &Transaction{RawData: []byte{0x0a}}
+	data := `{"txID":"` + hex.EncodeToString(junk.ID()) + `","raw_data_hex":"0a"}`
+	if _, err := ParseTransactionJSON([]byte(data)); !errors.Is(err, ErrInvalidTransaction) {
+		t.Fatalf("expected ErrInvalidTransaction, got %v", err)
+	}
+}
+
+func TestTransactionJSON_RoundTrip(t *testing.T) {
+	w, _ := RestoreWallet(testMnemonic)
+	priv, _ := w.Derive(0)
+	opts := testTxOptions()
+	opts.FeeLimit = 1
+	builders := []func() (*Transaction, error){
+		func() (*Transaction, error) {
+			return NewTransferTransaction(opts, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", 1)
+		},
+		func() (*Transaction, error) {
+			return NewTRC10TransferTransaction(opts, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", "1002000", 1)
+		},
+		func() (*Transaction, error) {
+			return NewVoteWitnessTransaction(opts, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", []Vote{{"TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", 3}})
+		},
+		func() (*Transaction, error) {
+			return NewDelegateResourceTransaction(opts, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK", 1_000_000, ResourceEnergy, true, 100)
+		},
+	}
+	for i, build := range builders {
+		tx, err := build()
+		if err != nil {
+			t.Fatalf("%d: build error: %v", i, err)
+		}
+		if err := tx.SignWithKey(priv); err != nil {
+			t.Fatalf("%d: sign error: %v", i, err)
+		}
+		for _, visible := range []bool{false, true} {
+			data, err := tx.JSON(visible)
+			if err != nil {
+				t.Fatalf("%d: JSON error: %v", i, err)
+			}
+			back, err := ParseTransactionJSON(data)
+			if err != nil {
+				t.Fatalf("%d: ParseTransactionJSON(%s) error: %v", i, data, err)
+			}
+			if string(back.Marshal()) != string(tx.Marshal()) {
+				t.Fatalf("%d: round trip mismatch", i)
+			}
+		}
+	}
+
+	tx, _ := builders[1]()
+	data, _ := json.Marshal(struct{ Tx *Transaction }{tx})
+	if !strings.Contains(string(data), `"asset_name":"1002000"`) || !strings.Contains(string(data), `"visible":true`) {
+		t.Fatalf("unexpected visible JSON %s", data)
+	}
+	var wrapped struct{ Tx *Transaction }
+	if err := json.Unmarshal(data, &wrapped); err != nil || string(wrapped.Tx.RawData) != string(tx.RawData) {
+		t.Fatalf("UnmarshalJSON failed: %v", err)
+	}
+	hidden, _ := tx.JSON(false)
+	if !strings.Contains(string(hidden), `"asset_name":"31303032303030"`) {
+		t.Fatalf("unexpected hex JSON %s", hidden)
+	}
+	if err := json.Unmarshal([]byte(`{"Tx":{"txID":"00"}}`), &wrapped); err == nil {
+		t.Fatalf("expected UnmarshalJSON error")
+	}
+}
+
+func TestTransactionJSON_UnknownContract(t *testing.T) {
+	raw := &TransactionRaw{
+		RefBlockBytes: []byte{1, 2},
+		Expiration:    2,
+		Contracts:     []Contract{{Parameter: &UnknownContract{Type: 99, TypeURL: "type.googleapis.com/protocol.Future", Value: []byte{0x08, 0x01}}}},
+	}
+	b, _ := raw.Marshal()
+	tx := &Transaction{RawData: b}
+	if _, err := tx.JSON(false); err == nil {
+		t.Fatalf("expected error rendering unknown contract")
+	}
+	data := `{"txID":"` + hex.EncodeToString(tx.ID()) + `","raw_data_hex":"` + hex.EncodeToString(b) + `","raw_data":{"ref_block_bytes":"0102","expiration":2,"contract":[{"type":"ContractType(99)","parameter":{"type_url":"type.googleapis.com/protocol.Future","value":{"anything":1}}}]}}`
+	if _, err := ParseTransactionJSON([]byte(data)); err != nil {
+		t.Fatalf("unknown contract value should not be compared: %v", err)
+	}
+	data = strings.Replace(data, `"expiration":2`, `"expiration":3`, 1)
+	if _, err := ParseTransactionJSON([]byte(data)); !errors.Is(err, ErrRawDataMismatch) {
+		t.Fatalf("expected ErrRawDataMismatch, got %v", err)
+	}
+}
+
+func TestTxJSONRenderer_Errors(t *testing.T) {
+	r := txJSONRenderer{}
+	if _, err := r.render(nil, "NoSuchMessage"); err == nil {
+		t.Fatalf("expected unknown schema error")
+	}
+	for _, b := range [][]byte{{0x0a}, {0x08, 0x01}, {0x5a, 0x02, 0x12, 0x00}, {0x5a, 0x04, 0x12, 0x02, 0x08, 0x01}, {0x5a, 0x04, 0x12, 0x02, 0x0a, 0x05}} {
+		if _, err := r.render(b, "Transaction.raw"); err == nil {
+			t.Fatalf("expected error rendering %x", b)
+		}
+	}
+	// A known type whose value is malformed, and a visible address of the
+	// wrong length.
+	bad := []byte{0x5a, 0x33, 0x12, 0x31, 0x0a, 0x2d}
+	bad = append(bad, "type.googleapis.com/protocol.TransferContract"...)
+	bad = append(bad, 0x12, 0x00)
+	if _, err := (txJSONRenderer{}).render(bad[:len(bad)-2], "Transaction.raw"); err == nil {
+		t.Fatalf("expected length error")
+	}
+	if _, err := (txJSONRenderer{visible: true}).render([]byte{0x0a, 0x01, 0x41}, "TransferContract"); err == nil {
+		t.Fatalf("expected invalid address error")
+	}
+	if _, err := (txJSONRenderer{}).render([]byte{0x0a, 0x02, 0x08, 0x01}, "TransferAssetContract"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := (txJSONRenderer{}).render([]byte{0x12, 0x01, 0x0a}, "VoteWitnessContract"); err == nil {
+		t.Fatalf("expected nested error")
+	}
+	if _, err := (txJSONRenderer{}).render([]byte{0x2a, 0x00}, "DelegateResourceContract"); err == nil {
+		t.Fatalf("expected bool wire type error")
+	}
+}
+
+// TestProtoJSONSchemas_CoverContracts fails when a contract type gains a
+// parser but no JSON schema, or its encoding gains a field the schema does
+// not describe, which the renderer would silently drop.
+func TestProtoJSONSchemas_CoverContracts(t *testing.T) {
+	owner, active := testPermissions(t)
+	witness := Permission{Type: WitnessPermission, ID: 1, Name: "witness", Threshold: 1, Keys: owner.Keys[:1]}
+	owner.Type, active.Type, active.ID = OwnerPermission, ActivePermission, 2
+	a, b := "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK"
+	samples := []ContractParameter{
+		&TransferContract{OwnerAddress: a, ToAddress: b, Amount: 1},
+		&TransferAssetContract{AssetName: "1002000", OwnerAddress: a, ToAddress: b, Amount: 1},
+		&VoteWitnessContract{OwnerAddress: a, Votes: []Vote{{Address: b, Count: 1}}, Support: true},
+		&WithdrawBalanceContract{OwnerAddress: a},
+		&CreateSmartContract{
+			OwnerAddress: a,
+			NewContract: SmartContract{
+				OriginAddress: a, ContractAddress: b, ABI: testABI, Bytecode: []byte{0x60}, CallValue: 1,
+				ConsumeUserResourcePercent: 1, Name: "n", OriginEnergyLimit: 1,
+			},
+			CallTokenValue: 1,
+			TokenID:        1,
+		},
+		&TriggerSmartContract{OwnerAddress: a, ContractAddress: b, CallValue: 1, Data: []byte{1}, CallTokenValue: 1, TokenID: 1},
+		&AccountPermissionUpdateContract{OwnerAddress: a, Owner: owner, Witness: &witness, Actives: []Permission{active}},
+		&FreezeBalanceV2Contract{OwnerAddress: a, FrozenBalance: 1_000_000, Resource: ResourceEnergy},
+		&UnfreezeBalanceV2Contract{OwnerAddress: a, UnfreezeBalance: 1_000_000, Resource: ResourceEnergy},
+		&WithdrawExpireUnfreezeContract{OwnerAddress: a},
+		&DelegateResourceContract{OwnerAddress: a, Resource: ResourceEnergy, Balance: 1_000_000, ReceiverAddress: b, Lock: true, LockPeriod: 1},
+		&UnDelegateResourceContract{OwnerAddress: a, Resource: ResourceEnergy, Balance: 1_000_000, ReceiverAddress: b},
+		&CancelAllUnfreezeV2Contract{OwnerAddress: a},
+	}
+	covered := map[ContractType]bool{}
+	for _, p := range samples {
+		b, err := p.marshalProto()
+		if err != nil {
+			t.Fatalf("%s: marshal error: %v", p.ContractType(), err)
+		}
+		checkSchemaCovers(t, b, p.ContractType().String())
+		covered[p.ContractType()] = true
+	}
+	for typ := range contractParsers {
+		if _, ok := protoJSONSchemas[typ.String()]; !ok {
+			t.Fatalf("%s has a parser but no JSON schema", typ)
+		}
+		if !covered[typ] {
+			t.Fatalf("%s has no sample in this test", typ)
+		}
+	}
+	for name, specs := range protoJSONSchemas {
+		for _, spec := range specs {
+			if _, ok := protoJSONSchemas[spec.message]; spec.kind == jsonMessage && !ok {
+				t.Fatalf("%s.%s refers to unknown schema %q", name, spec.name, spec.message)
+			}
+		}
+	}
+}
+
+// checkSchemaCovers fails if any field of the message b, at any depth, has
+// no entry in schema.
+func checkSchemaCovers(t *testing.T, b []byte, schema string) {
+	t.Helper()
+	fields, err := parseProto(b)
+	if err != nil {
+		t.Fatalf("%s: %v", schema, err)
+	}
+	for _, f := range fields {
+		var spec *protoJSONField
+		for i, s := range protoJSONSchemas[schema] {
+			if s.num == f.Num {
+				spec = &protoJSONSchemas[schema][i]
+			}
+		}
+		if spec == nil {
+			t.Fatalf("%s field %d is missing from the JSON schema", schema, f.Num)
+		}
+		if spec.kind == jsonMessage {
+			nested, _ := f.bytes()
+			checkSchemaCovers(t, nested, spec.message)
+		}
+	}
+}
@@ -0,0 +1,371 @@
+package tronwallet
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TypedDataField is a single member of a TIP-712 struct type.
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedData is a TIP-712 typed structured data document, the TRON variant of
+// EIP-712. It differs from EIP-712 in that address values are TRON
+// addresses (Base58 or 41-prefixed hex) and the trcToken type is supported;
+// both are encoded exactly like their EIP-712 counterparts (address and
+// uint256), so digests match EIP-712 for the same 20-byte accounts.
+type TypedData struct {
+	Types       map[string][]TypedDataField `json:"types"`
+	PrimaryType string                      `json:"primaryType"`
+	Domain      map[string]any              `json:"domain"`
+	Message     map[string]any              `json:"message"`
+}
+
+// typedDataDomainType is the name of the struct type describing the domain.
+const typedDataDomainType = "EIP712Domain"
+
+var (
+	typedDataArrayRe = regexp.MustCompile(`^(.+)\[(\d*)\]$`)
+	typedDataIntRe   = regexp.MustCompile(`^(u?)int(\d*)$`)
+	typedDataBytesRe = regexp.MustCompile(`^bytes(\d+)$`)
+)
+
+// ParseTypedData decodes a TIP-712 JSON document as produced for TronWeb's
+// _signTypedData. Numbers are kept exact.
+func ParseTypedData(data []byte) (*TypedData, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var td TypedData
+	if err := dec.Decode(&td); err != nil {
+		return nil, err
+	}
+	if td.PrimaryType == "" || td.Types[td.PrimaryType] == nil {
+		return nil, errors.New("typed data: unknown primary type")
+	}
+	if td.Domain == nil {
+		td.Domain = map[string]any{}
+	}
+	if td.Types[typedDataDomainType] == nil {
+		td.Types[typedDataDomainType] = inferDomainType(td.Domain)
+	}
+	return &td, nil
+}
+
+// inferDomainType builds the EIP712Domain type from the fields present in
+// the domain, in the canonical order, when the document omits it.
+func inferDomainType(domain map[string]any) []TypedDataField {
+	var fields []TypedDataField
+	for _, f := range []TypedDataField{
+		{"name", "string"},
+		{"version", "string"},
+		{"chainId", "uint256"},
+		{"verifyingContract", "address"},
+		{"salt", "bytes32"},
+	} {
+		if _, ok := domain[f.Name]; ok {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// EncodeType returns the canonical type string of a struct type: the type
+// itself followed by its referenced struct types in alphabetical order.
+func (td *TypedData) EncodeType(name string) string {
+	deps := map[string]bool{}
+	td.collectDeps(name, deps)
+	delete(deps, name)
+	others := make([]string, 0, len(deps))
+	for d := range deps {
+		others = append(others, d)
+	}
+	sort.Strings(others)
+
+	var sb strings.Builder
+	for _, t := range append([]string{name}, others...) {
+		sb.WriteString(t)
+		sb.WriteByte('(')
+		for i, f := range td.Types[t] {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(f.Type + " " + f.Name)
+		}
+		sb.WriteByte(')')
+	}
+	return sb.String()
+}
+
+func (td *TypedData) collectDeps(name string, deps map[string]bool) {
+	if deps[name] || td.Types[name] == nil {
+		return
+	}
+	deps[name] = true
+	for _, f := range td.Types[name] {
+		td.collectDeps(typedDataBaseType(f.Type), deps)
+	}
+}
+
+// typedDataBaseType strips array suffixes from a type name.
+func typedDataBaseType(t string) string {
+	for {
+		m := typedDataArrayRe.FindStringSubmatch(t)
+		if m == nil {
+			return t
+		}
+		t = m[1]
+	}
+}
+
+// TypeHash returns keccak256(EncodeType(name)).
+func (td *TypedData) TypeHash(name string) []byte {
+	return keccak256([]byte(td.EncodeType(name)))
+}
+
+// HashStruct returns the TIP-712 hashStruct of data interpreted as type name.
+func (td *TypedData) HashStruct(name string, data map[string]any) ([]byte, error) {
+	fields, ok := td.Types[name]
+	if !ok {
+		return nil, fmt.Errorf("typed data: unknown type %q", name)
+	}
+	enc := td.TypeHash(name)
+	for _, f := range fields {
+		v, ok := data[f.Name]
+		if !ok {
+			return nil, fmt.Errorf("typed data: missing field %q of %s", f.Name, name)
+		}
+		word, err := td.encodeValue(f.Type, v)
+		if err != nil {
+			return nil, fmt.Errorf("typed data: field %q of %s: %w", f.Name, name, err)
+		}
+		enc = append(enc, word...)
+	}
+	return keccak256(enc), nil
+}
+
+// DomainSeparator returns the hashStruct of the domain.
+func (td *TypedData) DomainSeparator() ([]byte, error) {
+	return td.HashStruct(typedDataDomainType, td.Domain)
+}
+
+// Hash returns the digest to sign: keccak256(0x1901 || domainSeparator ||
+// hashStruct(message)).
+func (td *TypedData) Hash() ([]byte, error) {
+	domain, err := td.DomainSeparator()
+	if err != nil {
+		return nil, err
+	}
+	msg, err := td.HashStruct(td.PrimaryType, td.Message)
+	if err != nil {
+		return nil, err
+	}
+	return keccak256([]byte{0x19, 0x01}, domain, msg), nil
+}
+
+// encodeValue returns the 32-byte encoding of a single value.
+func (td *TypedData) encodeValue(typ string, v any) ([]byte, error) {
+	if m := typedDataArrayRe.FindStringSubmatch(typ); m != nil {
+		items, ok := v.([]any)
+		if !ok {
+			return nil, fmt.Errorf("expected array for %s", typ)
+		}
+		if m[2] != "" {
+			if n, _ := strconv.Atoi(m[2]); n != len(items) {
+				return nil, fmt.Errorf("expected %d items for %s, got %d", n, typ, len(items))
+			}
+		}
+		var enc []byte
+		for _, item := range items {
+			word, err := td.encodeValue(m[1], item)
+			if err != nil {
+				return nil, err
+			}
+			enc = append(enc, word...)
+		}
+		return keccak256(enc), nil
+	}
+	if _, ok := td.Types[typ]; ok {
+		data, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("expected object for %s", typ)
+		}
+		return td.HashStruct(typ, data)
+	}
+
+	switch typ {
+	case "string":
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.New("expected string")
+		}
+		return keccak256([]byte(s)), nil
+	case "bytes":
+		b, err := typedDataBytes(v)
+		if err != nil {
+			return nil, err
+		}
+		return keccak256(b), nil
+	case "bool":
+		b, ok := v.(bool)
+		if !ok {
+			return nil, errors.New("expected bool")
+		}
+		word := make([]byte, 32)
+		if b {
+			word[31] = 1
+		}
+		return word, nil
+	case "address":
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.New("expected address string")
+		}
+		raw, err := typedDataAddress(s)
+		if err != nil {
+			return nil, err
+		}
+		word := make([]byte, 32)
+		copy(word[12:], raw)
+		return word, nil
+	case "trcToken":
+		return typedDataInt(v, 256, false)
+	}
+	if m := typedDataBytesRe.FindStringSubmatch(typ); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		b, err := typedDataBytes(v)
+		if err != nil {
+			return nil, err
+		}
+		if n < 1 || n > 32 || len(b) != n {
+			return nil, fmt.Errorf("expected %d bytes for %s", n, typ)
+		}
+		word := make([]byte, 32)
+		copy(word, b)
+		return word, nil
+	}
+	if m := typedDataIntRe.FindStringSubmatch(typ); m != nil {
+		bits := 256
+		if m[2] != "" {
+			bits, _ = strconv.Atoi(m[2])
+		}
+		if bits < 8 || bits > 256 || bits%8 != 0 {
+			return nil, fmt.Errorf("invalid integer type %s", typ)
+		}
+		return typedDataInt(v, bits, m[1] == "")
+	}
+	return nil, fmt.Errorf("unsupported type %s", typ)
+}
+
+// typedDataAddress accepts TRON Base58 or hex addresses, and 0x-prefixed
+// 20-byte EVM addresses, returning the 20-byte account hash.
+func typedDataAddress(s string) ([]byte, error) {
+	if h := strings.TrimPrefix(s, "0x"); len(h) == 40 {
+		return hex.DecodeString(h)
+	}
+	raw, err := DecodeAddress(s)
+	if err != nil {
+		return nil, err
+	}
+	return raw[1:], nil
+}
+
+func typedDataBytes(v any) ([]byte, error) {
+	s, ok := v.(string)
+	if !ok || !strings.HasPrefix(s, "0x") {
+		return nil, errors.New("expected 0x-prefixed hex bytes")
+	}
+	return hex.DecodeString(s[2:])
+}
+
+// typedDataInt encodes a JSON number, decimal string or 0x-prefixed hex
+// string as a 32-byte two's complement word, checking the value fits.
+func typedDataInt(v any, bits int, signed bool) ([]byte, error) {
+	var n *big.Int
+	var ok bool
+	switch x := v.(type) {
+	case json.Number:
+		n, ok = new(big.Int).SetString(x.String(), 10)
+	case string:
+		if strings.HasPrefix(x, "0x") {
+			n, ok = new(big.Int).SetString(x[2:], 16)
+		} else {
+			n, ok = new(big.Int).SetString(x, 10)
+		}
+	case float64:
+		if x == float64(int64(x)) {
+			n, ok = big.NewInt(int64(x)), true
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %v", v)
+	}
+	return encodeIntWord(n, bits, signed)
+}
+
+// encodeIntWord returns the 32-byte big-endian two's complement encoding of
+// n, checking it fits in an integer of the given size.
+func encodeIntWord(n *big.Int, bits int, signed bool) ([]byte, error) {
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	if signed {
+		half := new(big.Int).Rsh(limit, 1)
+		if n.Cmp(half) >= 0 || n.Cmp(new(big.Int).Neg(half)) < 0 {
+			return nil, fmt.Errorf("value %s out of range for int%d", n, bits)
+		}
+	} else if n.Sign() < 0 || n.Cmp(limit) >= 0 {
+		return nil, fmt.Errorf("value %s out of range for uint%d", n, bits)
+	}
+	word := make([]byte, 32)
+	if n.Sign() < 0 {
+		n = new(big.Int).Add(n, new(big.Int).Lsh(big.NewInt(1), 256))
+	}
+	n.FillBytes(word)
+	return word, nil
+}
+
+// SignTypedData signs the TIP-712 digest of td and returns the 65-byte
+// r || s || v signature, as TronWeb's _signTypedData does.
+func SignTypedData(priv *ecdsa.PrivateKey, td *TypedData) ([]byte, error) {
+	hash, err := td.Hash()
+	if err != nil {
+		return nil, err
+	}
+	return SignHash(priv, hash)
+}
+
+// SignTypedDataWith is SignTypedData for any Signer.
+func SignTypedDataWith(s Signer, td *TypedData) ([]byte, error) {
+	hash, err := td.Hash()
+	if err != nil {
+		return nil, err
+	}
+	return s.SignHash(hash)
+}
+
+// RecoverTypedDataAddress returns the address that signed td.
+func RecoverTypedDataAddress(td *TypedData, sig []byte) (string, error) {
+	hash, err := td.Hash()
+	if err != nil {
+		return "", err
+	}
+	return RecoverAddress(hash, sig)
+}
+
+// VerifyTypedData checks that sig is a signature of td by address.
+func VerifyTypedData(td *TypedData, sig []byte, address string) error {
+	hash, err := td.Hash()
+	if err != nil {
+		return err
+	}
+	return VerifySignature(hash, sig, address)
+}
@@ -0,0 +1,320 @@
+package tronwallet
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// tronAddr converts a 20-byte EVM address to its TRON Base58 form.
+func tronAddr(t *testing.T, evm string) string {
+	t.Helper()
+	raw, err := hex.DecodeString(strings.TrimPrefix(evm, "0x"))
+	if err != nil {
+		t.Fatalf("bad hex address: %v", err)
+	}
+	addr, err := EncodeAddress(append([]byte{0x41}, raw...))
+	if err != nil {
+		t.Fatalf("EncodeAddress error: %v", err)
+	}
+	return addr
+}
+
+// mailTypedData is the EIP-712 reference "Ether Mail" example with every
+// address expressed as a TRON address. TIP-712 encodes TRON addresses as
+// their 20-byte account hash, so the reference hashes still apply.
+func mailTypedData(t *testing.T) *TypedData {
+	t.Helper()
+	doc := `{
+	  "types": {
+	    "EIP712Domain": [
+	      {"name": "name", "type": "string"},
+	      {"name": "version", "type": "string"},
+	      {"name": "chainId", "type": "uint256"},
+	      {"name": "verifyingContract", "type": "address"}
+	    ],
+	    "Person": [
+	      {"name": "name", "type": "string"},
+	      {"name": "wallet", "type": "address"}
+	    ],
+	    "Mail": [
+	      {"name": "from", "type": "Person"},
+	      {"name": "to", "type": "Person"},
+	      {"name": "contents", "type": "string"}
+	    ]
+	  },
+	  "primaryType": "Mail",
+	  "domain": {
+	    "name": "Ether Mail",
+	    "version": "1",
+	    "chainId": 1,
+	    "verifyingContract": "` + tronAddr(t, "CcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC") + `"
+	  },
+	  "message": {
+	    "from": {"name": "Cow", "wallet": "` + tronAddr(t, "CD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826") + `"},
+	    "to": {"name": "Bob", "wallet": "41bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"},
+	    "contents": "Hello, Bob!"
+	  }
+	}`
+	td, err := ParseTypedData([]byte(doc))
+	if err != nil {
+		t.Fatalf("ParseTypedData error: %v", err)
+	}
+	return td
+}
+
+func TestTypedData_ReferenceVector(t *testing.T) {
+	td := mailTypedData(t)
+
+	if got := td.EncodeType("Mail"); got != "Mail(Person from,Person to,string contents)Person(string name,address wallet)" {
+		t.Fatalf("unexpected encodeType %s", got)
+	}
+	if got := hex.EncodeToString(td.TypeHash("Mail")); got != "a0cedeb2dc280ba39b857546d74f5549c3a1d7bdc2dd96bf881f76108e23dac2" {
+		t.Fatalf("unexpected typeHash %s", got)
+	}
+	domain, err := td.DomainSeparator()
+	if err != nil {
+		t.Fatalf("DomainSeparator error: %v", err)
+	}
+	if got := hex.EncodeToString(domain); got != "f2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f" {
+		t.Fatalf("unexpected domain separator %s", got)
+	}
+	msg, err := td.HashStruct("Mail", td.Message)
+	if err != nil {
+		t.Fatalf("HashStruct error: %v", err)
+	}
+	if got := hex.EncodeToString(msg); got != "c52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e" {
+		t.Fatalf("unexpected message hash %s", got)
+	}
+	digest, err := td.Hash()
+	if err != nil {
+		t.Fatalf("Hash error: %v", err)
+	}
+	if got := hex.EncodeToString(digest); got != "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2" {
+		t.Fatalf("unexpected digest %s", got)
+	}
+
+	// the reference signer's key is keccak256("cow")
+	priv := secp256k1.PrivKeyFromBytes(keccak256([]byte("cow"))).ToECDSA()
+	sig, err := SignTypedData(priv, td)
+	if err != nil {
+		t.Fatalf("SignTypedData error: %v", err)
+	}
+	want := "4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d" +
+		"07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b91562" + "1c"
+	if hex.EncodeToString(sig) != want {
+		t.Fatalf("unexpected signature %x", sig)
+	}
+	cow := tronAddr(t, "CD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")
+	if got, err := RecoverTypedDataAddress(td, sig); err != nil || got != cow {
+		t.Fatalf("RecoverTypedDataAddress = %s, %v", got, err)
+	}
+	if err := VerifyTypedData(td, sig, cow); err != nil {
+		t.Fatalf("VerifyTypedData error: %v", err)
+	}
+}
+
+// TestTypedData_TronVector checks a fixed digest and signature for a domain
+// and message using TRON addresses and trcToken. They were computed outside
+// this package with an independent implementation of TronWeb's
+// _signTypedData, which encodes trcToken as uint256 under its own name. The
+// same document signed by TronWeb itself is checked by
+// TestTypedData_TronWebVectors.
+func TestTypedData_TronVector(t *testing.T) {
+	doc := `{
+	  "types": {
+	    "EIP712Domain": [
+	      {"name": "name", "type": "string"},
+	      {"name": "version", "type": "string"},
+	      {"name": "chainId", "type": "uint256"},
+	      {"name": "verifyingContract", "type": "address"}
+	    ],
+	    "TokenTransfer": [
+	      {"name": "from", "type": "address"},
+	      {"name": "to", "type": "address"},
+	      {"name": "tokenId", "type": "trcToken"},
+	      {"name": "amount", "type": "uint256"}
+	    ]
+	  },
+	  "primaryType": "TokenTransfer",
+	  "domain": {
+	    "name": "TRC Gate",
+	    "version": "1",
+	    "chainId": 728126428,
+	    "verifyingContract": "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t"
+	  },
+	  "message": {
+	    "from": "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH",
+	    "to": "41b6e708a39781c96bd399c7657780ff9fe9f052a8",
+	    "tokenId": "1002000",
+	    "amount": 1500000
+	  }
+	}`
+	td, err := ParseTypedData([]byte(doc))
+	if err != nil {
+		t.Fatalf("ParseTypedData error: %v", err)
+	}
+	if got := hex.EncodeToString(td.TypeHash("TokenTransfer")); got != "0f18bf9d93891d71255c190969d7f1c6903664a6b96ca5c1a149ab0f3f507b2b" {
+		t.Fatalf("unexpected typeHash %s", got)
+	}
+	domain, _ := td.DomainSeparator()
+	if got := hex.EncodeToString(domain); got != "3332df178df2cd5eb38addae86995ba2a614ff9af672f0c4fd21f4e9afc7a9cf" {
+		t.Fatalf("unexpected domain separator %s", got)
+	}
+	digest, err := td.Hash()
+	if err != nil {
+		t.Fatalf("Hash error: %v", err)
+	}
+	if got := hex.EncodeToString(digest); got != "42206811d22419abee70c51db236e19b22ad56f6663c097eb26680f29dfea9f0" {
+		t.Fatalf("unexpected digest %s", got)
+	}
+
+	priv := secp256k1.PrivKeyFromBytes(keccak256([]byte("cow"))).ToECDSA()
+	sig, err := SignTypedData(priv, td)
+	if err != nil {
+		t.Fatalf("SignTypedData error: %v", err)
+	}
+	want := "7228b8b0fa0cb1a3e46503821ebb2f9fc0c4196855d6b3cb4adc8e5765a6dd2b" +
+		"5204d00600094de31a4c48f155e9527822872e1654230ecf22fffa52dc912a63" + "1c"
+	if hex.EncodeToString(sig) != want {
+		t.Fatalf("unexpected signature %x", sig)
+	}
+	if err := VerifyTypedData(td, sig, tronAddr(t, "CD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")); err != nil {
+		t.Fatalf("VerifyTypedData error: %v", err)
+	}
+}
+
+func TestTypedData_TronWebVectors(t *testing.T) {
+	v, priv := loadTronWebVectors(t)
+	for _, c := range v.TypedData {
+		td, err := ParseTypedData(c.Document)
+		if err != nil {
+			t.Fatalf("ParseTypedData error: %v", err)
+		}
+		digest, err := td.Hash()
+		if err != nil {
+			t.Fatalf("Hash error: %v", err)
+		}
+		if got := "0x" + hex.EncodeToString(digest); !strings.EqualFold(got, c.Digest) {
+			t.Fatalf("tronweb %s digest %s, got %s", v.TronWeb, c.Digest, got)
+		}
+		sig, err := SignTypedData(priv, td)
+		if err != nil {
+			t.Fatalf("SignTypedData error: %v", err)
+		}
+		if got := "0x" + hex.EncodeToString(sig); !strings.EqualFold(got, c.Signature) {
+			t.Fatalf("tronweb %s _signTypedData %s, got %s", v.TronWeb, c.Signature, got)
+		}
+	}
+}
+
+func TestTypedData_TronTypesAndArrays(t *testing.T) {
+	doc := `{
+	  "types": {
+	    "Order": [
+	      {"name": "maker", "type": "address"},
+	      {"name": "token", "type": "trcToken"},
+	      {"name": "amounts", "type": "uint64[2]"},
+	      {"name": "delta", "type": "int8"},
+	      {"name": "tags", "type": "string[]"},
+	      {"name": "salt", "type": "bytes32"},
+	      {"name": "memo", "type": "bytes"},
+	      {"name": "active", "type": "bool"}
+	    ]
+	  },
+	  "primaryType": "Order",
+	  "domain": {"name": "DEX", "chainId": "0x2b6653dc"},
+	  "message": {
+	    "maker": "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH",
+	    "token": "1002000",
+	    "amounts": [1, "18446744073709551615"],
+	    "delta": -5,
+	    "tags": ["a", "b"],
+	    "salt": "0x` + strings.Repeat("ab", 32) + `",
+	    "memo": "0xdeadbeef",
+	    "active": true
+	  }
+	}`
+	td, err := ParseTypedData([]byte(doc))
+	if err != nil {
+		t.Fatalf("ParseTypedData error: %v", err)
+	}
+	if got := td.EncodeType(typedDataDomainType); got != "EIP712Domain(string name,uint256 chainId)" {
+		t.Fatalf("unexpected inferred domain type %s", got)
+	}
+
+	w, _ := RestoreWallet(testMnemonic)
+	priv, _ := w.Derive(0)
+	sig, err := SignTypedData(priv, td)
+	if err != nil {
+		t.Fatalf("SignTypedData error: %v", err)
+	}
+	if err := VerifyTypedData(td, sig, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH"); err != nil {
+		t.Fatalf("VerifyTypedData error: %v", err)
+	}
+
+	td.Message["delta"] = "-129"
+	if _, err := td.Hash(); err == nil {
+		t.Fatalf("expected range error for int8")
+	}
+	td.Message["delta"] = "-1"
+	td.Message["amounts"] = []any{"1"}
+	if _, err := td.Hash(); err == nil {
+		t.Fatalf("expected length error for fixed array")
+	}
+}
+
+func TestTypedData_Errors(t *testing.T) {
+	if _, err := ParseTypedData([]byte(`{`)); err == nil {
+		t.Fatalf("expected JSON error")
+	}
+	if _, err := ParseTypedData([]byte(`{"types":{},"primaryType":"X"}`)); err == nil {
+		t.Fatalf("expected unknown primary type error")
+	}
+
+	td := mailTypedData(t)
+	bad := map[string]map[string]any{
+		"missing field":   map[string]any{"from": td.Message["from"], "to": td.Message["to"]},
+		"wrong type":      map[string]any{"from": "x", "to": td.Message["to"], "contents": "c"},
+		"invalid address": map[string]any{"from": map[string]any{"name": "a", "wallet": "Tnope"}, "to": td.Message["to"], "contents": "c"},
+		"non-string":      map[string]any{"from": td.Message["from"], "to": td.Message["to"], "contents": 1.0},
+	}
+	for name, msg := range bad {
+		td.Message = msg
+		if _, err := td.Hash(); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+
+	for typ, v := range map[string]any{
+		"uint7":   "1",
+		"uint256": "-1",
+		"bytes4":  "0x0102",
+		"bytes":   "0102",
+		"bool":    "true",
+		"float":   1.0,
+		"uint8":   1.5,
+		"int[]":   "x",
+	} {
+		if _, err := td.encodeValue(typ, v); err == nil {
+			t.Fatalf("expected error encoding %v as %s", v, typ)
+		}
+	}
+	if _, err := td.HashStruct("Unknown", nil); err == nil {
+		t.Fatalf("expected unknown type error")
+	}
+	if _, err := SignTypedData(nil, td); err == nil {
+		t.Fatalf("expected error for invalid message")
+	}
+	if _, err := SignTypedData(nil, mailTypedData(t)); err == nil {
+		t.Fatalf("expected error for nil key")
+	}
+	if _, err := RecoverTypedDataAddress(td, nil); err == nil {
+		t.Fatalf("expected error for invalid message")
+	}
+	if err := VerifyTypedData(td, nil, ""); err == nil {
+		t.Fatalf("expected error for invalid message")
+	}
+}
@@ -0,0 +1,368 @@
+package tronwallet
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// VaultVersion is the newest vault format version written by Seal.
+const VaultVersion = 1
+
+const (
+	vaultMagic     = "TRWV"
+	vaultSaltSize  = 16
+	vaultKeySize   = chacha20poly1305.KeySize
+	vaultNonceSize = chacha20poly1305.NonceSizeX
+	// magic(4) || version(1) || time(4) || memory(4) || threads(1) || salt || nonce
+	vaultHeaderSize = 4 + 1 + 4 + 4 + 1 + vaultSaltSize + vaultNonceSize
+
+	// upper bounds accepted when opening a vault, so a crafted header cannot
+	// make the KDF consume unbounded time or memory.
+	vaultMaxTime    = 64
+	vaultMaxMemory  = 1024 * 1024 // KiB (1 GiB)
+	vaultMaxThreads = 16
+)
+
+var (
+	// ErrVaultAuth is returned when a vault cannot be decrypted, either because
+	// the password is wrong or because the data was modified.
+	ErrVaultAuth = errors.New("vault: wrong password or tampered data")
+	// ErrVaultFormat is returned when the data is not a vault or is truncated.
+	ErrVaultFormat = errors.New("vault: invalid format")
+	// ErrVaultVersion is returned when the vault was written by an unknown
+	// format version.
+	ErrVaultVersion = errors.New("vault: unsupported version")
+	// ErrVaultEntryExists is returned when an entry name is already in use.
+	ErrVaultEntryExists = errors.New("vault: entry already exists")
+	// ErrVaultEntryNotFound is returned when no entry has the requested name.
+	ErrVaultEntryNotFound = errors.New("vault: entry not found")
+
+	errVaultMnemonic = errors.New("vault: wallet has an invalid mnemonic")
+)
+
+// VaultKDFParams holds the Argon2id parameters used to derive the vault key
+// from the password. Memory is expressed in KiB.
+type VaultKDFParams struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+}
+
+// DefaultVaultKDFParams are the Argon2id parameters used by Seal.
+var DefaultVaultKDFParams = VaultKDFParams{Time: 3, Memory: 64 * 1024, Threads: 4}
+
+// valid reports whether p is within the bounds OpenVault accepts.
+func (p VaultKDFParams) valid() bool {
+	return p.Time > 0 && p.Time <= vaultMaxTime &&
+		p.Memory > 0 && p.Memory <= vaultMaxMemory &&
+		p.Threads > 0 && p.Threads <= vaultMaxThreads
+}
+
+// allow tests to use cheaper KDF parameters
+var vaultKDFParamsImpl = func() VaultKDFParams { return DefaultVaultKDFParams }
+
+// VaultEntry is a single named secret stored in a Vault. Exactly one of
+// Wallet or PrivateKey is set: Wallet for mnemonic-based entries and
+// PrivateKey for imported keys.
+type VaultEntry struct {
+	Name       string
+	Wallet     *TronWallet
+	PrivateKey *ecdsa.PrivateKey
+	Metadata   map[string]string
+	CreatedAt  time.Time
+}
+
+// Vault is an in-memory collection of named wallets and imported keys that
+// can be sealed into a password-protected blob with Seal and restored with
+// OpenVault.
+type Vault struct {
+	// Metadata holds free-form vault-level information.
+	Metadata map[string]string
+	entries  []*VaultEntry
+}
+
+// NewVault returns an empty vault.
+func NewVault() *Vault {
+	return &Vault{Metadata: map[string]string{}}
+}
+
+// AddWallet stores w under name. Only the mnemonic is persisted; the seed is
+// derived from it again when the vault is opened.
+func (v *Vault) AddWallet(name string, w *TronWallet, metadata map[string]string) error {
+	if w == nil {
+		return errors.New("vault: nil wallet")
+	}
+	if !bip39.IsMnemonicValid(w.Mnemonic) {
+		return errVaultMnemonic
+	}
+	return v.add(&VaultEntry{Name: name, Wallet: w, Metadata: metadata})
+}
+
+// AddPrivateKey stores an imported private key under name.
+func (v *Vault) AddPrivateKey(name string, priv *ecdsa.PrivateKey, metadata map[string]string) error {
+	if priv == nil {
+		return errors.New("vault: nil private key")
+	}
+	return v.add(&VaultEntry{Name: name, PrivateKey: priv, Metadata: metadata})
+}
+
+func (v *Vault) add(e *VaultEntry) error {
+	if e.Name == "" {
+		return errors.New("vault: empty entry name")
+	}
+	if v.index(e.Name) >= 0 {
+		return ErrVaultEntryExists
+	}
+	if e.Metadata == nil {
+		e.Metadata = map[string]string{}
+	}
+	e.CreatedAt = time.Now().UTC()
+	v.entries = append(v.entries, e)
+	return nil
+}
+
+// Entry returns the entry stored under name.
+func (v *Vault) Entry(name string) (*VaultEntry, error) {
+	i := v.index(name)
+	if i < 0 {
+		return nil, ErrVaultEntryNotFound
+	}
+	return v.entries[i], nil
+}
+
+// Names returns the entry names in insertion order.
+func (v *Vault) Names() []string {
+	names := make([]string, len(v.entries))
+	for i, e := range v.entries {
+		names[i] = e.Name
+	}
+	return names
+}
+
+// Remove deletes the entry stored under name.
+func (v *Vault) Remove(name string) error {
+	i := v.index(name)
+	if i < 0 {
+		return ErrVaultEntryNotFound
+	}
+	v.entries = append(v.entries[:i], v.entries[i+1:]...)
+	return nil
+}
+
+// Rename changes the name of an entry.
+func (v *Vault) Rename(oldName, newName string) error {
+	if newName == "" {
+		return errors.New("vault: empty entry name")
+	}
+	i := v.index(oldName)
+	if i < 0 {
+		return ErrVaultEntryNotFound
+	}
+	if oldName != newName && v.index(newName) >= 0 {
+		return ErrVaultEntryExists
+	}
+	v.entries[i].Name = newName
+	return nil
+}
+
+func (v *Vault) index(name string) int {
+	for i, e := range v.entries {
+		if e.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// vaultPayload is the plaintext JSON document encrypted inside a vault.
+type vaultPayload struct {
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Entries  []vaultRecord     `json:"entries"`
+}
+
+type vaultRecord struct {
+	Name       string            `json:"name"`
+	Mnemonic   string            `json:"mnemonic,omitempty"`
+	PrivateKey []byte            `json:"private_key,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// Seal encrypts the vault with a key derived from password using Argon2id
+// and returns the versioned, authenticated vault blob. The header (including
+// the KDF parameters and salt) is authenticated as additional data, so any
+// modification is detected by OpenVault.
+func (v *Vault) Seal(password []byte) ([]byte, error) {
+	p := vaultPayload{Metadata: v.Metadata, Entries: make([]vaultRecord, 0, len(v.entries))}
+	for _, e := range v.entries {
+		r := vaultRecord{Name: e.Name, Metadata: e.Metadata, CreatedAt: e.CreatedAt}
+		switch {
+		case e.Wallet != nil:
+			if !bip39.IsMnemonicValid(e.Wallet.Mnemonic) {
+				return nil, fmt.Errorf("vault: entry %q: %w", e.Name, errVaultMnemonic)
+			}
+			r.Mnemonic = e.Wallet.Mnemonic
+		case e.PrivateKey != nil:
+			r.PrivateKey = PrivateKeyToBytes(e.PrivateKey)
+		default:
+			return nil, fmt.Errorf("vault: entry %q has neither a wallet nor a private key", e.Name)
+		}
+		p.Entries = append(p.Entries, r)
+	}
+	plaintext, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	params := vaultKDFParamsImpl()
+	if !params.valid() {
+		return nil, errors.New("vault: KDF parameters out of range")
+	}
+	header := make([]byte, vaultHeaderSize)
+	copy(header, vaultMagic)
+	header[4] = VaultVersion
+	binary.BigEndian.PutUint32(header[5:], params.Time)
+	binary.BigEndian.PutUint32(header[9:], params.Memory)
+	header[13] = params.Threads
+	salt := header[14 : 14+vaultSaltSize]
+	nonce := header[14+vaultSaltSize:]
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.NewX(vaultKey(password, salt, params))
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(header, nonce, plaintext, header), nil
+}
+
+// OpenVault decrypts a vault blob produced by Seal.
+func OpenVault(data, password []byte) (*Vault, error) {
+	if len(data) < 5 || !bytes.Equal(data[:4], []byte(vaultMagic)) {
+		return nil, ErrVaultFormat
+	}
+	if data[4] != VaultVersion {
+		return nil, fmt.Errorf("%w: %d", ErrVaultVersion, data[4])
+	}
+	if len(data) < vaultHeaderSize+chacha20poly1305.Overhead {
+		return nil, ErrVaultFormat
+	}
+
+	header := data[:vaultHeaderSize]
+	params := VaultKDFParams{
+		Time:    binary.BigEndian.Uint32(header[5:]),
+		Memory:  binary.BigEndian.Uint32(header[9:]),
+		Threads: header[13],
+	}
+	if !params.valid() {
+		return nil, ErrVaultFormat
+	}
+	salt := header[14 : 14+vaultSaltSize]
+	nonce := header[14+vaultSaltSize:]
+
+	aead, err := chacha20poly1305.NewX(vaultKey(password, salt, params))
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, nonce, data[vaultHeaderSize:], header)
+	if err != nil {
+		return nil, ErrVaultAuth
+	}
+
+	var p vaultPayload
+	if err := json.Unmarshal(plaintext, &p); err != nil {
+		return nil, ErrVaultFormat
+	}
+	v := NewVault()
+	if p.Metadata != nil {
+		v.Metadata = p.Metadata
+	}
+	for _, r := range p.Entries {
+		e := &VaultEntry{Name: r.Name, Metadata: r.Metadata, CreatedAt: r.CreatedAt}
+		if e.Metadata == nil {
+			e.Metadata = map[string]string{}
+		}
+		switch {
+		case r.Mnemonic != "":
+			if !bip39.IsMnemonicValid(r.Mnemonic) {
+				return nil, ErrVaultFormat
+			}
+			e.Wallet = &TronWallet{Mnemonic: r.Mnemonic, Seed: bip39.NewSeed(r.Mnemonic, "")}
+		case len(r.PrivateKey) == 32:
+			e.PrivateKey = secp256k1.PrivKeyFromBytes(r.PrivateKey).ToECDSA()
+		default:
+			return nil, ErrVaultFormat
+		}
+		v.entries = append(v.entries, e)
+	}
+	return v, nil
+}
+
+// ChangeVaultPassword re-encrypts a sealed vault under newPassword, using a
+// fresh salt and nonce. The entries are preserved unchanged.
+func ChangeVaultPassword(data, oldPassword, newPassword []byte) ([]byte, error) {
+	v, err := OpenVault(data, oldPassword)
+	if err != nil {
+		return nil, err
+	}
+	return v.Seal(newPassword)
+}
+
+// SaveFile seals the vault and writes it to path with 0600 permissions. The
+// file is replaced atomically so an interrupted write never leaves a
+// truncated vault behind.
+func (v *Vault) SaveFile(path string, password []byte) error {
+	data, err := v.Seal(password)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".vault-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// OpenVaultFile reads and decrypts the vault stored at path.
+func OpenVaultFile(path string, password []byte) (*Vault, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return OpenVault(data, password)
+}
+
+// vaultKey derives the XChaCha20-Poly1305 key from password with Argon2id.
+func vaultKey(password, salt []byte, p VaultKDFParams) []byte {
+	return argon2.IDKey(password, salt, p.Time, p.Memory, p.Threads, vaultKeySize)
+}
@@ -0,0 +1,253 @@
+package tronwallet
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// useFastVaultKDF swaps in cheap Argon2id parameters for the duration of a test.
+func useFastVaultKDF(t *testing.T) {
+	t.Helper()
+	orig := vaultKDFParamsImpl
+	vaultKDFParamsImpl = func() VaultKDFParams { return VaultKDFParams{Time: 1, Memory: 64, Threads: 1} }
+	t.Cleanup(func() { vaultKDFParamsImpl = orig })
+}
+
+func newTestVault(t *testing.T) *Vault {
+	t.Helper()
+	w, err := RestoreWallet(testMnemonic)
+	if err != nil {
+		t.Fatalf("RestoreWallet error: %v", err)
+	}
+	priv, err := w.Derive(1)
+	if err != nil {
+		t.Fatalf("Derive error: %v", err)
+	}
+	v := NewVault()
+	v.Metadata["owner"] = "ops"
+	if err := v.AddWallet("main", w, map[string]string{"note": "hot"}); err != nil {
+		t.Fatalf("AddWallet error: %v", err)
+	}
+	if err := v.AddPrivateKey("imported", priv, nil); err != nil {
+		t.Fatalf("AddPrivateKey error: %v", err)
+	}
+	return v
+}
+
+func TestVault_SealOpenRoundTrip(t *testing.T) {
+	useFastVaultKDF(t)
+	v := newTestVault(t)
+
+	data, err := v.Seal([]byte("pw"))
+	if err != nil {
+		t.Fatalf("Seal error: %v", err)
+	}
+	got, err := OpenVault(data, []byte("pw"))
+	if err != nil {
+		t.Fatalf("OpenVault error: %v", err)
+	}
+	if got.Metadata["owner"] != "ops" {
+		t.Fatalf("vault metadata not preserved")
+	}
+	names := got.Names()
+	if len(names) != 2 || names[0] != "main" || names[1] != "imported" {
+		t.Fatalf("unexpected names %v", names)
+	}
+	main, _ := got.Entry("main")
+	if main.Wallet == nil || main.Wallet.Mnemonic != testMnemonic || main.Metadata["note"] != "hot" {
+		t.Fatalf("wallet entry not preserved")
+	}
+	want, _ := v.Entry("main")
+	if !bytes.Equal(main.Wallet.Seed, want.Wallet.Seed) {
+		t.Fatalf("seed not rederived from the mnemonic")
+	}
+	imported, _ := got.Entry("imported")
+	orig, _ := v.Entry("imported")
+	if imported.PrivateKey == nil || PrivateKeyToHex(imported.PrivateKey) != PrivateKeyToHex(orig.PrivateKey) {
+		t.Fatalf("private key entry not preserved")
+	}
+}
+
+func TestVault_WrongPasswordAndTampering(t *testing.T) {
+	useFastVaultKDF(t)
+	data, err := newTestVault(t).Seal([]byte("pw"))
+	if err != nil {
+		t.Fatalf("Seal error: %v", err)
+	}
+	if _, err := OpenVault(data, []byte("nope")); !errors.Is(err, ErrVaultAuth) {
+		t.Fatalf("expected ErrVaultAuth for wrong password, got %v", err)
+	}
+
+	// flipping a byte in the ciphertext or in the authenticated header must fail
+	for _, i := range []int{len(data) - 1, 20} {
+		bad := append([]byte(nil), data...)
+		bad[i] ^= 0x01
+		if _, err := OpenVault(bad, []byte("pw")); !errors.Is(err, ErrVaultAuth) {
+			t.Fatalf("expected ErrVaultAuth for tampered byte %d, got %v", i, err)
+		}
+	}
+}
+
+func TestVault_FormatErrors(t *testing.T) {
+	useFastVaultKDF(t)
+	data, err := NewVault().Seal([]byte("pw"))
+	if err != nil {
+		t.Fatalf("Seal error: %v", err)
+	}
+
+	if _, err := OpenVault([]byte("nope"), nil); !errors.Is(err, ErrVaultFormat) {
+		t.Fatalf("expected ErrVaultFormat, got %v", err)
+	}
+	if _, err := OpenVault(data[:vaultHeaderSize], []byte("pw")); !errors.Is(err, ErrVaultFormat) {
+		t.Fatalf("expected ErrVaultFormat for truncated data, got %v", err)
+	}
+
+	future := append([]byte(nil), data...)
+	future[4] = VaultVersion + 1
+	if _, err := OpenVault(future, []byte("pw")); !errors.Is(err, ErrVaultVersion) {
+		t.Fatalf("expected ErrVaultVersion, got %v", err)
+	}
+
+	huge := append([]byte(nil), data...)
+	huge[9] = 0xff // memory parameter far above the accepted bound
+	if _, err := OpenVault(huge, []byte("pw")); !errors.Is(err, ErrVaultFormat) {
+		t.Fatalf("expected ErrVaultFormat for oversized KDF params, got %v", err)
+	}
+	threads := append([]byte(nil), data...)
+	threads[13] = vaultMaxThreads + 1
+	if _, err := OpenVault(threads, []byte("pw")); !errors.Is(err, ErrVaultFormat) {
+		t.Fatalf("expected ErrVaultFormat for too many threads, got %v", err)
+	}
+}
+
+func TestVault_SealRejects(t *testing.T) {
+	useFastVaultKDF(t)
+	v := newTestVault(t)
+	e, _ := v.Entry("imported")
+	e.PrivateKey = nil
+	if _, err := v.Seal([]byte("pw")); err == nil {
+		t.Fatalf("expected error for an entry without a wallet or key")
+	}
+
+	v = newTestVault(t)
+	e, _ = v.Entry("main")
+	e.Wallet.Mnemonic = ""
+	if _, err := v.Seal([]byte("pw")); !errors.Is(err, errVaultMnemonic) {
+		t.Fatalf("expected errVaultMnemonic for a wallet without a mnemonic, got %v", err)
+	}
+
+	vaultKDFParamsImpl = func() VaultKDFParams { return VaultKDFParams{Time: 1, Memory: vaultMaxMemory + 1, Threads: 1} }
+	if _, err := NewVault().Seal([]byte("pw")); err == nil {
+		t.Fatalf("expected error for KDF parameters OpenVault would reject")
+	}
+}
+
+func TestVault_AddRemoveRename(t *testing.T) {
+	v := newTestVault(t)
+	w, _ := RestoreWallet(testMnemonic)
+
+	if err := v.AddWallet("main", w, nil); !errors.Is(err, ErrVaultEntryExists) {
+		t.Fatalf("expected ErrVaultEntryExists, got %v", err)
+	}
+	if err := v.AddWallet("", w, nil); err == nil {
+		t.Fatalf("expected error for empty name")
+	}
+	if err := v.AddWallet("nil", nil, nil); err == nil {
+		t.Fatalf("expected error for nil wallet")
+	}
+	if err := v.AddWallet("seedless", &TronWallet{Seed: w.Seed}, nil); !errors.Is(err, errVaultMnemonic) {
+		t.Fatalf("expected errVaultMnemonic for a wallet without a mnemonic, got %v", err)
+	}
+	if err := v.AddPrivateKey("nil", nil, nil); err == nil {
+		t.Fatalf("expected error for nil key")
+	}
+
+	if err := v.Rename("main", "imported"); !errors.Is(err, ErrVaultEntryExists) {
+		t.Fatalf("expected ErrVaultEntryExists on rename collision, got %v", err)
+	}
+	if err := v.Rename("missing", "x"); !errors.Is(err, ErrVaultEntryNotFound) {
+		t.Fatalf("expected ErrVaultEntryNotFound, got %v", err)
+	}
+	if err := v.Rename("main", ""); err == nil {
+		t.Fatalf("expected error for empty new name")
+	}
+	if err := v.Rename("main", "cold"); err != nil {
+		t.Fatalf("Rename error: %v", err)
+	}
+	if _, err := v.Entry("cold"); err != nil {
+		t.Fatalf("renamed entry missing: %v", err)
+	}
+
+	if err := v.Remove("cold"); err != nil {
+		t.Fatalf("Remove error: %v", err)
+	}
+	if err := v.Remove("cold"); !errors.Is(err, ErrVaultEntryNotFound) {
+		t.Fatalf("expected ErrVaultEntryNotFound, got %v", err)
+	}
+	if _, err := v.Entry("cold"); !errors.Is(err, ErrVaultEntryNotFound) {
+		t.Fatalf("expected ErrVaultEntryNotFound, got %v", err)
+	}
+	if len(v.Names()) != 1 {
+		t.Fatalf("expected one entry left, got %v", v.Names())
+	}
+}
+
+func TestChangeVaultPassword(t *testing.T) {
+	useFastVaultKDF(t)
+	data, err := newTestVault(t).Seal([]byte("old"))
+	if err != nil {
+		t.Fatalf("Seal error: %v", err)
+	}
+	if _, err := ChangeVaultPassword(data, []byte("wrong"), []byte("new")); !errors.Is(err, ErrVaultAuth) {
+		t.Fatalf("expected ErrVaultAuth, got %v", err)
+	}
+	rotated, err := ChangeVaultPassword(data, []byte("old"), []byte("new"))
+	if err != nil {
+		t.Fatalf("ChangeVaultPassword error: %v", err)
+	}
+	if _, err := OpenVault(rotated, []byte("old")); !errors.Is(err, ErrVaultAuth) {
+		t.Fatalf("old password should no longer open the vault")
+	}
+	v, err := OpenVault(rotated, []byte("new"))
+	if err != nil {
+		t.Fatalf("OpenVault error: %v", err)
+	}
+	if len(v.Names()) != 2 {
+		t.Fatalf("entries lost during rotation: %v", v.Names())
+	}
+	main, _ := v.Entry("main")
+	if _, err := main.Wallet.Derive(0); err != nil || main.Wallet.Mnemonic != testMnemonic {
+		t.Fatalf("wallet unusable after rotation: %v", err)
+	}
+}
+
+func TestVault_SaveAndOpenFile(t *testing.T) {
+	useFastVaultKDF(t)
+	path := filepath.Join(t.TempDir(), "wallets.vault")
+	if err := newTestVault(t).SaveFile(path, []byte("pw")); err != nil {
+		t.Fatalf("SaveFile error: %v", err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat error: %v", err)
+	}
+	if fi.Mode().Perm() != 0o600 {
+		t.Fatalf("expected 0600 permissions, got %v", fi.Mode().Perm())
+	}
+	v, err := OpenVaultFile(path, []byte("pw"))
+	if err != nil {
+		t.Fatalf("OpenVaultFile error: %v", err)
+	}
+	if len(v.Names()) != 2 {
+		t.Fatalf("unexpected entries %v", v.Names())
+	}
+	if _, err := OpenVaultFile(filepath.Join(t.TempDir(), "missing"), []byte("pw")); err == nil {
+		t.Fatalf("expected error for missing file")
+	}
+	if err := NewVault().SaveFile(filepath.Join(t.TempDir(), "no", "dir"), []byte("pw")); err == nil {
+		t.Fatalf("expected error for missing directory")
+	}
+}
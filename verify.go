@@ -0,0 +1,86 @@
+package tronwallet
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	secpecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+var (
+	// ErrInvalidSignature is returned for signatures that are malformed or do
+	// not recover to a public key.
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrHighS is returned for malleable signatures whose s value is in the
+	// upper half of the curve order.
+	ErrHighS = errors.New("signature s value is not canonical (high-S)")
+	// ErrSignerMismatch is returned when a valid signature was produced by a
+	// different address than expected.
+	ErrSignerMismatch = errors.New("signature does not match address")
+)
+
+// secp256k1HalfOrder is N/2, the largest canonical s value.
+var secp256k1HalfOrder = new(big.Int).Rsh(secp256k1.S256().N, 1)
+
+// RecoverPublicKey returns the public key that produced sig over hash. The
+// recovery byte v may use either the 0/1 or the 27/28 convention. Signatures
+// with a high s value are rejected with ErrHighS.
+func RecoverPublicKey(hash, sig []byte) (*ecdsa.PublicKey, error) {
+	if len(hash) != 32 {
+		return nil, ErrInvalidHash
+	}
+	if len(sig) != SignatureLength {
+		return nil, ErrInvalidSignature
+	}
+	v := sig[64]
+	if v == 0 || v == 1 {
+		v += 27
+	}
+	if v != 27 && v != 28 {
+		return nil, ErrInvalidSignature
+	}
+	if new(big.Int).SetBytes(sig[32:64]).Cmp(secp256k1HalfOrder) > 0 {
+		return nil, ErrHighS
+	}
+
+	compact := make([]byte, SignatureLength)
+	compact[0] = v
+	copy(compact[1:], sig[:64])
+	pub, _, err := secpecdsa.RecoverCompact(compact, hash)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	return pub.ToECDSA(), nil
+}
+
+// RecoverAddress returns the Base58 TRON address of the key that produced
+// sig over hash.
+func RecoverAddress(hash, sig []byte) (string, error) {
+	pub, err := RecoverPublicKey(hash, sig)
+	if err != nil {
+		return "", err
+	}
+	return TronAddressFromPublic(pub), nil
+}
+
+// VerifySignature checks that sig over hash was produced by address, given in
+// Base58 or hex form. It returns ErrSignerMismatch when the signature is
+// valid but belongs to another address.
+func VerifySignature(hash, sig []byte, address string) error {
+	want, err := DecodeAddress(address)
+	if err != nil {
+		return err
+	}
+	got, err := RecoverAddress(hash, sig)
+	if err != nil {
+		return err
+	}
+	raw, _ := DecodeAddress(got)
+	if !bytes.Equal(raw, want) {
+		return ErrSignerMismatch
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package tronwallet
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+func TestRecoverAddress_BothVConventions(t *testing.T) {
+	w, _ := RestoreWallet(testMnemonic)
+	priv, _ := w.Derive(0)
+	hash := sha256.Sum256([]byte("withdrawal #1"))
+	sig, err := SignHash(priv, hash[:])
+	if err != nil {
+		t.Fatalf("SignHash error: %v", err)
+	}
+
+	for _, v := range []byte{sig[64], sig[64] - 27} {
+		s := append([]byte(nil), sig...)
+		s[64] = v
+		addr, err := RecoverAddress(hash[:], s)
+		if err != nil {
+			t.Fatalf("RecoverAddress(v=%d) error: %v", v, err)
+		}
+		if addr != "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH" {
+			t.Fatalf("unexpected address %s", addr)
+		}
+	}
+
+	pub, err := RecoverPublicKey(hash[:], sig)
+	if err != nil || pub.X.Cmp(priv.PublicKey.X) != 0 || pub.Y.Cmp(priv.PublicKey.Y) != 0 {
+		t.Fatalf("recovered public key mismatch (%v)", err)
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	w, _ := RestoreWallet(testMnemonic)
+	priv, _ := w.Derive(0)
+	hash := sha256.Sum256([]byte("approve"))
+	sig, _ := SignHash(priv, hash[:])
+
+	if err := VerifySignature(hash[:], sig, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH"); err != nil {
+		t.Fatalf("VerifySignature error: %v", err)
+	}
+	raw, _ := DecodeAddress("TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH")
+	if err := VerifySignature(hash[:], sig, hex.EncodeToString(raw)); err != nil {
+		t.Fatalf("VerifySignature with hex address error: %v", err)
+	}
+	if err := VerifySignature(hash[:], sig, "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK"); !errors.Is(err, ErrSignerMismatch) {
+		t.Fatalf("expected ErrSignerMismatch, got %v", err)
+	}
+	if err := VerifySignature(hash[:], sig, "nope"); !errors.Is(err, ErrInvalidAddress) {
+		t.Fatalf("expected ErrInvalidAddress, got %v", err)
+	}
+	other := sha256.Sum256([]byte("other"))
+	if err := VerifySignature(other[:], sig, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH"); !errors.Is(err, ErrSignerMismatch) {
+		t.Fatalf("expected ErrSignerMismatch for different hash, got %v", err)
+	}
+}
+
+func TestRecoverPublicKey_RejectsMalformedAndHighS(t *testing.T) {
+	w, _ := RestoreWallet(testMnemonic)
+	priv, _ := w.Derive(0)
+	hash := sha256.Sum256([]byte("malleable"))
+	sig, _ := SignHash(priv, hash[:])
+
+	// (r, N-s) with the flipped recovery bit is mathematically valid but malleable
+	n := secp256k1.S256().N
+	highS := new(big.Int).Sub(n, new(big.Int).SetBytes(sig[32:64]))
+	malleable := append([]byte(nil), sig...)
+	highS.FillBytes(malleable[32:64])
+	malleable[64] = 55 - sig[64] // swap 27 and 28
+	if _, err := RecoverPublicKey(hash[:], malleable); !errors.Is(err, ErrHighS) {
+		t.Fatalf("expected ErrHighS, got %v", err)
+	}
+
+	badV := append([]byte(nil), sig...)
+	badV[64] = 29
+	zeroR := append([]byte(nil), sig...)
+	copy(zeroR[:32], make([]byte, 32))
+	for name, s := range map[string][]byte{"short": sig[:64], "v": badV, "r": zeroR} {
+		if _, err := RecoverPublicKey(hash[:], s); !errors.Is(err, ErrInvalidSignature) {
+			t.Fatalf("%s: expected ErrInvalidSignature, got %v", name, err)
+		}
+	}
+	if _, err := RecoverAddress(hash[:31], sig); !errors.Is(err, ErrInvalidHash) {
+		t.Fatalf("expected ErrInvalidHash, got %v", err)
+	}
+}
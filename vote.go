@@ -0,0 +1,139 @@
+package tronwallet
+
+import "fmt"
+
+// MaxVotes is the largest number of Super Representatives a single
+// VoteWitnessContract may vote for.
+const MaxVotes = 30
+
+// Vote casts Count votes (one per TRX of TRON Power) for the Super
+// Representative candidate at Address.
+type Vote struct {
+	Address string
+	Count   int64
+}
+
+// VoteWitnessContract replaces all of OwnerAddress's votes with Votes.
+type VoteWitnessContract struct {
+	OwnerAddress string
+	Votes        []Vote
+	Support      bool
+}
+
+// NewVoteWitnessTransaction builds an unsigned vote for up to MaxVotes
+// Super Representatives. It replaces any earlier votes of owner.
+func NewVoteWitnessTransaction(opts TransactionOptions, owner string, votes []Vote) (*Transaction, error) {
+	return NewTransaction(opts, &VoteWitnessContract{OwnerAddress: owner, Votes: votes})
+}
+
+// ContractType implements ContractParameter.
+func (c *VoteWitnessContract) ContractType() ContractType { return VoteWitnessContractType }
+
+func (c *VoteWitnessContract) marshalProto() ([]byte, error) {
+	owner, err := contractAddress("owner address", c.OwnerAddress)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Votes) == 0 || len(c.Votes) > MaxVotes {
+		return nil, fmt.Errorf("need between 1 and %d votes, got %d", MaxVotes, len(c.Votes))
+	}
+	var w protoWriter
+	w.bytes(1, owner)
+	seen := map[string]bool{}
+	for i, v := range c.Votes {
+		addr, err := contractAddress(fmt.Sprintf("vote %d address", i), v.Address)
+		if err != nil {
+			return nil, err
+		}
+		if seen[string(addr)] {
+			return nil, fmt.Errorf("duplicate vote for %s", v.Address)
+		}
+		seen[string(addr)] = true
+		if v.Count <= 0 {
+			return nil, fmt.Errorf("vote %d: count must be positive", i)
+		}
+		var vote protoWriter
+		vote.bytes(1, addr)
+		vote.int64(2, v.Count)
+		w.message(2, vote.buf)
+	}
+	w.bool(3, c.Support)
+	return w.buf, nil
+}
+
+func parseVoteWitnessContract(b []byte) (ContractParameter, error) {
+	fields, err := parseProto(b)
+	if err != nil {
+		return nil, err
+	}
+	c := &VoteWitnessContract{}
+	for _, f := range fields {
+		switch f.Num {
+		case 1:
+			c.OwnerAddress, err = f.address()
+		case 2:
+			var v *Vote
+			if v, err = parseVote(f); err == nil {
+				c.Votes = append(c.Votes, *v)
+			}
+		case 3:
+			var support uint64
+			support, err = f.uint64()
+			c.Support = support != 0
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func parseVote(f protoField) (*Vote, error) {
+	b, err := f.bytes()
+	if err != nil {
+		return nil, err
+	}
+	fields, err := parseProto(b)
+	if err != nil {
+		return nil, err
+	}
+	v := &Vote{}
+	for _, f := range fields {
+		switch f.Num {
+		case 1:
+			v.Address, err = f.address()
+		case 2:
+			v.Count, err = f.int64()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// WithdrawBalanceContract claims OwnerAddress's accumulated voting and block
+// rewards. java-tron allows one claim every 24 hours.
+type WithdrawBalanceContract struct {
+	OwnerAddress string
+}
+
+// NewWithdrawBalanceTransaction builds an unsigned reward claim.
+func NewWithdrawBalanceTransaction(opts TransactionOptions, owner string) (*Transaction, error) {
+	return NewTransaction(opts, &WithdrawBalanceContract{OwnerAddress: owner})
+}
+
+// ContractType implements ContractParameter.
+func (c *WithdrawBalanceContract) ContractType() ContractType { return WithdrawBalanceContractType }
+
+func (c *WithdrawBalanceContract) marshalProto() ([]byte, error) {
+	return marshalOwnerOnly(c.OwnerAddress)
+}
+
+func parseWithdrawBalanceContract(b []byte) (ContractParameter, error) {
+	c := &WithdrawBalanceContract{}
+	if err := parseOwnerOnly(b, &c.OwnerAddress); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,95 @@
+package tronwallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewVoteWitnessTransaction(t *testing.T) {
+	sr1 := "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK"
+	raw2, _ := hex.DecodeString("41f16412b9a17ee9408646e2a21e16478f72ed1e95")
+	sr2, _ := EncodeAddress(raw2)
+	votes := []Vote{{sr1, 100}, {sr2, 5}}
+
+	tx, err := NewVoteWitnessTransaction(testTxOptions(), "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", votes)
+	if err != nil {
+		t.Fatalf("NewVoteWitnessTransaction error: %v", err)
+	}
+	want := "0a02abcd2208112233445566778840e0a499ffbc315a860108041281010a30747970652e676f6f676c65617069732e636f6d2f70726f746f636f6c2e566f74655769746e657373436f6e7472616374124d0a1541c8599111f29c1e1e061265b4af93ea1f274ad78a12190a1541b6e708a39781c96bd399c7657780ff9fe9f052a8106412190a1541f16412b9a17ee9408646e2a21e16478f72ed1e9510057080d095ffbc31"
+	if got := hex.EncodeToString(tx.RawData); got != want {
+		t.Fatalf("raw data mismatch:\n got %s\nwant %s", got, want)
+	}
+	raw, err := tx.Raw()
+	if err != nil {
+		t.Fatalf("Raw error: %v", err)
+	}
+	c := raw.Contracts[0].Parameter.(*VoteWitnessContract)
+	if !reflect.DeepEqual(c.Votes, votes) || c.Support {
+		t.Fatalf("unexpected votes %+v", c)
+	}
+
+	// Support is carried through even though java-tron ignores it.
+	tx, _ = NewTransaction(testTxOptions(), &VoteWitnessContract{OwnerAddress: "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", Votes: votes[:1], Support: true})
+	raw, _ = tx.Raw()
+	if !raw.Contracts[0].Parameter.(*VoteWitnessContract).Support {
+		t.Fatalf("support flag lost")
+	}
+}
+
+func TestVoteWitness_Validation(t *testing.T) {
+	owner, sr := "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", "TSeJkUh4Qv67VNFwY8LaAxERygNdy6NQZK"
+	tooMany := make([]Vote, MaxVotes+1)
+	for name, tc := range map[string]struct {
+		owner string
+		votes []Vote
+		want  string
+	}{
+		"no votes":    {owner, nil, "between 1 and"},
+		"too many":    {owner, tooMany, "between 1 and"},
+		"bad owner":   {"nope", []Vote{{sr, 1}}, "owner address"},
+		"bad SR":      {owner, []Vote{{"nope", 1}}, "vote 0 address"},
+		"zero count":  {owner, []Vote{{sr, 0}}, "count must be positive"},
+		"duplicate":   {owner, []Vote{{sr, 1}, {sr, 2}}, "duplicate vote"},
+		"neg count":   {owner, []Vote{{sr, -3}}, "count must be positive"},
+		"hex and b58": {owner, []Vote{{sr, 1}, {"41b6e708a39781c96bd399c7657780ff9fe9f052a8", 1}}, "duplicate vote"},
+	} {
+		_, err := NewVoteWitnessTransaction(testTxOptions(), tc.owner, tc.votes)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: expected error containing %q, got %v", name, tc.want, err)
+		}
+	}
+	if _, err := NewVoteWitnessTransaction(testTxOptions(), owner, []Vote{{"nope", 1}}); !errors.Is(err, ErrInvalidAddress) {
+		t.Fatalf("expected ErrInvalidAddress, got %v", err)
+	}
+
+	for _, b := range [][]byte{{0x0a}, {0x08, 0x01}, {0x10, 0x01}, {0x12, 0x02, 0x08, 0x01}, {0x12, 0x02, 0x12, 0x00}, {0x12, 0x01, 0x0a}, {0x1a, 0x00}} {
+		if _, err := parseVoteWitnessContract(b); err == nil {
+			t.Fatalf("expected error parsing %x", b)
+		}
+	}
+}
+
+func TestNewWithdrawBalanceTransaction(t *testing.T) {
+	owner := "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH"
+	tx, err := NewWithdrawBalanceTransaction(testTxOptions(), owner)
+	if err != nil {
+		t.Fatalf("NewWithdrawBalanceTransaction error: %v", err)
+	}
+	raw, _ := tx.Raw()
+	if c, ok := raw.Contracts[0].Parameter.(*WithdrawBalanceContract); !ok || c.OwnerAddress != owner {
+		t.Fatalf("unexpected contract %+v", raw.Contracts[0].Parameter)
+	}
+	want := "0a02abcd2208112233445566778840e0a499ffbc315a53080d124f0a34747970652e676f6f676c65617069732e636f6d2f70726f746f636f6c2e576974686472617742616c616e6365436f6e747261637412170a1541c8599111f29c1e1e061265b4af93ea1f274ad78a7080d095ffbc31"
+	if got := hex.EncodeToString(tx.RawData); got != want {
+		t.Fatalf("raw data mismatch:\n got %s\nwant %s", got, want)
+	}
+	if _, err := NewWithdrawBalanceTransaction(testTxOptions(), "nope"); !errors.Is(err, ErrInvalidAddress) {
+		t.Fatalf("expected ErrInvalidAddress, got %v", err)
+	}
+	if _, err := parseWithdrawBalanceContract([]byte{0x08, 0x01}); err == nil {
+		t.Fatalf("expected parse error")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/ecdsa"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/tyler-smith/go-bip39"
 )
@@ -55,12 +56,38 @@ func NewWallet(length ...MnemonicLength) (*TronWallet, error) {
 	return &TronWallet{Mnemonic: mn, Seed: seed}, nil
 }
 
+// RestoreOption configures optional checks performed by RestoreWallet.
+type RestoreOption func(*restoreOptions)
+
+type restoreOptions struct {
+	rejectWeak bool
+}
+
+// WithWeakMnemonicCheck makes RestoreWallet reject mnemonics that
+// CheckMnemonic rates as compromised or weak, returning ErrWeakMnemonic.
+func WithWeakMnemonicCheck() RestoreOption {
+	return func(o *restoreOptions) { o.rejectWeak = true }
+}
+
 // RestoreWallet validates a BIP39 mnemonic string and returns the corresponding
 // TronWallet with the derived seed.
-func RestoreWallet(mnemonic string) (*TronWallet, error) {
+func RestoreWallet(mnemonic string, opts ...RestoreOption) (*TronWallet, error) {
 	if !bip39.IsMnemonicValid(mnemonic) {
 		return nil, errors.New("invalid mnemonic")
 	}
+	var o restoreOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.rejectWeak {
+		report, err := CheckMnemonic(mnemonic)
+		if err != nil {
+			return nil, err
+		}
+		if report.Strength <= MnemonicWeak {
+			return nil, fmt.Errorf("%w: %s", ErrWeakMnemonic, strings.Join(report.Issues(), "; "))
+		}
+	}
 	seed := bip39.NewSeed(mnemonic, "")
 	return &TronWallet{Mnemonic: mnemonic, Seed: seed}, nil
 }